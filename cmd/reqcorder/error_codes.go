@@ -1,35 +1,77 @@
 package main
 
 import (
+	"reqcorder/internal/baseline"
+	"reqcorder/internal/capture"
+	"reqcorder/internal/collection"
+	"reqcorder/internal/curl"
 	"reqcorder/internal/diff"
+	"reqcorder/internal/expect"
+	"reqcorder/internal/export"
 	"reqcorder/internal/history"
+	"reqcorder/internal/inbound"
 	"reqcorder/internal/initiator"
+	"reqcorder/internal/mock"
+	"reqcorder/internal/monitor"
+	"reqcorder/internal/proxy"
 	"reqcorder/internal/record"
 	"reqcorder/internal/request"
+	"reqcorder/pkg/jsonschema"
 	"reqcorder/pkg/utils"
 )
 
 var errorCodes = map[error]int{
 	// File IO errors
-	ErrorFailedToReadHomeDirectory:     1,
-	ErrorFailedToOpenLogFile:           1,
-	utils.ErrorFailedToCreateDirectory: 1,
-	record.ErrorFailedToStatPath:       1,
-	record.ErrorFailedToReadDirectory:  1,
-	record.ErrorPathIsNotDirectory:     1,
+	ErrorFailedToReadHomeDirectory:         1,
+	ErrorFailedToOpenLogFile:               1,
+	utils.ErrorFailedToCreateDirectory:     1,
+	record.ErrorFailedToStatPath:           1,
+	record.ErrorFailedToReadDirectory:      1,
+	record.ErrorPathIsNotDirectory:         1,
+	jsonschema.ErrorFailedToLoadSchema:     1,
+	request.ErrorStoredTemplateFileMissing: 1,
+	ErrorFailedToListen:                    1,
+	proxy.ErrorFailedToLoadCA:              1,
+	inbound.ErrorFailedToLoadCertificate:   1,
+	ErrorFailedToWriteTemplate:             1,
+	request.ErrorFailedToReadBodyFile:      1,
 	// Usage errors
-	ErrorInvalidUsage:          2,
-	diff.ErrorInvalidDiffType:  2,
-	ErrorInvalidShowType:       2,
-	ErrorInvalidListType:       2,
-	request.ErrorInvalidURL:    2,
-	request.ErrorInvalidMethod: 2,
+	ErrorInvalidUsage:                2,
+	diff.ErrorInvalidDiffType:        2,
+	ErrorInvalidShowType:             2,
+	ErrorInvalidListType:             2,
+	request.ErrorInvalidURL:          2,
+	request.ErrorInvalidMethod:       2,
+	request.ErrorInvalidFunctionArgs: 2,
+	jsonschema.ErrorInvalidSchema:    2,
+	request.ErrorInvalidIgnorePath:   2,
+	request.ErrorTemplateCycle:       2,
+	request.ErrorConflictingBody:     2,
+	request.ErrorInvalidMultipart:    2,
+	request.ErrorInvalidJSONBody:     2,
+	request.ErrorInvalidVarOverride:  2,
+	request.ErrorEnvironmentNotFound: 2,
+	request.ErrorTemplateValidation:  2,
+	request.ErrorInvalidCapture:      2,
+	request.ErrorInvalidExpectation:  2,
+	mock.ErrorInvalidPick:            2,
+	export.ErrorInvalidFormat:        2,
+	curl.ErrorInvalidCommand:         2,
+	curl.ErrorUnsupportedOption:      2,
 	// Processing data errors
 	utils.ErrorFailedToMarshalJSON:       3,
+	export.ErrorFailedToEncode:           3,
 	history.ErrorFailedToParseTimestamp:  3,
 	utils.ErrorFailedToUnmarshalYAML:     3,
 	request.ErrorFailedToConvertBodyVar:  3,
 	request.ErrorFailedToCreateCookieJar: 3,
+	request.ErrorInvalidEnvironment:      3,
+	request.ErrorInvalidTemplate:         3,
+	request.ErrorInvalidVarsFile:         3,
+	collection.ErrorInvalidCollection:    3,
+	capture.ErrorCaptureFailed:           3,
+	request.ErrorReplayFileChanged:       3,
+	expect.ErrorBodyNotJSON:              3,
 	initiator.ErrorFailedToReadCert:      3,
 	initiator.ErrorFailedToBuildRequest:  3,
 	initiator.ErrorRequestFailed:         3,
@@ -38,6 +80,22 @@ var errorCodes = map[error]int{
 	record.ErrorFailedToGetRequest:  4,
 	record.ErrorFailedToGetTemplate: 4,
 	record.ErrorFailedToGetResponse: 4,
+	record.ErrorFailedToGetRun:      4,
+	record.ErrorBaselineNotFound:    4,
+	record.ErrorFailedToGetMonitor:  4,
+	record.ErrorFailedToGetInbound:  4,
+	mock.ErrorNoRecordedResponses:   4,
 	// Rendering errors
 	diff.ErrorFailedToRenderDiff: 5,
+	// Template placeholder errors
+	request.ErrorUnresolvedPlaceholders: 6,
+	// Run errors
+	collection.ErrorRunFailed: 7,
+	// Assertion errors
+	expect.ErrorAssertionsFailed: 8,
+	expect.ErrorSchemaViolations: 8,
+	// Baseline errors
+	baseline.ErrorBaselineMismatch: 9,
+	// Monitor errors
+	monitor.ErrorThresholdBreached: 10,
 }
@@ -1,33 +1,79 @@
 package main
 
 import (
+	"reqcorder/internal/baseline"
+	"reqcorder/internal/capture"
+	"reqcorder/internal/collection"
 	"reqcorder/internal/diff"
+	"reqcorder/internal/expect"
+	"reqcorder/internal/export"
 	"reqcorder/internal/history"
+	"reqcorder/internal/inbound"
 	"reqcorder/internal/initiator"
+	"reqcorder/internal/mock"
+	"reqcorder/internal/proxy"
 	"reqcorder/internal/record"
 	"reqcorder/internal/request"
+	"reqcorder/pkg/jsonschema"
 	"reqcorder/pkg/utils"
 )
 
 var errorMessages = map[error]string{
-	utils.ErrorFailedToCreateDirectory:   "failed to create directory",
-	utils.ErrorFailedToMarshalJSON:       "failed to format output",
-	utils.ErrorFailedToUnmarshalYAML:     "failed to read file contents",
-	record.ErrorFailedToStatPath:         "failed to read required path",
-	record.ErrorFailedToReadDirectory:    "failed to read required directory",
-	record.ErrorPathIsNotDirectory:       "expected directory",
-	record.ErrorFailedToGetRequest:       "failed to get request details",
-	record.ErrorFailedToGetResponse:      "failed to get response details",
-	record.ErrorFailedToGetTemplate:      "failed to get template details",
-	diff.ErrorInvalidDiffType:            "invalid usage, invalid diff type",
-	diff.ErrorFailedToRenderDiff:         "failed to render diff",
-	history.ErrorFailedToParseTimestamp:  "failed to format timestamp",
-	request.ErrorInvalidURL:              "invalid URL passed",
-	request.ErrorInvalidMethod:           "invalid HTTP method passed",
-	request.ErrorFailedToConvertBodyVar:  "failed to process body var(s)",
-	request.ErrorFailedToCreateCookieJar: "failed to process request cookie(s)",
-	initiator.ErrorFailedToReadCert:      "failed to read certificate path",
-	initiator.ErrorFailedToBuildRequest:  "failed to process request",
-	initiator.ErrorRequestFailed:         "failed to process request",
-	initiator.ErrorFailedToReadResponse:  "failed to read response",
+	utils.ErrorFailedToCreateDirectory:     "failed to create directory",
+	utils.ErrorFailedToMarshalJSON:         "failed to format output",
+	utils.ErrorFailedToUnmarshalYAML:       "failed to read file contents",
+	record.ErrorFailedToStatPath:           "failed to read required path",
+	record.ErrorFailedToReadDirectory:      "failed to read required directory",
+	record.ErrorPathIsNotDirectory:         "expected directory",
+	record.ErrorFailedToGetRequest:         "failed to get request details",
+	record.ErrorFailedToGetResponse:        "failed to get response details",
+	record.ErrorFailedToGetTemplate:        "failed to get template details",
+	diff.ErrorInvalidDiffType:              "invalid usage, invalid diff type",
+	diff.ErrorFailedToRenderDiff:           "failed to render diff",
+	history.ErrorFailedToParseTimestamp:    "failed to format timestamp",
+	request.ErrorInvalidURL:                "invalid URL passed",
+	request.ErrorInvalidMethod:             "invalid HTTP method passed",
+	request.ErrorFailedToConvertBodyVar:    "failed to process body var(s)",
+	request.ErrorFailedToCreateCookieJar:   "failed to process request cookie(s)",
+	request.ErrorEnvironmentNotFound:       "environment not found",
+	request.ErrorInvalidEnvironment:        "failed to read environment file",
+	request.ErrorInvalidFunctionArgs:       "invalid template function usage",
+	request.ErrorInvalidTemplate:           "failed to read template",
+	request.ErrorTemplateCycle:             "template extends chain contains a cycle",
+	request.ErrorConflictingBody:           "only one of body, body_file, json, form, or multipart can be defined",
+	request.ErrorInvalidMultipart:          "invalid multipart body",
+	request.ErrorInvalidJSONBody:           "json body could not be serialized",
+	request.ErrorFailedToReadBodyFile:      "failed to read file referenced by the template",
+	request.ErrorInvalidVarOverride:        "invalid --var value, expected key=value",
+	request.ErrorInvalidVarsFile:           "failed to read vars file",
+	request.ErrorTemplateValidation:        "template validation failed:\n%s",
+	request.ErrorInvalidCapture:            "invalid capture, each capture needs exactly one valid json, header, cookie, status, or regex source",
+	capture.ErrorCaptureFailed:             "failed to capture response values:\n%s",
+	request.ErrorInvalidExpectation:        "invalid expect block in template",
+	expect.ErrorAssertionsFailed:           "one or more response assertions failed",
+	expect.ErrorSchemaViolations:           "response body does not match the JSON Schema",
+	expect.ErrorBodyNotJSON:                "response body is not JSON",
+	jsonschema.ErrorFailedToLoadSchema:     "failed to read JSON Schema file",
+	jsonschema.ErrorInvalidSchema:          "invalid JSON Schema",
+	collection.ErrorInvalidCollection:      "failed to read collection",
+	collection.ErrorRunFailed:              "one or more requests in the collection failed",
+	record.ErrorFailedToGetMonitor:         "failed to get monitor",
+	record.ErrorFailedToGetInbound:         "failed to get inbound request",
+	mock.ErrorNoRecordedResponses:          "no recorded responses to serve, record some with reqcorder exec first",
+	mock.ErrorInvalidPick:                  "invalid usage, --pick must be latest or baseline",
+	export.ErrorInvalidFormat:              "invalid usage, --format must be curl, httpie, go, python-requests, or fetch",
+	export.ErrorFailedToEncode:             "failed to encode request body for export",
+	proxy.ErrorFailedToLoadCA:              "failed to load or create the proxy CA certificate",
+	inbound.ErrorFailedToLoadCertificate:   "failed to load TLS certificate",
+	record.ErrorFailedToGetRun:             "failed to get run",
+	record.ErrorBaselineNotFound:           "no baseline set for this template, pin one with reqcorder baseline set -re <response_id>",
+	request.ErrorStoredTemplateFileMissing: "files referenced by the stored template were not found:\n%s",
+	request.ErrorReplayFileChanged:         "a file sent with the request changed since it was recorded",
+	request.ErrorInvalidIgnorePath:         "invalid ignore path, use status, body, header:<name>, or a JSONPath such as $.id",
+	baseline.ErrorBaselineMismatch:         "response differs from baseline",
+	request.ErrorUnresolvedPlaceholders:    "unresolved placeholders (set strict: false in the template or pass --no-strict to allow them):\n%s",
+	initiator.ErrorFailedToReadCert:        "failed to read certificate path",
+	initiator.ErrorFailedToBuildRequest:    "failed to process request",
+	initiator.ErrorRequestFailed:           "failed to process request",
+	initiator.ErrorFailedToReadResponse:    "failed to read response",
 }
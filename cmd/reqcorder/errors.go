@@ -7,5 +7,7 @@ var (
 	ErrorFailedToReadHomeDirectory = errors.New("failed to read home directory for current user")
 	ErrorInvalidShowType           = errors.New("invalid usage, invalid show type")
 	ErrorInvalidListType           = errors.New("invalid usage, invalid list type")
-	ErrorFailedToOpenLogFile          = errors.New("failed to open log file")
+	ErrorFailedToOpenLogFile       = errors.New("failed to open log file")
+	ErrorFailedToListen            = errors.New("failed to listen")
+	ErrorFailedToWriteTemplate     = errors.New("failed to write template")
 )
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"reqcorder/internal/export"
+	"reqcorder/internal/record"
+	"reqcorder/pkg/utils"
+)
+
+func runExport(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running export command", "args", args, "recordStorePath", recordStorePath)
+	var requestHash, responseID, format string
+	var redact bool
+	exportCommand := flag.NewFlagSet("export", flag.ExitOnError)
+	exportCommand.StringVar(&requestHash, "request", "", "Hash of the stored request to export")
+	exportCommand.StringVar(&requestHash, "rq", "", "Hash of the stored request to export (shorthand)")
+	exportCommand.StringVar(&responseID, "response", "", "Response ID whose request is exported")
+	exportCommand.StringVar(&responseID, "re", "", "Response ID whose request is exported (shorthand)")
+	exportCommand.StringVar(&format, "format", export.FormatCurl, "Snippet format: curl, httpie, go, python-requests, or fetch")
+	exportCommand.StringVar(&format, "f", export.FormatCurl, "Snippet format (shorthand)")
+	exportCommand.BoolVar(&redact, "redact", false, "Mask the auth header and cookie values")
+	exportCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of export:\nreqcorder export (-request|-rq <request_hash> | -response|-re <response_id>) [--format|-f curl|httpie|go|python-requests|fetch] [--redact] [--verbose|-v]")
+		exportCommand.PrintDefaults()
+	}
+	exportCommand.Parse(args)
+	if (requestHash == "") == (responseID == "") || exportCommand.NArg() > 0 {
+		slog.Error("Exactly one of request hash or response ID must be provided")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	if responseID != "" {
+		responseStore := record.RecordStore{
+			RecordStorePath: recordStorePath,
+			ResponseID:      responseID,
+		}
+		err := responseStore.GetResponseByID()
+		if err != nil {
+			slog.Error("Failed to get response by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		requestHash = responseStore.RequestHash
+		slog.Debug("Exporting request of response", "responseID", responseID, "requestHash", requestHash)
+	}
+	recordStore := record.RecordStore{
+		RecordStorePath: recordStorePath,
+		RequestHash:     requestHash,
+	}
+	err := recordStore.GetRequestByHash()
+	if err != nil {
+		slog.Error("Failed to get request by hash", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	snippet, err := export.Render(recordStore.Request, format, redact)
+	if err != nil {
+		slog.Error("Failed to export request", "requestHash", requestHash, "format", format, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	utils.Fprint(outStream, snippet)
+	slog.Debug("Export command completed successfully")
+}
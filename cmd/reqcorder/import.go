@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reqcorder/internal/curl"
+	"reqcorder/pkg/utils"
+)
+
+func runImport(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running import command", "args", args, "recordStorePath", recordStorePath)
+	var output string
+	var execute bool
+	const curlType = "curl"
+	importCommand := flag.NewFlagSet("import", flag.ExitOnError)
+	importCommand.StringVar(&output, "output", "", "Write the template to this file instead of stdout")
+	importCommand.StringVar(&output, "o", "", "Write the template to this file instead of stdout (shorthand)")
+	importCommand.BoolVar(&execute, "exec", false, "Execute the template right away and record the exchange")
+	importCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of import:\nreqcorder import curl [--output|-o <template_path>] [--exec] ['<curl_command>'|-] [--verbose|-v]\nThe curl command is read from stdin when it is omitted or -.")
+		importCommand.PrintDefaults()
+	}
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" {
+			importCommand.Usage()
+			return
+		}
+	}
+	if len(args) < 1 || args[0] != curlType {
+		slog.Error("Invalid import type provided", "args", args)
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	importCommand.Parse(args[1:])
+	if importCommand.NArg() > 1 {
+		slog.Error("Unexpected arguments for import command, the curl command must be quoted", "args", importCommand.Args())
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+
+	commandLine := importCommand.Arg(0)
+	if commandLine == "" || commandLine == "-" {
+		slog.Debug("Reading curl command from stdin")
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			err = fmt.Errorf("%w %q: %v", utils.ErrorFailedToReadFile, "stdin", err)
+			slog.Error("Failed to read curl command", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		commandLine = string(content)
+	}
+	template, err := curl.Parse(commandLine)
+	if err != nil {
+		slog.Error("Failed to parse curl command", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	templateYaml, err := template.Marshal()
+	if err != nil {
+		slog.Error("Failed to convert curl command to template", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+
+	temporary := false
+	if output == "" {
+		utils.Fprint(outStream, string(templateYaml))
+		if !execute {
+			return
+		}
+		file, err := os.CreateTemp("", "reqcorder-import-*.yaml")
+		if err != nil {
+			err = fmt.Errorf("%w: %v", ErrorFailedToWriteTemplate, err)
+			slog.Error("Failed to create temporary template", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		output = file.Name()
+		temporary = true
+		_, err = file.Write(templateYaml)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(output)
+			err = fmt.Errorf("%w %q: %v", ErrorFailedToWriteTemplate, output, err)
+			slog.Error("Failed to write temporary template", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintln(outStream)
+	} else {
+		// Refuse to overwrite, the path may well be a template written by hand.
+		file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = file.Write(templateYaml)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("%w %q: %v", ErrorFailedToWriteTemplate, output, err)
+			slog.Error("Failed to write template", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintf(outStream, "Wrote template to %s\n", output)
+		if !execute {
+			return
+		}
+		utils.Fprintln(outStream)
+	}
+	slog.Debug("Executing imported template", "path", output)
+	config := execConfig{templatePath: output}
+	_, _, err = config.execute(outStream, recordStorePath)
+	// Exiting skips deferred calls, so the temporary template is removed before any error is reported.
+	if temporary {
+		os.Remove(output)
+	}
+	if err != nil {
+		printErrorAndExit(errStream, err)
+	}
+	slog.Debug("Import command completed successfully")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"reqcorder/internal/inbound"
+	"reqcorder/internal/record"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func runListen(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running listen command", "args", args, "recordStorePath", recordStorePath)
+	var host, body, bodyFile, certFile, keyFile string
+	var port int
+	var headers stringListFlag
+	var reply inbound.Reply
+	var useTLS, quiet bool
+	listenCommand := flag.NewFlagSet("listen", flag.ExitOnError)
+	listenCommand.IntVar(&port, "port", 9000, "Port to listen on")
+	listenCommand.StringVar(&host, "host", "127.0.0.1", "Address to listen on")
+	listenCommand.IntVar(&reply.StatusCode, "status", http.StatusOK, "Status code of the reply")
+	listenCommand.Var(&headers, "header", "Reply header as \"Name: value\" (repeatable)")
+	listenCommand.StringVar(&body, "body", "", "Body of the reply")
+	listenCommand.StringVar(&bodyFile, "body-file", "", "File holding the body of the reply")
+	listenCommand.DurationVar(&reply.Delay, "delay", 0, "Wait this long before replying, such as 2s")
+	listenCommand.BoolVar(&useTLS, "tls", false, "Serve HTTPS, with a self-signed certificate unless --cert-file and --key-file are given")
+	listenCommand.StringVar(&certFile, "cert-file", "", "PEM certificate to serve HTTPS with")
+	listenCommand.StringVar(&keyFile, "key-file", "", "PEM private key of the certificate")
+	listenCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	listenCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	listenCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of listen:\nreqcorder listen [--port <port>] [--host <address>] [--status <code>] [--header <header>]... [--body <body>|--body-file <file>] [--delay <duration>] [--tls [--cert-file <file> --key-file <file>]] [--quiet|-q] [--verbose|-v]")
+		listenCommand.PrintDefaults()
+	}
+	listenCommand.Parse(args)
+	if listenCommand.NArg() > 0 || port < 0 || port > 65535 || reply.StatusCode < 100 || reply.StatusCode > 599 || reply.Delay < 0 {
+		slog.Error("Invalid listen settings", "args", listenCommand.Args(), "port", port, "status", reply.StatusCode, "delay", reply.Delay)
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	if (body != "" && bodyFile != "") || (certFile == "") != (keyFile == "") || (certFile != "" && !useTLS) {
+		slog.Error("Conflicting listen flags", "body", body != "", "bodyFile", bodyFile, "certFile", certFile, "keyFile", keyFile, "tls", useTLS)
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	reply.Body = body
+	if bodyFile != "" {
+		content, err := utils.ReadFile(bodyFile)
+		if err != nil {
+			slog.Error("Failed to read reply body file", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		reply.Body = string(content)
+	}
+	reply.Headers = make(map[string]string)
+	for _, header := range headers {
+		name, value, found := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			slog.Error("Invalid reply header", "header", header)
+			printErrorAndExit(errStream, ErrorInvalidUsage)
+		}
+		reply.Headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+
+	tally := &recordingTally{outStream: outStream, quiet: quiet}
+	receiver := &inbound.Receiver{Reply: reply}
+	receiver.OnReceive = func(req *inbound.Request) {
+		status := "recording failed"
+		inboundStore := record.InboundStore{RecordStorePath: recordStorePath}
+		inboundYaml, err := utils.ConvertToYAML(req)
+		if err == nil {
+			inboundStore.InboundYaml = inboundYaml
+			err = inboundStore.Record()
+		}
+		if err != nil {
+			slog.Error("Failed to record inbound request", "path", req.Path, "error", err)
+		} else {
+			status = "recorded " + inboundStore.InboundID
+		}
+		tally.add(err, fmt.Sprintf("%s %s %s %d B from %s %d %s", time.Now().Format(time.TimeOnly), req.Method, req.Target(), req.Size, req.RemoteAddr, req.ReplyStatus, status))
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		err = fmt.Errorf("%w on %s: %v", ErrorFailedToListen, address, err)
+		slog.Error("Failed to listen", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	scheme := "http"
+	if useTLS {
+		hosts := []string{"localhost", "127.0.0.1", "::1"}
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+		cert, err := inbound.LoadCertificate(certFile, keyFile, hosts)
+		if err != nil {
+			listener.Close()
+			slog.Error("Failed to load TLS certificate", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}})
+		scheme = "https"
+	}
+	if !quiet {
+		utils.Fprintf(outStream, "Listening on %s://%s, press Ctrl+C to stop\n", scheme, listener.Addr())
+		if useTLS && certFile == "" {
+			utils.Fprintln(outStream, "The certificate is self-signed, senders must skip its verification")
+		}
+		utils.Fprintln(outStream)
+	}
+	httpServer := &http.Server{Handler: receiver, ReadHeaderTimeout: 10 * time.Second}
+	if err := serveUntilSignal(httpServer, listener, "receiver"); err != nil {
+		printErrorAndExit(errStream, err)
+	}
+	tally.summarize("request")
+}
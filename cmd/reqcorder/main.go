@@ -1,32 +1,48 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"reqcorder/internal/baseline"
+	"reqcorder/internal/capture"
+	"reqcorder/internal/collection"
 	"reqcorder/internal/diff"
+	"reqcorder/internal/expect"
 	"reqcorder/internal/history"
 	"reqcorder/internal/initiator"
 	"reqcorder/internal/record"
 	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/jsonschema"
 	"reqcorder/pkg/render"
 	"reqcorder/pkg/utils"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var VERSION = "rc"
 
 func printErrorAndExit(errStream io.Writer, err error) {
-	utils.PrintError(errStream, formatErrorMessage(err))
+	utils.PrintError(errStream, formatErrorMessage(err, errorDetails(err)...))
+	os.Exit(exitCode(err))
+}
+
+// Return the exit code of an error, or 125 for errors without one.
+func exitCode(err error) int {
 	for key, code := range errorCodes {
 		if errors.Is(err, key) {
-			os.Exit(code)
+			return code
 		}
 	}
-	os.Exit(125)
+	return 125
 }
 
 func formatErrorMessage(err error, args ...any) error {
@@ -42,6 +58,27 @@ func formatErrorMessage(err error, args ...any) error {
 	return err
 }
 
+// Return the values that fill in the error message for errors that carry details.
+func errorDetails(err error) []any {
+	var placeholdersErr *request.UnresolvedPlaceholdersError
+	if errors.As(err, &placeholdersErr) {
+		return []any{strings.TrimSuffix(placeholdersErr.Describe(), "\n")}
+	}
+	var captureErr *capture.CaptureError
+	if errors.As(err, &captureErr) {
+		return []any{strings.TrimSuffix(captureErr.Describe(), "\n")}
+	}
+	var missingFilesErr *request.MissingFilesError
+	if errors.As(err, &missingFilesErr) {
+		return []any{strings.TrimSuffix(missingFilesErr.Describe(), "\n")}
+	}
+	var issuesErr *request.TemplateIssuesError
+	if errors.As(err, &issuesErr) {
+		return []any{strings.TrimSuffix(issuesErr.Describe(), "\n")}
+	}
+	return nil
+}
+
 func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
 	slog.Debug("Running diff command", "args", args, "recordStorePath", recordStorePath)
 	var source, target string
@@ -50,6 +87,7 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 		requestType  = "requests"
 		templateType = "templates"
 		responseType = "responses"
+		inboundType  = "inbound"
 	)
 
 	for _, arg := range args {
@@ -62,7 +100,7 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 			diffCommand.BoolVar(&inline, "inline", false, "Inline diff")
 			diffCommand.BoolVar(&inline, "i", false, "Inline diff (shorthand)")
 			diffCommand.Usage = func() {
-				utils.Fprintln(errStream, "Usage of diff:\nreqcorder diff (templates|requests|responses) -s <source_identifier> -t <target_identifier> [-i|-inline] [--verbose|-v]")
+				utils.Fprintln(errStream, "Usage of diff:\nreqcorder diff (templates|requests|responses|inbound) -s <source_identifier> -t <target_identifier> [-i|-inline] [--verbose|-v]")
 				diffCommand.PrintDefaults()
 			}
 			diffCommand.Usage()
@@ -79,6 +117,7 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 		templateType: true,
 		requestType:  true,
 		responseType: true,
+		inboundType:  true,
 	}
 	if !validDiffTypes[diffType] {
 		slog.Error("Invalid diff type provided", "diffType", diffType)
@@ -92,7 +131,7 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 	diffCommand.BoolVar(&inline, "inline", false, "Inline diff")
 	diffCommand.BoolVar(&inline, "i", false, "Inline diff (shorthand)")
 	diffCommand.Usage = func() {
-		utils.Fprintln(errStream, "Usage of diff:\nreqcorder diff (templates|requests|responses) -s <source_identifier> -t <target_identifier> [-i|-inline] [--verbose|-v]")
+		utils.Fprintln(errStream, "Usage of diff:\nreqcorder diff (templates|requests|responses|inbound) -s <source_identifier> -t <target_identifier> [-i|-inline] [--verbose|-v]")
 		diffCommand.PrintDefaults()
 	}
 	diffCommand.Parse(args[1:])
@@ -151,6 +190,22 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 				printErrorAndExit(errStream, err)
 			}
 		}
+	case inboundType:
+		if inline {
+			slog.Debug("Running inline diff for inbound request", "source", source, "target", target)
+			err := diff.InlineDiff(outStream, recordStorePath, source, target, "inbound")
+			if err != nil {
+				slog.Error("Failed to run inline diff for inbound request", "error", err)
+				printErrorAndExit(errStream, err)
+			}
+		} else {
+			slog.Debug("Running default diff for inbound request", "source", source, "target", target)
+			err := diff.DefaultDiff(outStream, recordStorePath, source, target, "inbound")
+			if err != nil {
+				slog.Error("Failed to run default diff for inbound request", "error", err)
+				printErrorAndExit(errStream, err)
+			}
+		}
 	default:
 		slog.Error("Invalid diff type provided", "diffType", diffType)
 		printErrorAndExit(errStream, diff.ErrorInvalidDiffType)
@@ -160,7 +215,7 @@ func runDiff(outStream io.Writer, errStream io.Writer, args []string, recordStor
 
 func runShow(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
 	slog.Debug("Running show command", "args", args, "recordStorePath", recordStorePath)
-	var request, template, response string
+	var request, template, response, run, monitor, inbound string
 	showCommand := flag.NewFlagSet("show", flag.ExitOnError)
 	showCommand.StringVar(&request, "request", "", "Request hash")
 	showCommand.StringVar(&request, "rq", "", "Request hash (shorthand)")
@@ -168,8 +223,14 @@ func runShow(outStream io.Writer, errStream io.Writer, args []string, recordStor
 	showCommand.StringVar(&template, "tp", "", "Template hash (shorthand)")
 	showCommand.StringVar(&response, "response", "", "Response ID")
 	showCommand.StringVar(&response, "re", "", "Response ID (shorthand)")
+	showCommand.StringVar(&run, "run", "", "Run ID")
+	showCommand.StringVar(&run, "rn", "", "Run ID (shorthand)")
+	showCommand.StringVar(&monitor, "monitor", "", "Monitor ID")
+	showCommand.StringVar(&monitor, "mo", "", "Monitor ID (shorthand)")
+	showCommand.StringVar(&inbound, "inbound", "", "Inbound request ID")
+	showCommand.StringVar(&inbound, "in", "", "Inbound request ID (shorthand)")
 	showCommand.Usage = func() {
-		utils.Fprintln(errStream, "Usage of show:\nreqcorder show (-template|-tp|-request|-rq|-response|-re) <value> [--verbose|-v]")
+		utils.Fprintln(errStream, "Usage of show:\nreqcorder show (-template|-tp|-request|-rq|-response|-re|-run|-rn|-monitor|-mo|-inbound|-in) <value> [--verbose|-v]")
 		showCommand.PrintDefaults()
 	}
 	showCommand.Parse(args)
@@ -200,6 +261,30 @@ func runShow(outStream io.Writer, errStream io.Writer, args []string, recordStor
 			printErrorAndExit(errStream, err)
 		}
 		utils.Fprint(outStream, content)
+	} else if run != "" {
+		slog.Debug("Showing run by ID", "runID", run)
+		content, err := historyStore.GetRunByID(run)
+		if err != nil {
+			slog.Error("Failed to get run by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprint(outStream, content)
+	} else if monitor != "" {
+		slog.Debug("Showing monitor by ID", "monitorID", monitor)
+		content, err := historyStore.GetMonitorByID(monitor)
+		if err != nil {
+			slog.Error("Failed to get monitor by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprint(outStream, content)
+	} else if inbound != "" {
+		slog.Debug("Showing inbound request by ID", "inboundID", inbound)
+		content, err := historyStore.GetInboundByID(inbound)
+		if err != nil {
+			slog.Error("Failed to get inbound request by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprint(outStream, content)
 	} else {
 		slog.Error("Invalid show type - no valid parameter provided")
 		printErrorAndExit(errStream, ErrorInvalidShowType)
@@ -207,127 +292,891 @@ func runShow(outStream io.Writer, errStream io.Writer, args []string, recordStor
 	slog.Debug("Show command completed successfully")
 }
 
+func runRun(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running run command", "args", args, "recordStorePath", recordStorePath)
+	var quiet bool
+	var options requestOptions
+	runCommand := flag.NewFlagSet("run", flag.ExitOnError)
+	runCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	runCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	options.register(runCommand)
+	runCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of run:\nreqcorder run [--quiet|-q] [--env|-e <name>] [--vars-file <file>] [--var key=value]... [--no-strict] <collection_path> [--verbose|-v]")
+		runCommand.PrintDefaults()
+	}
+	runCommand.Parse(args)
+	if runCommand.NArg() < 1 {
+		slog.Error("No collection path provided for run command")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	collectionPath := runCommand.Args()[0]
+	c, collectionYaml, err := collection.LoadCollection(collectionPath)
+	if err != nil {
+		slog.Error("Failed to load collection", "collectionPath", collectionPath, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	err = checkCollection(c)
+	if err != nil {
+		slog.Error("Template check failed", "collectionPath", collectionPath, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	run := collection.RunObject{
+		Collection:     c.Name,
+		CollectionPath: collectionPath,
+		CollectionHash: utils.CalculateMD5Hash(collectionYaml),
+		Environment:    options.environment,
+		StartedAt:      time.Now().UTC(),
+	}
+	if !quiet {
+		utils.Fprintf(outStream, "Running collection %s (%d requests)\n\n", c.Name, len(c.Requests))
+	}
+	captured := make(map[string]string)
+	for i, item := range c.Requests {
+		if !quiet {
+			utils.Fprintf(outStream, "[%d/%d] %s... ", i+1, len(c.Requests), item.Name)
+		}
+		result := runCollectionItem(context.Background(), c, item, options, captured, recordStorePath)
+		run.Results = append(run.Results, result)
+		for name, value := range result.Captured {
+			captured[name] = value
+		}
+		if !quiet {
+			if result.Error != "" {
+				utils.Fprintf(outStream, "failed ❌ (%s)\n", result.Error)
+			} else if result.Passed {
+				utils.Fprintf(outStream, "%d ✅ (%s)\n", result.StatusCode, result.Duration)
+			} else {
+				utils.Fprintf(outStream, "%d ❌ (%s)\n", result.StatusCode, result.Duration)
+			}
+			for _, assertion := range result.FailedAssertions {
+				utils.Fprintf(outStream, "    ❌ %s: expected %s, received %s\n", assertion.Check, utils.CreatePreview(assertion.Expected), utils.CreatePreview(assertion.Actual))
+			}
+			for _, violation := range result.SchemaViolations {
+				utils.Fprintf(outStream, "      %s: %s\n", pointerOrRoot(violation.Path), violation.Message)
+			}
+		}
+	}
+	run.Duration = time.Since(run.StartedAt)
+	run.Tally()
+	runYaml, err := utils.ConvertToYAML(run)
+	if err != nil {
+		slog.Error("Failed to convert run to YAML", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	runStore := record.RunStore{
+		RecordStorePath: recordStorePath,
+		CollectionYaml:  collectionYaml,
+		RunYaml:         runYaml,
+	}
+	err = runStore.Record()
+	if err != nil {
+		slog.Error("Failed to record collection run", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	if !quiet {
+		var data [][]string
+		for i, result := range run.Results {
+			status := "-"
+			if result.StatusCode != 0 {
+				status = strconv.Itoa(result.StatusCode)
+			}
+			if result.Passed {
+				status += " ✅"
+			} else {
+				status += " ❌"
+			}
+			responseID := result.ResponseID
+			if responseID == "" {
+				responseID = "-"
+			}
+			data = append(data, []string{strconv.Itoa(i + 1), result.Name, result.Method, status, result.Duration.String(), responseID})
+		}
+		utils.Fprintf(outStream, "\nRun Summary (%d passed, %d failed, %s)\n", run.Passed, run.Failed, run.Duration)
+		render.RenderTable(outStream, []string{"#", "Name", "Method", "Status", "Time", "Response ID"}, data...)
+		utils.Fprintf(outStream, "\nRun ID - %s\nCollection hash - %s\n", runStore.RunID, runStore.CollectionHash)
+	}
+	if run.Failed > 0 {
+		printErrorAndExit(errStream, collection.ErrorRunFailed)
+	}
+	slog.Debug("Run command completed successfully", "runID", runStore.RunID)
+}
+
+// Build, execute and record a single collection request. Failures are reported in the result rather than aborting the run.
+// Values captured by earlier requests are available to the request as variables.
+func runCollectionItem(ctx context.Context, c *collection.CollectionObject, item collection.CollectionItem, options requestOptions, captured map[string]string, recordStorePath string) collection.RunResult {
+	result := collection.RunResult{Name: item.Name}
+	fail := func(err error) collection.RunResult {
+		slog.Error("Collection request failed", "name", item.Name, "error", err)
+		result.Error = formatErrorMessage(err, errorDetails(err)...).Error()
+		return result
+	}
+	req, templateYaml, err := c.BuildRequest(item)
+	if err != nil {
+		return fail(err)
+	}
+	templatePath := c.Path
+	if item.Template != "" {
+		templatePath = c.ResolveTemplatePath(item)
+	}
+	err = options.apply(req, templatePath)
+	if err != nil {
+		return fail(err)
+	}
+	req.ApplyCaptured(captured)
+	err = req.Validate()
+	if err != nil {
+		return fail(err)
+	}
+	result.Method = req.Method
+	result.URL, _ = req.FullURL()
+	res, requestErr := initiator.InitiateRequestWithContext(ctx, req)
+	// A request aborted by cancelling the context is not recorded.
+	if res == nil || (requestErr != nil && ctx.Err() != nil) {
+		return fail(requestErr)
+	}
+	var captureErr error
+	if requestErr == nil {
+		res.Captured, captureErr = capture.Extract(req.Capture, res)
+		result.Captured = res.Captured
+		res.Assertions, res.SchemaViolations = expect.Evaluate(req.Expect, res)
+		result.AssertionCount = len(res.Assertions)
+		result.SchemaViolations = res.SchemaViolations
+		for _, assertion := range res.Assertions {
+			if !assertion.Passed {
+				result.FailedAssertions = append(result.FailedAssertions, assertion)
+			}
+		}
+	}
+	recordStore := record.RecordStore{
+		RecordStorePath: recordStorePath,
+		TemplateYaml:    templateYaml,
+		Request:         req,
+		Response:        res,
+	}
+	err = recordStore.Record()
+	if err != nil {
+		return fail(err)
+	}
+	result.TemplateHash = recordStore.TemplateHash
+	result.RequestHash = recordStore.RequestHash
+	result.ResponseID = recordStore.ResponseID
+	result.StatusCode = res.StatusCode
+	result.Duration = res.Timing.Total
+	if requestErr != nil {
+		return fail(requestErr)
+	}
+	if captureErr != nil {
+		return fail(captureErr)
+	}
+	// An expected status replaces the default check that the status is below 400.
+	statusPassed := res.StatusCode < 400
+	if req.Expect != nil && len(req.Expect.Status) > 0 {
+		statusPassed = true
+	}
+	result.Passed = statusPassed && len(result.FailedAssertions) == 0
+	slog.Debug("Collection request completed", slog.Any("result", result))
+	return result
+}
+
+func runValidate(outStream io.Writer, errStream io.Writer, args []string) {
+	slog.Debug("Running validate command", "args", args)
+	var schema bool
+	validateCommand := flag.NewFlagSet("validate", flag.ExitOnError)
+	validateCommand.BoolVar(&schema, "schema", false, "Print the JSON Schema of the template format")
+	validateCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of validate:\nreqcorder validate (--schema | <template_path>...) [--verbose|-v]")
+		validateCommand.PrintDefaults()
+	}
+	validateCommand.Parse(args)
+	if schema {
+		slog.Debug("Printing template JSON Schema")
+		content, err := request.TemplateJSONSchema()
+		if err != nil {
+			slog.Error("Failed to build template JSON Schema", "error", err)
+			printErrorAndExit(errStream, fmt.Errorf("%w: %v", utils.ErrorFailedToMarshalJSON, err))
+		}
+		utils.Fprintln(outStream, string(content))
+		return
+	}
+	if validateCommand.NArg() < 1 {
+		slog.Error("No template path provided for validate command")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	err := checkTemplates(validateCommand.Args()...)
+	if err != nil {
+		slog.Error("Template validation failed", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	for _, templatePath := range validateCommand.Args() {
+		utils.Fprintf(outStream, "%s: valid ✅\n", templatePath)
+	}
+}
+
+// Check templates against the template schema, returning every issue found across them as one error.
+func checkTemplates(templatePaths ...string) error {
+	var issues []request.TemplateIssue
+	for _, templatePath := range templatePaths {
+		templateIssues, err := request.CheckTemplate(templatePath)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, templateIssues...)
+	}
+	if len(issues) > 0 {
+		return &request.TemplateIssuesError{Issues: issues}
+	}
+	return nil
+}
+
+// Check the defaults and requests of a collection against the template schema, returning every issue found as one error.
+func checkCollection(c *collection.CollectionObject) error {
+	issues, err := c.Check()
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &request.TemplateIssuesError{Issues: issues}
+	}
+	return nil
+}
+
+// stringListFlag collects the values of a flag that may be repeated.
+type stringListFlag []string
+
+func (s *stringListFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// requestOptions holds the flags shared by commands that execute templates.
+type requestOptions struct {
+	environment string
+	varsFile    string
+	vars        stringListFlag
+	noStrict    bool
+}
+
+// Register the shared request flags on a command.
+func (o *requestOptions) register(command *flag.FlagSet) {
+	command.StringVar(&o.environment, "env", "", "Environment name or file whose variables are merged into the template")
+	command.StringVar(&o.environment, "e", "", "Environment name or file whose variables are merged into the template (shorthand)")
+	command.Var(&o.vars, "var", "Variable override as key=value, layered over body_vars (repeatable)")
+	command.StringVar(&o.varsFile, "vars-file", "", "YAML file of variable overrides, layered over body_vars")
+	command.BoolVar(&o.noStrict, "no-strict", false, "Allow unresolved or empty placeholders")
+}
+
+// Apply the environment, variable overrides and strict mode to a loaded template.
+func (o *requestOptions) apply(req *request.RequestObject, templatePath string) error {
+	if o.environment != "" {
+		slog.Debug("Loading environment", "environment", o.environment)
+		env, err := request.LoadEnvironment(o.environment, templatePath)
+		if err != nil {
+			slog.Error("Failed to load environment", "environment", o.environment, "error", err)
+			return err
+		}
+		req.ApplyEnvironment(env)
+	}
+	overrides, err := loadVarOverrides(o.varsFile, o.vars)
+	if err != nil {
+		slog.Error("Failed to load variable overrides", "error", err)
+		return err
+	}
+	req.ApplyOverrides(overrides)
+	if o.noStrict {
+		strict := false
+		req.Strict = &strict
+	}
+	return nil
+}
+
+// Combine the vars file and --var flags into one override map. --var values win over the file.
+func loadVarOverrides(varsFile string, vars []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	if varsFile != "" {
+		fileVars, err := request.LoadVarsFile(varsFile)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range fileVars {
+			overrides[key] = value
+		}
+	}
+	flagVars, err := request.ParseVarOverrides(vars)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range flagVars {
+		overrides[key] = value
+	}
+	return overrides, nil
+}
+
+// execConfig holds the exec flags that shape how a template is executed.
+type execConfig struct {
+	templatePath  string
+	templateHash  string
+	options       requestOptions
+	minimal       bool
+	quiet         bool
+	checkBaseline bool
+	ignore        []string
+	noRecord      bool
+}
+
 func runExec(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
 	slog.Debug("Running exec command", "args", args, "recordStorePath", recordStorePath)
-	var minimal, quiet bool
+	var config execConfig
+	var ignore stringListFlag
+	var watchMode bool
 	execCommand := flag.NewFlagSet("exec", flag.ExitOnError)
-	execCommand.BoolVar(&minimal, "min", false, "Only show response body and recording info on stdout")
-	execCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
-	execCommand.BoolVar(&minimal, "m", false, "Only show response body and recording info on stdout (shorthand)")
-	execCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	execCommand.StringVar(&config.templateHash, "template", "", "Hash of a stored template to execute instead of a template file")
+	execCommand.StringVar(&config.templateHash, "tp", "", "Hash of a stored template to execute instead of a template file (shorthand)")
+	execCommand.BoolVar(&config.minimal, "min", false, "Only show response body and recording info on stdout")
+	execCommand.BoolVar(&config.quiet, "quiet", false, "No output on stdout")
+	execCommand.BoolVar(&config.minimal, "m", false, "Only show response body and recording info on stdout (shorthand)")
+	execCommand.BoolVar(&config.quiet, "q", false, "No output on stdout (shorthand)")
+	execCommand.BoolVar(&config.checkBaseline, "check-baseline", false, "Compare the response with the baseline of the template and fail on differences")
+	execCommand.Var(&ignore, "ignore", "Location left out of baseline and watch comparisons: status, body, header:<name>, or a JSONPath (repeatable)")
+	execCommand.BoolVar(&watchMode, "watch", false, "Execute again whenever the template or a file it references changes")
+	execCommand.BoolVar(&watchMode, "w", false, "Execute again whenever the template or a file it references changes (shorthand)")
+	execCommand.BoolVar(&config.noRecord, "no-record", false, "Keep watch mode responses in memory instead of recording them")
+	config.options.register(execCommand)
 	execCommand.Usage = func() {
-		utils.Fprintln(errStream, "Usage of exec:\nreqcorder exec [--min|-m|--quiet|-q] <template_path> [--verbose|-v]")
+		utils.Fprintln(errStream, "Usage of exec:\nreqcorder exec [--min|-m|--quiet|-q] [--env|-e <name>] [--vars-file <file>] [--var key=value]... [--no-strict] [--check-baseline] [--ignore <path>]... [--watch|-w [--no-record]] (<template_path> | -template|-tp <template_hash>) [--verbose|-v]")
 		execCommand.PrintDefaults()
 	}
 	execCommand.Parse(args)
-	if execCommand.NArg() < 1 {
-		slog.Error("No template path provided for exec command")
+	if (execCommand.NArg() < 1) == (config.templateHash == "") {
+		slog.Error("Exec needs either a template path or a template hash", "argCount", execCommand.NArg(), "templateHash", config.templateHash)
 		printErrorAndExit(errStream, ErrorInvalidUsage)
 	}
-	templatePath := execCommand.Args()[0]
-	slog.Debug("Reading template file", "templatePath", templatePath)
-	var req request.RequestObject
-	err := utils.ReadYAMLFile(templatePath, &req)
+	if config.noRecord && !watchMode {
+		slog.Error("The no-record flag is only supported in watch mode")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	for _, path := range ignore {
+		if err := request.ValidateIgnorePath(path); err != nil {
+			slog.Error("Invalid ignore path", "path", path, "error", err)
+			printErrorAndExit(errStream, err)
+		}
+	}
+	config.ignore = ignore
+	if config.templateHash == "" {
+		config.templatePath = execCommand.Args()[0]
+	}
+	if watchMode {
+		runExecWatch(outStream, errStream, config, recordStorePath)
+		return
+	}
+	_, _, err := config.execute(outStream, recordStorePath)
 	if err != nil {
-		slog.Error("Failed to read template file", "templatePath", templatePath, "error", err)
 		printErrorAndExit(errStream, err)
 	}
-	templateYaml, err := utils.ReadFile(templatePath)
+	slog.Debug("Exec command completed successfully")
+}
+
+// Load, send, check, and record one execution of the template. The request is nil when the template could not be loaded,
+// and the response is nil when no response was received. The error is the one exec exits with.
+func (c *execConfig) execute(outStream io.Writer, recordStorePath string) (*request.RequestObject, *response.ResponseObject, error) {
+	var req *request.RequestObject
+	var templateYaml []byte
+	var err error
+	if c.templateHash != "" {
+		req, templateYaml, err = loadStoredTemplate(recordStorePath, c.templateHash)
+		if err != nil {
+			slog.Error("Failed to load stored template", "templateHash", c.templateHash, "error", err)
+			return nil, nil, err
+		}
+	} else {
+		slog.Debug("Checking template file", "templatePath", c.templatePath)
+		err = checkTemplates(c.templatePath)
+		if err != nil {
+			slog.Error("Template check failed", "templatePath", c.templatePath, "error", err)
+			return nil, nil, err
+		}
+		slog.Debug("Reading template file", "templatePath", c.templatePath)
+		req, templateYaml, err = request.LoadTemplate(c.templatePath)
+		if err != nil {
+			slog.Error("Failed to load template", "templatePath", c.templatePath, "error", err)
+			return nil, nil, err
+		}
+	}
+	err = c.options.apply(req, c.templatePath)
 	if err != nil {
-		slog.Error("Failed to read template YAML", "templatePath", templatePath, "error", err)
-		printErrorAndExit(errStream, err)
+		slog.Error("Failed to apply request options", "error", err)
+		return req, nil, err
 	}
 	slog.Debug("Validating request object")
 	err = req.Validate()
 	if err != nil {
 		slog.Error("Failed to validate request object", "error", err)
-		printErrorAndExit(errStream, err)
+		return req, nil, err
+	}
+	var baselineResponse *response.ResponseObject
+	var baselineID string
+	if c.checkBaseline {
+		baselineResponse, baselineID, err = loadBaselineResponse(recordStorePath, request.TemplateHash(templateYaml, req))
+		if err != nil {
+			slog.Error("Failed to load baseline", "templatePath", c.templatePath, "error", err)
+			return req, nil, err
+		}
 	}
 	recordStore := record.RecordStore{
 		RecordStorePath: recordStorePath,
 		TemplateYaml:    templateYaml,
-		Request:         &req,
+		Request:         req,
 	}
-	if !quiet && !minimal {
-		utils.Fprintln(outStream, "Request Table:")
-		var reqData [][]string
-		reqData = append(reqData, []string{"URL", req.URL})
-		reqData = append(reqData, []string{"Method", req.Method})
-		reqData = append(reqData, []string{"Body preview", utils.CreatePreview(req.Body)})
-		reqData = append(reqData, []string{"Authorization header", req.Auth})
-		reqData = append(reqData, []string{"Timeout", req.Timeout.String()})
-		render.RenderTable(outStream, []string{"Property", "Value"}, reqData...)
+	if !c.quiet && !c.minimal {
+		renderRequestTable(outStream, req)
 		utils.Fprint(outStream, "Performing request... ")
 	}
 	slog.Debug("Initiating HTTP request", "url", req.URL, "method", req.Method)
-	res, err := initiator.InitiateRequest(&req)
+	res, err := initiator.InitiateRequest(req)
 	if err != nil {
 		slog.Error("Failed to initiate HTTP request", "error", err)
+		if c.noRecord {
+			return req, nil, err
+		}
 		recordStore.Response = res
 		record_err := recordStore.Record()
 		if record_err != nil {
 			slog.Error("Failed to record request-response cycle", "error", record_err)
 		}
-		if !quiet {
+		if !c.quiet {
 			utils.Fprintf(outStream, "\nResponse ID - %s\nRequest hash - %s\nTemplate hash - %s\n\n", recordStore.ResponseID, recordStore.RequestHash, recordStore.TemplateHash)
 		}
-		printErrorAndExit(errStream, err)
+		return req, nil, err
 	}
-	if !quiet && !minimal {
-		utils.Fprintf(outStream, "Request complete (Time taken %s)\n\n", res.Timing.Total.String())
-		statusStr := strconv.Itoa(int(res.StatusCode))
-		if res.StatusCode < 400 {
-			statusStr += " ✅"
-		} else {
-			statusStr += " ❌"
-		}
-		var resData [][]string
-		utils.Fprintln(outStream, "Response Table:")
-		resData = append(resData, []string{"HTTP Status Code", statusStr})
-		resData = append(resData, []string{"Body preview", utils.CreatePreview(res.Body)})
-		resData = append(resData, []string{"Size (Bytes)", strconv.Itoa(int(res.Size))})
-		resData = append(resData, []string{"DNS lookup time", res.Timing.DNSLookup.String()})
-		resData = append(resData, []string{"TCP connection time", res.Timing.TCPConnect.String()})
-		resData = append(resData, []string{"TLS handshake time", res.Timing.TLSHandshake.String()})
-		resData = append(resData, []string{"Time to first byte", res.Timing.FirstByte.String()})
-		resData = append(resData, []string{"Total time taken ⏳", res.Timing.Total.String()})
-		importantHeaders := []string{
-			"Content-Type", "Location", "Cache-Control",
-			"X-Ratelimit-Remaining", "X-Ratelimit-Limit",
-			"X-Ratelimit-Reset",
-		}
-
-		for _, headerName := range importantHeaders {
-			if value := res.Headers[headerName]; value != "" {
-				resData = append(resData, []string{headerName + " header", value})
-			}
+	var captureErr error
+	res.Captured, captureErr = capture.Extract(req.Capture, res)
+	res.Assertions, res.SchemaViolations = expect.Evaluate(req.Expect, res)
+	if baselineResponse != nil {
+		res.Baseline = &response.BaselineComparison{
+			ResponseID:  baselineID,
+			Differences: baseline.Compare(baselineResponse, res, c.ignorePaths(req)),
 		}
-		render.RenderTable(outStream, []string{"Property", "Value"}, resData...)
-		utils.Fprintln(outStream, "Response Body:")
 	}
-	if !quiet {
+	if !c.quiet && !c.minimal {
+		renderResponseTable(outStream, res)
+	}
+	if !c.quiet {
 		body, err := utils.Prettify(res.Body)
 		if err != nil {
 			slog.Error("Failed to prettify response body", "error", err)
-			printErrorAndExit(errStream, err)
+			return req, res, err
 		}
 		utils.Fprintln(outStream, body)
 		utils.Fprint(outStream, "\n")
 	}
+	if !c.quiet && !c.minimal && len(res.Assertions) > 0 {
+		renderAssertions(outStream, res.Assertions)
+	}
+	if !c.quiet && !c.minimal && len(res.SchemaViolations) > 0 {
+		renderSchemaViolations(outStream, res.SchemaViolations)
+	}
+	if !c.quiet && !c.minimal && res.Baseline != nil {
+		renderBaselineComparison(outStream, res.Baseline)
+	}
+	if c.noRecord {
+		if !c.quiet {
+			utils.Fprint(outStream, "Response kept in memory, not recorded\n\n")
+		}
+	} else {
+		if !c.quiet && !c.minimal {
+			utils.Fprint(outStream, "Recording to store... ")
+		}
+		slog.Debug("Recording request-response cycle")
+		recordStore.Response = res
+		err = recordStore.Record()
+		if err != nil {
+			slog.Error("Failed to record request-response cycle", "error", err)
+			return req, res, err
+		}
+		if !c.quiet {
+			utils.Fprintf(outStream, "Done \nResponse ID - %s\nRequest hash - %s\nTemplate hash - %s\n\n", recordStore.ResponseID, recordStore.RequestHash, recordStore.TemplateHash)
+		}
+	}
+	if captureErr != nil {
+		slog.Error("Failed to capture response values", "error", captureErr)
+		return req, res, captureErr
+	}
+	if failed := expect.Failed(res.Assertions); failed > 0 {
+		slog.Error("Response assertions failed", "failedCount", failed, "assertionCount", len(res.Assertions))
+		return req, res, expect.ErrorAssertionsFailed
+	}
+	if res.Baseline != nil && len(res.Baseline.Differences) > 0 {
+		slog.Error("Response differs from baseline", "baselineResponseID", res.Baseline.ResponseID, "differenceCount", len(res.Baseline.Differences))
+		return req, res, baseline.ErrorBaselineMismatch
+	}
+	slog.Debug("Template executed", "responseID", recordStore.ResponseID)
+	return req, res, nil
+}
+
+// Combine the ignore paths of the template with the ones passed on the command line.
+func (c *execConfig) ignorePaths(req *request.RequestObject) []string {
+	var ignorePaths []string
+	if req != nil && req.Baseline != nil {
+		ignorePaths = append(ignorePaths, req.Baseline.Ignore...)
+	}
+	return append(ignorePaths, c.ignore...)
+}
+
+// Print the URL, method, and other resolved settings of a request.
+func renderRequestTable(outStream io.Writer, req *request.RequestObject) {
+	utils.Fprintln(outStream, "Request Table:")
+	var reqData [][]string
+	fullURL, _ := req.FullURL()
+	reqData = append(reqData, []string{"URL", fullURL})
+	reqData = append(reqData, []string{"Method", req.Method})
+	if req.Environment != "" {
+		reqData = append(reqData, []string{"Environment", req.Environment})
+	}
+	if len(req.Overrides) > 0 {
+		reqData = append(reqData, []string{"Overrides", req.DescribeOverrides()})
+	}
+	reqData = append(reqData, []string{"Body preview", utils.CreatePreview(req.DescribeBody())})
+	reqData = append(reqData, []string{"Authorization header", req.Auth})
+	reqData = append(reqData, []string{"Timeout", req.Timeout.String()})
+	render.RenderTable(outStream, []string{"Property", "Value"}, reqData...)
+}
+
+func runReplay(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running replay command", "args", args, "recordStorePath", recordStorePath)
+	var requestHash, responseID string
+	var minimal, quiet bool
+	replayCommand := flag.NewFlagSet("replay", flag.ExitOnError)
+	replayCommand.StringVar(&requestHash, "request", "", "Hash of the stored request to send again")
+	replayCommand.StringVar(&requestHash, "rq", "", "Hash of the stored request to send again (shorthand)")
+	replayCommand.StringVar(&responseID, "response", "", "Response ID whose request is sent again")
+	replayCommand.StringVar(&responseID, "re", "", "Response ID whose request is sent again (shorthand)")
+	replayCommand.BoolVar(&minimal, "min", false, "Only show response body and recording info on stdout")
+	replayCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	replayCommand.BoolVar(&minimal, "m", false, "Only show response body and recording info on stdout (shorthand)")
+	replayCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	replayCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of replay:\nreqcorder replay (-request|-rq <request_hash> | -response|-re <response_id>) [--min|-m|--quiet|-q] [--verbose|-v]")
+		replayCommand.PrintDefaults()
+	}
+	replayCommand.Parse(args)
+	if (requestHash == "") == (responseID == "") {
+		slog.Error("Exactly one of request hash or response ID must be provided")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	if responseID != "" {
+		responseStore := record.RecordStore{
+			RecordStorePath: recordStorePath,
+			ResponseID:      responseID,
+		}
+		err := responseStore.GetResponseByID()
+		if err != nil {
+			slog.Error("Failed to get response by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		requestHash = responseStore.RequestHash
+		slog.Debug("Replaying request of response", "responseID", responseID, "requestHash", requestHash)
+	}
+	recordStore := record.RecordStore{
+		RecordStorePath: recordStorePath,
+		RequestHash:     requestHash,
+	}
+	err := recordStore.GetRequestByHash()
+	if err != nil {
+		slog.Error("Failed to get request by hash", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	req := recordStore.Request
+	err = req.PrepareReplay()
+	if err != nil {
+		slog.Error("Failed to prepare stored request", "requestHash", requestHash, "error", err)
+		printErrorAndExit(errStream, err)
+	}
 	if !quiet && !minimal {
-		utils.Fprint(outStream, "Recording to store... ")
+		renderRequestTable(outStream, req)
+		utils.Fprint(outStream, "Replaying request... ")
+	}
+	res, requestErr := initiator.InitiateRequest(req)
+	if res == nil {
+		slog.Error("Failed to replay request", "error", requestErr)
+		printErrorAndExit(errStream, requestErr)
+	}
+	if requestErr == nil {
+		res.Assertions, res.SchemaViolations = expect.Evaluate(req.Expect, res)
+		if !quiet && !minimal {
+			renderResponseTable(outStream, res)
+		}
+		if !quiet {
+			body, err := utils.Prettify(res.Body)
+			if err != nil {
+				slog.Error("Failed to prettify response body", "error", err)
+				printErrorAndExit(errStream, err)
+			}
+			utils.Fprintln(outStream, body)
+			utils.Fprint(outStream, "\n")
+		}
+		if !quiet && !minimal && len(res.Assertions) > 0 {
+			renderAssertions(outStream, res.Assertions)
+		}
+		if !quiet && !minimal && len(res.SchemaViolations) > 0 {
+			renderSchemaViolations(outStream, res.SchemaViolations)
+		}
 	}
-	slog.Debug("Recording request-response cycle")
 	recordStore.Response = res
-	err = recordStore.Record()
+	err = recordStore.RecordResponse()
 	if err != nil {
-		slog.Error("Failed to record request-response cycle", "error", err)
+		slog.Error("Failed to record replayed response", "error", err)
 		printErrorAndExit(errStream, err)
 	}
 	if !quiet {
 		utils.Fprintf(outStream, "Done \nResponse ID - %s\nRequest hash - %s\nTemplate hash - %s\n\n", recordStore.ResponseID, recordStore.RequestHash, recordStore.TemplateHash)
 	}
-	slog.Debug("Exec command completed successfully", "responseID", recordStore.ResponseID)
+	if requestErr != nil {
+		slog.Error("Failed to replay request", "error", requestErr)
+		printErrorAndExit(errStream, requestErr)
+	}
+	if failed := expect.Failed(res.Assertions); failed > 0 {
+		slog.Error("Response assertions failed", "failedCount", failed, "assertionCount", len(res.Assertions))
+		printErrorAndExit(errStream, expect.ErrorAssertionsFailed)
+	}
+	slog.Debug("Replay command completed successfully", "responseID", recordStore.ResponseID)
+}
+
+// Print the status, timing, important headers, and captured values of a response, followed by the body heading.
+func renderResponseTable(outStream io.Writer, res *response.ResponseObject) {
+	utils.Fprintf(outStream, "Request complete (Time taken %s)\n\n", res.Timing.Total.String())
+	statusStr := strconv.Itoa(int(res.StatusCode))
+	if res.StatusCode < 400 {
+		statusStr += " ✅"
+	} else {
+		statusStr += " ❌"
+	}
+	var resData [][]string
+	utils.Fprintln(outStream, "Response Table:")
+	resData = append(resData, []string{"HTTP Status Code", statusStr})
+	resData = append(resData, []string{"Body preview", utils.CreatePreview(res.Body)})
+	resData = append(resData, []string{"Size (Bytes)", strconv.Itoa(int(res.Size))})
+	resData = append(resData, []string{"DNS lookup time", res.Timing.DNSLookup.String()})
+	resData = append(resData, []string{"TCP connection time", res.Timing.TCPConnect.String()})
+	resData = append(resData, []string{"TLS handshake time", res.Timing.TLSHandshake.String()})
+	resData = append(resData, []string{"Time to first byte", res.Timing.FirstByte.String()})
+	resData = append(resData, []string{"Total time taken ⏳", res.Timing.Total.String()})
+	importantHeaders := []string{
+		"Content-Type", "Location", "Cache-Control",
+		"X-Ratelimit-Remaining", "X-Ratelimit-Limit",
+		"X-Ratelimit-Reset",
+	}
+
+	for _, headerName := range importantHeaders {
+		if value := res.Headers[headerName]; value != "" {
+			resData = append(resData, []string{headerName + " header", value})
+		}
+	}
+	capturedNames := make([]string, 0, len(res.Captured))
+	for name := range res.Captured {
+		capturedNames = append(capturedNames, name)
+	}
+	sort.Strings(capturedNames)
+	for _, name := range capturedNames {
+		resData = append(resData, []string{"Captured " + name, utils.CreatePreview(res.Captured[name])})
+	}
+	render.RenderTable(outStream, []string{"Property", "Value"}, resData...)
+	utils.Fprintln(outStream, "Response Body:")
+}
+
+// Print the outcome of each response assertion as a table.
+func renderAssertions(outStream io.Writer, assertions []response.Assertion) {
+	var data [][]string
+	for _, assertion := range assertions {
+		result := "✅"
+		if !assertion.Passed {
+			result = "❌"
+		}
+		data = append(data, []string{assertion.Check, utils.CreatePreview(assertion.Expected), utils.CreatePreview(assertion.Actual), result})
+	}
+	failed := expect.Failed(assertions)
+	utils.Fprintf(outStream, "Assertions (%d passed, %d failed):\n", len(assertions)-failed, failed)
+	render.RenderTable(outStream, []string{"Check", "Expected", "Actual", "Result"}, data...)
+	utils.Fprint(outStream, "\n")
+}
+
+// Print the differences between a response and the baseline of its template as a table.
+func renderBaselineComparison(outStream io.Writer, comparison *response.BaselineComparison) {
+	if len(comparison.Differences) == 0 {
+		utils.Fprintf(outStream, "Matches baseline %s ✅\n\n", comparison.ResponseID)
+		return
+	}
+	var data [][]string
+	for _, difference := range comparison.Differences {
+		data = append(data, []string{difference.Path, difference.Kind, utils.CreatePreview(difference.Baseline), utils.CreatePreview(difference.Actual)})
+	}
+	utils.Fprintf(outStream, "Baseline differences (%d, baseline %s):\n", len(comparison.Differences), comparison.ResponseID)
+	render.RenderTable(outStream, []string{"Path", "Change", "Baseline", "Actual"}, data...)
+	utils.Fprint(outStream, "\n")
+}
+
+// Load a template from the store by its hash.
+func loadStoredTemplate(recordStorePath string, templateHash string) (*request.RequestObject, []byte, error) {
+	recordStore := record.RecordStore{RecordStorePath: recordStorePath, TemplateHash: templateHash}
+	if err := recordStore.GetTemplateByHash(); err != nil {
+		return nil, nil, err
+	}
+	return request.LoadStoredTemplate(templateHash, recordStore.TemplateYaml)
+}
+
+// Load the baseline response pinned for a template.
+func loadBaselineResponse(recordStorePath string, templateHash string) (*response.ResponseObject, string, error) {
+	baselineStore := record.BaselineStore{
+		RecordStorePath: recordStorePath,
+		TemplateHash:    templateHash,
+	}
+	if err := baselineStore.GetBaseline(); err != nil {
+		return nil, "", err
+	}
+	recordStore := record.RecordStore{
+		RecordStorePath: recordStorePath,
+		ResponseID:      baselineStore.Baseline.ResponseID,
+	}
+	if err := recordStore.GetResponseByID(); err != nil {
+		return nil, "", err
+	}
+	return recordStore.Response, baselineStore.Baseline.ResponseID, nil
+}
+
+func runBaseline(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running baseline command", "args", args, "recordStorePath", recordStorePath)
+	var responseID, templateHash string
+	baselineCommand := flag.NewFlagSet("baseline", flag.ExitOnError)
+	baselineCommand.StringVar(&responseID, "response", "", "Response ID to pin as the baseline of its template")
+	baselineCommand.StringVar(&responseID, "re", "", "Response ID to pin as the baseline of its template (shorthand)")
+	baselineCommand.StringVar(&templateHash, "template", "", "Template hash whose baseline is cleared")
+	baselineCommand.StringVar(&templateHash, "tp", "", "Template hash whose baseline is cleared (shorthand)")
+	baselineCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of baseline:\nreqcorder baseline set (-response|-re) <response_id> [--verbose|-v]\nreqcorder baseline clear (-template|-tp) <template_hash> [--verbose|-v]")
+		baselineCommand.PrintDefaults()
+	}
+	if len(args) < 1 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+			baselineCommand.Usage()
+			return
+		}
+		slog.Error("No action provided for baseline command")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	action := args[0]
+	baselineCommand.Parse(args[1:])
+	switch action {
+	case "set":
+		if responseID == "" {
+			slog.Error("No response ID provided for baseline set")
+			printErrorAndExit(errStream, ErrorInvalidUsage)
+		}
+		recordStore := record.RecordStore{
+			RecordStorePath: recordStorePath,
+			ResponseID:      responseID,
+		}
+		if err := recordStore.GetResponseByID(); err != nil {
+			slog.Error("Failed to get response by ID", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		baselineStore := record.BaselineStore{
+			RecordStorePath: recordStorePath,
+			Baseline: &record.Baseline{
+				TemplateHash: recordStore.TemplateHash,
+				RequestHash:  recordStore.RequestHash,
+				ResponseID:   responseID,
+				CreatedAt:    time.Now().UTC(),
+			},
+		}
+		if err := baselineStore.Record(); err != nil {
+			slog.Error("Failed to record baseline", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintf(outStream, "Baseline of template %s set to response %s\n", baselineStore.TemplateHash, responseID)
+	case "clear":
+		if templateHash == "" {
+			slog.Error("No template hash provided for baseline clear")
+			printErrorAndExit(errStream, ErrorInvalidUsage)
+		}
+		baselineStore := record.BaselineStore{
+			RecordStorePath: recordStorePath,
+			TemplateHash:    templateHash,
+		}
+		if err := baselineStore.Remove(); err != nil {
+			slog.Error("Failed to remove baseline", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintf(outStream, "Baseline of template %s cleared\n", templateHash)
+	default:
+		slog.Error("Invalid baseline action", "action", action)
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	slog.Debug("Baseline command completed successfully", "action", action)
+}
+
+// Print the locations where a body does not match its JSON Schema as a table.
+func renderSchemaViolations(outStream io.Writer, violations []response.SchemaViolation) {
+	var data [][]string
+	for _, violation := range violations {
+		data = append(data, []string{pointerOrRoot(violation.Path), violation.Message, violation.Keyword})
+	}
+	utils.Fprintf(outStream, "Schema violations (%d):\n", len(violations))
+	render.RenderTable(outStream, []string{"Path", "Message", "Schema keyword"}, data...)
+	utils.Fprint(outStream, "\n")
+}
+
+// The JSON pointer of the whole document is empty, which reads poorly in tables.
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "(root)"
+	}
+	return pointer
+}
+
+func runCheck(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running check command", "args", args, "recordStorePath", recordStorePath)
+	var responseID, schemaPath string
+	checkCommand := flag.NewFlagSet("check", flag.ExitOnError)
+	checkCommand.StringVar(&responseID, "response", "", "Response ID")
+	checkCommand.StringVar(&responseID, "re", "", "Response ID (shorthand)")
+	checkCommand.StringVar(&schemaPath, "schema", "", "JSON Schema (draft 2020-12) file")
+	checkCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of check:\nreqcorder check (-response|-re) <response_id> --schema <schema_file> [--verbose|-v]")
+		checkCommand.PrintDefaults()
+	}
+	checkCommand.Parse(args)
+	if responseID == "" || schemaPath == "" {
+		slog.Error("Both response ID and schema must be provided")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	schema, err := jsonschema.Load(schemaPath)
+	if err != nil {
+		slog.Error("Failed to load JSON Schema", "schemaPath", schemaPath, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	recordStore := record.RecordStore{
+		RecordStorePath: recordStorePath,
+		ResponseID:      responseID,
+	}
+	err = recordStore.GetResponseByID()
+	if err != nil {
+		slog.Error("Failed to get response by ID", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	violations, err := expect.ValidateSchema(schema, recordStore.Response.Body)
+	if err != nil {
+		slog.Error("Failed to validate response body", "responseID", responseID, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	if len(violations) > 0 {
+		renderSchemaViolations(outStream, violations)
+		slog.Error("Response body does not match the JSON Schema", "responseID", responseID, "violationCount", len(violations))
+		printErrorAndExit(errStream, expect.ErrorSchemaViolations)
+	}
+	utils.Fprintf(outStream, "Response %s matches %s ✅\n", responseID, filepath.Base(schemaPath))
+	slog.Debug("Check command completed successfully", "responseID", responseID)
 }
 
 func runList(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
@@ -338,6 +1187,8 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 		requestType  = "requests"
 		templateType = "templates"
 		responseType = "responses"
+		runType      = "runs"
+		inboundType  = "inbound"
 	)
 
 	for _, arg := range args {
@@ -349,7 +1200,7 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 			listCommand.StringVar(&template, "template", "", "Template hash filter")
 			listCommand.StringVar(&template, "tp", "", "Template hash filter (shorthand)")
 			listCommand.Usage = func() {
-				utils.Fprintln(errStream, "Usage of list:\nreqcorder list [-n] (templates|requests|responses) [-template|-tp|-request|-rq] [--verbose|-v]")
+				utils.Fprintln(errStream, "Usage of list:\nreqcorder list [-n] (templates|requests|responses|runs|inbound) [-template|-tp|-request|-rq] [--verbose|-v]")
 				listCommand.PrintDefaults()
 			}
 			listCommand.Usage()
@@ -366,6 +1217,8 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 		templateType: true,
 		requestType:  true,
 		responseType: true,
+		runType:      true,
+		inboundType:  true,
 	}
 	if !validListTypes[listType] {
 		slog.Error("Invalid list type provided", "listType", listType)
@@ -379,7 +1232,7 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 	listCommand.StringVar(&template, "template", "", "Template hash filter")
 	listCommand.StringVar(&template, "tp", "", "Template hash filter (shorthand)")
 	listCommand.Usage = func() {
-		utils.Fprintln(errStream, "Usage of list:\nreqcorder list [-n] (templates|requests|responses) [-template|-tp|-request|-rq] [--verbose|-v]")
+		utils.Fprintln(errStream, "Usage of list:\nreqcorder list [-n] (templates|requests|responses|runs|inbound) [-template|-tp|-request|-rq] [--verbose|-v]")
 		listCommand.PrintDefaults()
 	}
 	listCommand.Parse(args[1:])
@@ -426,7 +1279,7 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 				printErrorAndExit(errStream, err)
 			}
 			utils.Fprintf(outStream, "Request History (%d requests)\n", len(data))
-			render.RenderTable(outStream, []string{"Request Hash", "Template Hash", "Last Modified"}, data...)
+			render.RenderTable(outStream, []string{"Request Hash", "Template Hash", "Environment", "Last Modified"}, data...)
 		}
 	case templateType:
 		slog.Debug("Listing all templates sorted by modification time", "limit", limit)
@@ -436,7 +1289,25 @@ func runList(outStream io.Writer, errStream io.Writer, args []string, recordStor
 			printErrorAndExit(errStream, err)
 		}
 		utils.Fprintf(outStream, "Template History (%d templates)\n", len(data))
-		render.RenderTable(outStream, []string{"Template Hash", "Last Modified"}, data...)
+		render.RenderTable(outStream, []string{"Template Hash", "Last Modified", "Baseline"}, data...)
+	case runType:
+		slog.Debug("Listing all runs sorted by modification time", "limit", limit)
+		data, err := historyStore.GetAllRunsSorted(limit)
+		if err != nil {
+			slog.Error("Failed to get all runs sorted", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintf(outStream, "Run History (%d runs)\n", len(data))
+		render.RenderTable(outStream, []string{"Run ID", "Collection", "Passed", "Duration", "Timestamp"}, data...)
+	case inboundType:
+		slog.Debug("Listing all inbound requests sorted by modification time", "limit", limit)
+		data, err := historyStore.GetAllInboundSorted(limit)
+		if err != nil {
+			slog.Error("Failed to get all inbound requests sorted", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+		utils.Fprintf(outStream, "Inbound History (%d requests)\n", len(data))
+		render.RenderTable(outStream, []string{"Inbound ID", "Method", "Target", "Remote Address", "Size", "Timestamp"}, data...)
 	default:
 		slog.Error("Invalid list type provided", "listType", listType)
 		printErrorAndExit(errStream, ErrorInvalidListType)
@@ -452,7 +1323,18 @@ Subcommands:
   diff     Compare two templates, requests, or responses
   show     Display a specific template, request, or response
   exec     Execute HTTP request from a template file
-  list     List templates, requests, or responses in the store
+  replay   Send a recorded request again without its template file
+  export   Print a recorded request as a curl command or code snippet
+  import   Convert a curl command into a template
+  list     List templates, requests, responses, runs, or inbound requests
+  run      Execute the requests of a collection file in order
+  validate Check template files for unknown keys and invalid values
+  check    Validate a recorded response body against a JSON Schema
+  baseline Pin a recorded response as the baseline of its template
+  monitor  Run a template or collection on an interval and report availability
+  serve    Answer HTTP requests with recorded responses as a local mock server
+  proxy    Record traffic from other HTTP clients through a forward proxy
+  listen   Record inbound HTTP requests such as webhooks with a local receiver
 
 Run "reqcorder <subcommand> --help" for more details.`)
 }
@@ -547,9 +1429,42 @@ func main() {
 	case "exec":
 		slog.Debug("Running exec command")
 		runExec(outStream, errStream, subcommandArgs, recordStorePath)
+	case "replay":
+		slog.Debug("Running replay command")
+		runReplay(outStream, errStream, subcommandArgs, recordStorePath)
+	case "export":
+		slog.Debug("Running export command")
+		runExport(outStream, errStream, subcommandArgs, recordStorePath)
+	case "import":
+		slog.Debug("Running import command")
+		runImport(outStream, errStream, subcommandArgs, recordStorePath)
 	case "list":
 		slog.Debug("Running list command")
 		runList(outStream, errStream, subcommandArgs, recordStorePath)
+	case "run":
+		slog.Debug("Running run command")
+		runRun(outStream, errStream, subcommandArgs, recordStorePath)
+	case "validate":
+		slog.Debug("Running validate command")
+		runValidate(outStream, errStream, subcommandArgs)
+	case "check":
+		slog.Debug("Running check command")
+		runCheck(outStream, errStream, subcommandArgs, recordStorePath)
+	case "baseline":
+		slog.Debug("Running baseline command")
+		runBaseline(outStream, errStream, subcommandArgs, recordStorePath)
+	case "monitor":
+		slog.Debug("Running monitor command")
+		runMonitor(outStream, errStream, subcommandArgs, recordStorePath)
+	case "serve":
+		slog.Debug("Running serve command")
+		runServe(outStream, errStream, subcommandArgs, recordStorePath)
+	case "proxy":
+		slog.Debug("Running proxy command")
+		runProxy(outStream, errStream, subcommandArgs, recordStorePath)
+	case "listen":
+		slog.Debug("Running listen command")
+		runListen(outStream, errStream, subcommandArgs, recordStorePath)
 	case "help", "-h", "--help":
 		slog.Debug("Printing help")
 		printRootUsage(outStream)
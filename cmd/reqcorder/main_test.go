@@ -2,7 +2,13 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"reqcorder/internal/request"
 	"strings"
 	"testing"
 )
@@ -69,3 +75,47 @@ func TestFailedInvalidCommand(t *testing.T) {
 		t.Error("Expected error when executing invalid command")
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "Body file", err: fmt.Errorf("%w %q: missing", request.ErrorFailedToReadBodyFile, "body.json"), expected: 1},
+		{name: "Environment not found", err: fmt.Errorf("%w %q", request.ErrorEnvironmentNotFound, "staging"), expected: 2},
+		{name: "Invalid URL", err: request.ErrorInvalidURL, expected: 2},
+		{name: "Unresolved placeholders", err: request.ErrorUnresolvedPlaceholders, expected: 6},
+		{name: "Unknown error", err: errors.New("unknown"), expected: 125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := exitCode(tt.err); code != tt.expected {
+				t.Errorf("Expected exit code %d, received %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestLoadVarOverrides(t *testing.T) {
+	varsFile := filepath.Join(t.TempDir(), "vars.yaml")
+	if err := os.WriteFile(varsFile, []byte("user: file-user\nregion: eu\n"), 0644); err != nil {
+		t.Fatalf("Failed to write vars file: %v", err)
+	}
+	overrides, err := loadVarOverrides(varsFile, []string{"user=flag-user", "token=a=b"})
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	expected := map[string]string{"user": "flag-user", "region": "eu", "token": "a=b"}
+	if !reflect.DeepEqual(overrides, expected) {
+		t.Errorf("Expected overrides %v, received %v", expected, overrides)
+	}
+	_, err = loadVarOverrides("", []string{"missing-separator"})
+	if !errors.Is(err, request.ErrorInvalidVarOverride) {
+		t.Errorf("Expected invalid override error, received %v", err)
+	}
+	_, err = loadVarOverrides(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	if !errors.Is(err, request.ErrorInvalidVarsFile) {
+		t.Errorf("Expected invalid vars file error, received %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reqcorder/internal/collection"
+	"reqcorder/internal/monitor"
+	"reqcorder/internal/record"
+	"reqcorder/pkg/render"
+	"reqcorder/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	stopCompleted   = "completed"
+	stopInterrupted = "interrupted"
+	stopBreached    = "breached"
+)
+
+func runMonitor(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running monitor command", "args", args, "recordStorePath", recordStorePath)
+	var every, duration time.Duration
+	var windowSize int
+	var thresholds monitor.Thresholds
+	var quiet bool
+	var options requestOptions
+	monitorCommand := flag.NewFlagSet("monitor", flag.ExitOnError)
+	monitorCommand.DurationVar(&every, "every", 30*time.Second, "Time between two checks")
+	monitorCommand.DurationVar(&duration, "for", 0, "Stop after this long, runs until interrupted when not set")
+	monitorCommand.IntVar(&windowSize, "window", 20, "Number of recent requests the rolling stats and thresholds cover")
+	monitorCommand.Float64Var(&thresholds.MaxErrorRate, "max-error-rate", 0, "Fail when the percentage of failed requests in the window exceeds this")
+	monitorCommand.DurationVar(&thresholds.MaxLatency, "max-latency", 0, "Fail when the latency percentile of the window exceeds this")
+	monitorCommand.Float64Var(&thresholds.Percentile, "percentile", 95, "Latency percentile checked against --max-latency")
+	monitorCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	monitorCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	options.register(monitorCommand)
+	monitorCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of monitor:\nreqcorder monitor [--every <duration>] [--for <duration>] [--window <count>] [--max-error-rate <percent>] [--max-latency <duration> [--percentile <p>]] [--quiet|-q] [--env|-e <name>] [--vars-file <file>] [--var key=value]... [--no-strict] (<template_path> | <collection_path>) [--verbose|-v]")
+		monitorCommand.PrintDefaults()
+	}
+	monitorCommand.Parse(args)
+	if monitorCommand.NArg() < 1 {
+		slog.Error("No template or collection path provided for monitor command")
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	if every <= 0 || duration < 0 || windowSize < 1 || thresholds.MaxErrorRate < 0 || thresholds.MaxErrorRate > 100 || thresholds.MaxLatency < 0 || thresholds.Percentile <= 0 || thresholds.Percentile > 100 {
+		slog.Error("Invalid monitor settings", "every", every, "for", duration, "window", windowSize, slog.Any("thresholds", thresholds))
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	if thresholds.MaxLatency == 0 {
+		thresholds.Percentile = 0
+	}
+	targetPath := monitorCommand.Args()[0]
+	c, kind, err := loadMonitorTarget(targetPath)
+	if err != nil {
+		slog.Error("Failed to load monitor target", "targetPath", targetPath, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	err = checkCollection(c)
+	if err != nil {
+		slog.Error("Template check failed", "targetPath", targetPath, "error", err)
+		printErrorAndExit(errStream, err)
+	}
+
+	// Requests are only cancelled by a signal, so the last check of --for still completes.
+	interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx := interrupted
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(interrupted, duration)
+		defer cancel()
+	}
+	summary := monitor.Summary{
+		Target:      c.Name,
+		TargetPath:  targetPath,
+		Kind:        kind,
+		Environment: options.environment,
+		Interval:    every,
+		Window:      windowSize,
+		Thresholds:  thresholds,
+		StartedAt:   time.Now().UTC(),
+	}
+	live := isTerminal(outStream)
+	if !quiet {
+		utils.Fprintf(outStream, "Monitoring %s %s every %s", kind, c.Name, every)
+		if duration > 0 {
+			utils.Fprintf(outStream, " for %s", duration)
+		}
+		utils.Fprint(outStream, ", press Ctrl+C to stop\n\n")
+	}
+	window := monitor.NewWindow(windowSize)
+	var totals monitor.Stats
+	var breach error
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		summary.Checks++
+		slog.Debug("Starting monitor check", "check", summary.Checks)
+		captured := make(map[string]string)
+		for _, item := range c.Requests {
+			result := runCollectionItem(interrupted, c, item, options, captured, recordStorePath)
+			if interrupted.Err() != nil {
+				slog.Debug("Monitor check interrupted", "name", item.Name)
+				break
+			}
+			for name, value := range result.Captured {
+				captured[name] = value
+			}
+			sample := monitor.Sample{
+				Name:             result.Name,
+				StatusCode:       result.StatusCode,
+				Latency:          result.Duration,
+				Passed:           result.Passed,
+				AssertionCount:   result.AssertionCount,
+				FailedAssertions: len(result.FailedAssertions),
+				Error:            result.Error,
+			}
+			window.Add(sample)
+			totals.Add(sample)
+			if !quiet && !sample.Passed {
+				if live {
+					utils.Fprint(outStream, "\r\033[K")
+				}
+				utils.Fprintf(outStream, "[%s] %s\n", time.Now().Format(time.TimeOnly), describeFailedSample(sample))
+			}
+		}
+		stats := window.Stats()
+		if !quiet {
+			line := fmt.Sprintf("[%s] check %d, last %d requests: %s", time.Now().Format(time.TimeOnly), summary.Checks, stats.Requests, describeStats(stats))
+			if live {
+				utils.Fprint(outStream, "\r\033[K"+line)
+			} else {
+				utils.Fprintln(outStream, line)
+			}
+		}
+		if breach = thresholds.Check(stats); breach != nil {
+			slog.Error("Monitor threshold breached", "error", breach)
+			summary.StopReason = stopBreached
+			summary.Breach = strings.TrimPrefix(breach.Error(), monitor.ErrorThresholdBreached.Error()+": ")
+			break
+		}
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+				continue
+			}
+		}
+		summary.StopReason = stopInterrupted
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			summary.StopReason = stopCompleted
+		}
+		break
+	}
+	if !quiet && live {
+		utils.Fprint(outStream, "\n")
+	}
+	summary.Duration = time.Since(summary.StartedAt)
+	summary.Totals = totals
+	summary.LastWindow = window.Stats()
+	slog.Debug("Monitor stopped", "stopReason", summary.StopReason, "checks", summary.Checks)
+	monitorYaml, err := utils.ConvertToYAML(summary)
+	if err != nil {
+		slog.Error("Failed to convert monitor summary to YAML", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	monitorStore := record.MonitorStore{
+		RecordStorePath: recordStorePath,
+		MonitorYaml:     monitorYaml,
+	}
+	err = monitorStore.Record()
+	if err != nil {
+		slog.Error("Failed to record monitor summary", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	if !quiet {
+		renderMonitorSummary(outStream, &summary)
+		utils.Fprintf(outStream, "\nMonitor ID - %s\n", monitorStore.MonitorID)
+	}
+	if breach != nil {
+		printErrorAndExit(errStream, breach)
+	}
+	slog.Debug("Monitor command completed successfully", "monitorID", monitorStore.MonitorID)
+}
+
+// Load the file to monitor. A template is wrapped in a collection with a single request, so both are run the same way.
+func loadMonitorTarget(path string) (*collection.CollectionObject, string, error) {
+	isCollection, err := collection.IsCollectionFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if isCollection {
+		c, _, err := collection.LoadCollection(path)
+		return c, "collection", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	c := &collection.CollectionObject{
+		Name:     name,
+		Path:     absPath,
+		Requests: []collection.CollectionItem{{Name: name, Template: absPath}},
+	}
+	return c, "template", nil
+}
+
+// Describe window stats on one line: the error rate, status codes, assertions, and latency percentiles.
+func describeStats(stats monitor.Stats) string {
+	parts := []string{"errors " + monitor.FormatRate(stats.ErrorRate)}
+	if len(stats.StatusCodes) > 0 {
+		parts = append(parts, "status "+describeStatusCodes(stats.StatusCodes))
+	}
+	if stats.AssertionCount > 0 {
+		parts = append(parts, fmt.Sprintf("assertions %d/%d passed", stats.AssertionCount-stats.FailedAssertions, stats.AssertionCount))
+	}
+	if len(stats.StatusCodes) > 0 {
+		parts = append(parts, fmt.Sprintf("p50 %s p95 %s p99 %s", roundLatency(stats.Latency.P50), roundLatency(stats.Latency.P95), roundLatency(stats.Latency.P99)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// List status codes with their counts, such as 200x9 503x1.
+func describeStatusCodes(statusCodes map[int]int) string {
+	codes := make([]int, 0, len(statusCodes))
+	for code := range statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%dx%d", code, statusCodes[code])
+	}
+	return strings.Join(parts, " ")
+}
+
+func describeFailedSample(sample monitor.Sample) string {
+	switch {
+	case sample.Error != "":
+		return fmt.Sprintf("%s failed ❌ (%s)", sample.Name, sample.Error)
+	case sample.FailedAssertions > 0:
+		return fmt.Sprintf("%s %d ❌ (%d of %d assertions failed)", sample.Name, sample.StatusCode, sample.FailedAssertions, sample.AssertionCount)
+	default:
+		return fmt.Sprintf("%s %d ❌", sample.Name, sample.StatusCode)
+	}
+}
+
+// Print the totals of a monitor session.
+func renderMonitorSummary(outStream io.Writer, summary *monitor.Summary) {
+	totals := summary.Totals
+	data := [][]string{
+		{"Checks", strconv.Itoa(summary.Checks)},
+		{"Requests", strconv.Itoa(totals.Requests)},
+		{"Failed", strconv.Itoa(totals.Failed)},
+		{"Error rate", monitor.FormatRate(totals.ErrorRate)},
+	}
+	if len(totals.StatusCodes) > 0 {
+		data = append(data, []string{"Status codes", describeStatusCodes(totals.StatusCodes)})
+	}
+	if totals.AssertionCount > 0 {
+		data = append(data, []string{"Assertions passed", fmt.Sprintf("%d/%d", totals.AssertionCount-totals.FailedAssertions, totals.AssertionCount)})
+	}
+	if window := summary.LastWindow; len(window.StatusCodes) > 0 {
+		suffix := fmt.Sprintf(" (last %d)", window.Requests)
+		data = append(data,
+			[]string{"Latency p50" + suffix, roundLatency(window.Latency.P50).String()},
+			[]string{"Latency p90" + suffix, roundLatency(window.Latency.P90).String()},
+			[]string{"Latency p95" + suffix, roundLatency(window.Latency.P95).String()},
+			[]string{"Latency p99" + suffix, roundLatency(window.Latency.P99).String()},
+			[]string{"Latency max" + suffix, roundLatency(window.Latency.Max).String()},
+		)
+	}
+	stopReason := summary.StopReason
+	if summary.Breach != "" {
+		stopReason += " (" + summary.Breach + ")"
+	}
+	data = append(data, []string{"Stopped", stopReason})
+	utils.Fprintf(outStream, "\nMonitor Summary (%s, %s)\n", summary.Target, summary.Duration.Round(time.Second))
+	render.RenderTable(outStream, []string{"Metric", "Value"}, data...)
+}
+
+func roundLatency(latency time.Duration) time.Duration {
+	return latency.Round(time.Millisecond / 10)
+}
+
+// Report whether output goes to a terminal, where the status line is redrawn in place.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
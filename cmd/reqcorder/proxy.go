@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"reqcorder/internal/proxy"
+	"reqcorder/internal/record"
+	"reqcorder/pkg/utils"
+	"time"
+)
+
+func runProxy(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running proxy command", "args", args, "recordStorePath", recordStorePath)
+	var listen string
+	var filter proxy.Filter
+	var hosts, paths stringListFlag
+	var mitm, insecure, quiet bool
+	proxyCommand := flag.NewFlagSet("proxy", flag.ExitOnError)
+	proxyCommand.StringVar(&listen, "listen", "127.0.0.1:8888", "Address to listen on, such as :8888 for all interfaces")
+	proxyCommand.Var(&hosts, "host", "Only record traffic to this host or host glob such as *.example.com (repeatable)")
+	proxyCommand.Var(&paths, "path", "Only record traffic under this path prefix or path glob (repeatable)")
+	proxyCommand.BoolVar(&mitm, "mitm", false, "Intercept HTTPS traffic to recorded hosts with certificates from a local CA")
+	proxyCommand.BoolVar(&insecure, "insecure", false, "Skip verification of upstream TLS certificates")
+	proxyCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	proxyCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	proxyCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of proxy:\nreqcorder proxy [--listen <address>] [--host <pattern>]... [--path <pattern>]... [--mitm] [--insecure] [--quiet|-q] [--verbose|-v]")
+		proxyCommand.PrintDefaults()
+	}
+	proxyCommand.Parse(args)
+	if proxyCommand.NArg() > 0 {
+		slog.Error("Unexpected arguments for proxy command", "args", proxyCommand.Args())
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	filter.Hosts, filter.Paths = hosts, paths
+	var ca *proxy.CA
+	if mitm {
+		var err error
+		ca, err = proxy.LoadOrCreateCA(filepath.Join(filepath.Dir(recordStorePath), "proxy"))
+		if err != nil {
+			slog.Error("Failed to load proxy CA", "error", err)
+			printErrorAndExit(errStream, err)
+		}
+	}
+	p := proxy.New(filter, ca, insecure)
+	tally := &recordingTally{outStream: outStream, quiet: quiet}
+	p.OnExchange = func(e *proxy.Exchange) {
+		if e.Request == nil {
+			tally.skip(fmt.Sprintf("%s %s %s %d not recorded", time.Now().Format(time.TimeOnly), e.Method, e.URL, e.StatusCode))
+			return
+		}
+		recordStore := record.RecordStore{
+			RecordStorePath: recordStorePath,
+			TemplateYaml:    e.TemplateYaml,
+			Request:         e.Request,
+			Response:        e.Response,
+		}
+		status := "recording failed"
+		err := recordStore.Record()
+		if err != nil {
+			slog.Error("Failed to record proxied exchange", "url", e.URL, "error", err)
+		} else {
+			status = "recorded " + recordStore.ResponseID
+		}
+		tally.add(err, fmt.Sprintf("%s %s %s %d %s", time.Now().Format(time.TimeOnly), e.Method, e.URL, e.StatusCode, status))
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		err = fmt.Errorf("%w on %s: %v", ErrorFailedToListen, listen, err)
+		slog.Error("Failed to listen", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	if !quiet {
+		utils.Fprintf(outStream, "Proxying on http://%s, press Ctrl+C to stop\n", listener.Addr())
+		if ca != nil {
+			utils.Fprintf(outStream, "HTTPS traffic is intercepted, trust the CA certificate at %s\n", ca.CertPath)
+		} else {
+			utils.Fprintln(outStream, "HTTPS traffic is tunneled and not recorded, pass --mitm to record it")
+		}
+		utils.Fprintln(outStream)
+	}
+	httpServer := &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	if err := serveUntilSignal(httpServer, listener, "proxy"); err != nil {
+		printErrorAndExit(errStream, err)
+	}
+	tally.summarize("exchange")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"reqcorder/internal/mock"
+	"reqcorder/pkg/render"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"sync"
+	"time"
+)
+
+func runServe(outStream io.Writer, errStream io.Writer, args []string, recordStorePath string) {
+	slog.Debug("Running serve command", "args", args, "recordStorePath", recordStorePath)
+	var host, pick string
+	var port int
+	var options mock.Options
+	var noHints, quiet bool
+	serveCommand := flag.NewFlagSet("serve", flag.ExitOnError)
+	serveCommand.IntVar(&port, "port", 8080, "Port to listen on")
+	serveCommand.StringVar(&host, "host", "127.0.0.1", "Address to listen on")
+	serveCommand.StringVar(&pick, "pick", mock.PickLatest, "Recorded response served for each request, latest or baseline")
+	serveCommand.BoolVar(&options.MatchBody, "match-body", false, "Also match request bodies, JSON and form bodies by value")
+	serveCommand.BoolVar(&options.ReplayLatency, "latency", false, "Delay replies by the total time recorded with the response")
+	serveCommand.IntVar(&options.MissStatus, "miss-status", http.StatusNotFound, "Status code returned when no recorded request matches")
+	serveCommand.BoolVar(&noHints, "no-hints", false, "Leave near misses out of replies to unmatched requests")
+	serveCommand.BoolVar(&quiet, "quiet", false, "No output on stdout")
+	serveCommand.BoolVar(&quiet, "q", false, "No output on stdout (shorthand)")
+	serveCommand.Usage = func() {
+		utils.Fprintln(errStream, "Usage of serve:\nreqcorder serve [--port <port>] [--host <address>] [--pick latest|baseline] [--match-body] [--latency] [--miss-status <code>] [--no-hints] [--quiet|-q] [--verbose|-v]")
+		serveCommand.PrintDefaults()
+	}
+	serveCommand.Parse(args)
+	if serveCommand.NArg() > 0 || port < 0 || port > 65535 || options.MissStatus < 100 || options.MissStatus > 599 {
+		slog.Error("Invalid serve settings", "args", serveCommand.Args(), "port", port, "missStatus", options.MissStatus)
+		printErrorAndExit(errStream, ErrorInvalidUsage)
+	}
+	options.Hints = !noHints
+	routes, err := mock.LoadRoutes(recordStorePath, pick)
+	if err != nil {
+		slog.Error("Failed to load recorded routes", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	var mu sync.Mutex
+	server := &mock.Server{Routes: routes, Options: options}
+	if !quiet {
+		server.OnServe = func(r *mock.Request, route *mock.Route, status int) {
+			mu.Lock()
+			defer mu.Unlock()
+			line := fmt.Sprintf("%s %s %s %d", time.Now().Format(time.TimeOnly), r.Method, requestTarget(r.Path, r.Query.Encode()), status)
+			if route != nil {
+				line += " " + route.ResponseID
+			} else {
+				line += " no match"
+			}
+			utils.Fprintln(outStream, line)
+		}
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		err = fmt.Errorf("%w on %s: %v", ErrorFailedToListen, address, err)
+		slog.Error("Failed to listen", "error", err)
+		printErrorAndExit(errStream, err)
+	}
+	if !quiet {
+		renderRoutes(outStream, routes)
+		utils.Fprintf(outStream, "\nServing %d recorded routes on http://%s, press Ctrl+C to stop\n\n", len(routes), listener.Addr())
+	}
+	httpServer := &http.Server{Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	if err := serveUntilSignal(httpServer, listener, "mock server"); err != nil {
+		printErrorAndExit(errStream, err)
+	}
+	if !quiet {
+		utils.Fprintln(outStream, "\nStopped serving")
+	}
+}
+
+func renderRoutes(outStream io.Writer, routes []mock.Route) {
+	var data [][]string
+	for _, route := range routes {
+		data = append(data, []string{route.Method, requestTarget(route.Path, route.Query.Encode()), strconv.Itoa(route.Response.StatusCode), route.RequestHash, route.ResponseID})
+	}
+	render.RenderTable(outStream, []string{"Method", "Path", "Status", "Request Hash", "Response ID"}, data...)
+}
+
+func requestTarget(path string, rawQuery string) string {
+	if rawQuery == "" {
+		return path
+	}
+	return path + "?" + rawQuery
+}
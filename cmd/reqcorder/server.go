@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"reqcorder/pkg/utils"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long in-flight replies may take once the server is stopped.
+const shutdownTimeout = 5 * time.Second
+
+// Serve on the listener until the server fails or an interrupt or termination signal arrives, then shut it down.
+// The error is nil when the server was stopped by a signal.
+func serveUntilSignal(httpServer *http.Server, listener net.Listener, name string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- httpServer.Serve(listener) }()
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		slog.Error("Server stopped", "server", name, "error", err)
+		return err
+	case <-ctx.Done():
+		slog.Debug("Shutting down server", "server", name)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("Server did not shut down cleanly", "server", name, "error", err)
+		}
+		return nil
+	}
+}
+
+// recordingTally counts what a server command recorded and keeps the lines it prints from interleaving.
+type recordingTally struct {
+	outStream io.Writer
+	quiet     bool
+	mu        sync.Mutex
+	recorded  int
+	failed    int
+}
+
+// Count a recording, failed when err is set, and print its line.
+func (t *recordingTally) add(err error, line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		t.recorded++
+	} else {
+		t.failed++
+	}
+	t.println(line)
+}
+
+// Print the line of something that was not recorded.
+func (t *recordingTally) skip(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.println(line)
+}
+
+func (t *recordingTally) println(line string) {
+	if !t.quiet {
+		utils.Fprintln(t.outStream, line)
+	}
+}
+
+// Print how many recordings were made and how many failed, with noun naming what was recorded.
+func (t *recordingTally) summarize(noun string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.quiet {
+		return
+	}
+	utils.Fprintf(t.outStream, "\nRecorded %d %s(s)", t.recorded, noun)
+	if t.failed > 0 {
+		utils.Fprintf(t.outStream, ", %d could not be recorded", t.failed)
+	}
+	utils.Fprintln(t.outStream)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reqcorder/internal/baseline"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/internal/watch"
+	"reqcorder/pkg/utils"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	watchInterval = 200 * time.Millisecond
+	watchDebounce = 300 * time.Millisecond
+)
+
+// Execute a template, then execute it again whenever the template or a file it references changes, until interrupted.
+// Failed executions are reported without stopping the loop.
+func runExecWatch(outStream io.Writer, errStream io.Writer, config execConfig, recordStorePath string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	watcher := watch.New(watchInterval, watchDebounce)
+	var previous *response.ResponseObject
+	for iteration := 1; ; iteration++ {
+		slog.Debug("Starting watch iteration", "iteration", iteration)
+		req, res, err := config.execute(outStream, recordStorePath)
+		if err != nil {
+			utils.PrintError(errStream, formatErrorMessage(err, errorDetails(err)...))
+		}
+		if res != nil {
+			if previous != nil && !config.quiet {
+				renderRunChanges(outStream, baseline.Compare(previous, res, config.ignorePaths(req)))
+			}
+			previous = res
+		}
+		files := config.watchedFiles(req)
+		watcher.Watch(files)
+		if !config.quiet {
+			utils.Fprintf(outStream, "Watching %d files for changes, press Ctrl+C to stop...\n", len(files))
+		}
+		changed, err := watcher.Wait(ctx)
+		if err != nil {
+			slog.Debug("Watch stopped", "iterations", iteration)
+			if !config.quiet {
+				utils.Fprintln(outStream, "\nStopped watching")
+			}
+			return
+		}
+		if !config.quiet {
+			utils.Fprintf(outStream, "\nChanged: %s\n\n", describeFiles(changed))
+		}
+	}
+}
+
+// List the files watched for a request: the template and the templates it extends, the vars file,
+// and the files the request reads. The request is nil when the template could not be loaded.
+func (c *execConfig) watchedFiles(req *request.RequestObject) []string {
+	var files []string
+	if c.templatePath != "" {
+		files = append(files, request.TemplateChain(c.templatePath)...)
+	}
+	if c.options.varsFile != "" {
+		files = append(files, c.options.varsFile)
+	}
+	if req != nil {
+		files = append(files, req.Dependencies()...)
+	}
+	return files
+}
+
+// Print how a response differs from the response of the previous run, one line per difference.
+func renderRunChanges(outStream io.Writer, differences []response.Difference) {
+	if len(differences) == 0 {
+		utils.Fprint(outStream, "No changes since the previous run\n\n")
+		return
+	}
+	utils.Fprintf(outStream, "Changes since the previous run (%d):\n", len(differences))
+	for _, difference := range differences {
+		switch difference.Kind {
+		case baseline.KindAdded:
+			utils.Fprintf(outStream, "  + %s: %s\n", difference.Path, utils.CreatePreview(difference.Actual))
+		case baseline.KindRemoved:
+			utils.Fprintf(outStream, "  - %s: %s\n", difference.Path, utils.CreatePreview(difference.Baseline))
+		default:
+			utils.Fprintf(outStream, "  ~ %s: %s -> %s\n", difference.Path, utils.CreatePreview(difference.Baseline), utils.CreatePreview(difference.Actual))
+		}
+	}
+	utils.Fprint(outStream, "\n")
+}
+
+// Name changed files relative to the working directory when they are below it.
+func describeFiles(files []string) string {
+	names := make([]string, len(files))
+	wd, _ := os.Getwd()
+	for i, file := range files {
+		names[i] = file
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			names[i] = rel
+		}
+	}
+	return strings.Join(names, ", ")
+}
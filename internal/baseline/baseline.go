@@ -0,0 +1,209 @@
+package baseline
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/jsonpath"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+const (
+	KindChanged = "changed"
+	KindAdded   = "added"
+	KindRemoved = "removed"
+)
+
+// DefaultIgnore lists headers that change between otherwise identical responses. Timing is never compared.
+var DefaultIgnore = []string{"header:Date", "header:Age", "header:Expires", "header:Content-Length"}
+
+// ignoreSet is a parsed list of ignore paths.
+type ignoreSet struct {
+	status   bool
+	body     bool
+	headers  map[string]bool
+	patterns []jsonpath.Path
+}
+
+func parseIgnore(paths []string) ignoreSet {
+	set := ignoreSet{headers: make(map[string]bool)}
+	for _, path := range paths {
+		switch {
+		case path == "status":
+			set.status = true
+		case path == "body":
+			set.body = true
+		case strings.HasPrefix(path, "header:"):
+			set.headers[http.CanonicalHeaderKey(strings.TrimSpace(strings.TrimPrefix(path, "header:")))] = true
+		default:
+			pattern, err := jsonpath.ParsePattern(path)
+			if err != nil {
+				slog.Warn("Skipping invalid ignore path", "path", path, "error", err)
+				continue
+			}
+			set.patterns = append(set.patterns, pattern)
+		}
+	}
+	return set
+}
+
+func (s ignoreSet) ignoresPath(path jsonpath.Path) bool {
+	for _, pattern := range s.patterns {
+		if path.Within(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare a response with the baseline response of its template. The status, headers, and body are compared,
+// JSON bodies structurally, so key order and number formatting do not matter. Differences are returned in a stable order.
+func Compare(base *response.ResponseObject, actual *response.ResponseObject, ignore []string) []response.Difference {
+	set := parseIgnore(append(append([]string(nil), DefaultIgnore...), ignore...))
+	slog.Debug("Comparing response with baseline", "ignoreCount", len(ignore))
+	var differences []response.Difference
+	if !set.status && base.StatusCode != actual.StatusCode {
+		differences = append(differences, response.Difference{
+			Path:     "status",
+			Kind:     KindChanged,
+			Baseline: strconv.Itoa(base.StatusCode),
+			Actual:   strconv.Itoa(actual.StatusCode),
+		})
+	}
+	differences = append(differences, compareHeaders(base.Headers, actual.Headers, set)...)
+	if !set.body {
+		differences = append(differences, compareBody(base.Body, actual.Body, set)...)
+	}
+	slog.Debug("Compared response with baseline", "differenceCount", len(differences))
+	return differences
+}
+
+func compareHeaders(base map[string]string, actual map[string]string, set ignoreSet) []response.Difference {
+	baseHeaders := canonicalHeaders(base)
+	actualHeaders := canonicalHeaders(actual)
+	names := make(map[string]bool)
+	for name := range baseHeaders {
+		names[name] = true
+	}
+	for name := range actualHeaders {
+		names[name] = true
+	}
+	var differences []response.Difference
+	for _, name := range utils.SortedKeys(names) {
+		if set.headers[name] {
+			continue
+		}
+		baseValue, inBase := baseHeaders[name]
+		actualValue, inActual := actualHeaders[name]
+		path := "header:" + name
+		switch {
+		case !inActual:
+			differences = append(differences, response.Difference{Path: path, Kind: KindRemoved, Baseline: baseValue})
+		case !inBase:
+			differences = append(differences, response.Difference{Path: path, Kind: KindAdded, Actual: actualValue})
+		case baseValue != actualValue:
+			differences = append(differences, response.Difference{Path: path, Kind: KindChanged, Baseline: baseValue, Actual: actualValue})
+		}
+	}
+	return differences
+}
+
+// JSON bodies are compared structurally, any other body as text.
+func compareBody(base string, actual string, set ignoreSet) []response.Difference {
+	baseDoc, baseErr := jsonpath.Decode([]byte(base))
+	actualDoc, actualErr := jsonpath.Decode([]byte(actual))
+	if baseErr == nil && actualErr == nil {
+		var differences []response.Difference
+		compareJSON(jsonpath.Path{}, baseDoc, actualDoc, set, &differences)
+		return differences
+	}
+	if base == actual {
+		return nil
+	}
+	return []response.Difference{{Path: "body", Kind: KindChanged, Baseline: base, Actual: actual}}
+}
+
+func compareJSON(path jsonpath.Path, base any, actual any, set ignoreSet, differences *[]response.Difference) {
+	if set.ignoresPath(path) {
+		return
+	}
+	switch b := base.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for key := range b {
+			keys[key] = true
+		}
+		for key := range a {
+			keys[key] = true
+		}
+		for _, key := range utils.SortedKeys(keys) {
+			child := path.Key(key)
+			baseValue, inBase := b[key]
+			actualValue, inActual := a[key]
+			switch {
+			case set.ignoresPath(child):
+			case !inActual:
+				*differences = append(*differences, response.Difference{Path: child.String(), Kind: KindRemoved, Baseline: jsonpath.Format(baseValue)})
+			case !inBase:
+				*differences = append(*differences, response.Difference{Path: child.String(), Kind: KindAdded, Actual: jsonpath.Format(actualValue)})
+			default:
+				compareJSON(child, baseValue, actualValue, set, differences)
+			}
+		}
+		return
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(b), len(a)); i++ {
+			child := path.Index(i)
+			switch {
+			case set.ignoresPath(child):
+			case i >= len(a):
+				*differences = append(*differences, response.Difference{Path: child.String(), Kind: KindRemoved, Baseline: jsonpath.Format(b[i])})
+			case i >= len(b):
+				*differences = append(*differences, response.Difference{Path: child.String(), Kind: KindAdded, Actual: jsonpath.Format(a[i])})
+			default:
+				compareJSON(child, b[i], a[i], set, differences)
+			}
+		}
+		return
+	default:
+		if equalScalar(base, actual) {
+			return
+		}
+	}
+	*differences = append(*differences, response.Difference{Path: path.String(), Kind: KindChanged, Baseline: jsonpath.Format(base), Actual: jsonpath.Format(actual)})
+}
+
+// Numbers are compared by value, so 1 and 1.0 are equal.
+func equalScalar(a any, b any) bool {
+	x, okX := a.(json.Number)
+	y, okY := b.(json.Number)
+	if okX && okY {
+		rx, validX := new(big.Rat).SetString(x.String())
+		ry, validY := new(big.Rat).SetString(y.String())
+		return validX && validY && rx.Cmp(ry) == 0
+	}
+	if okX != okY {
+		return false
+	}
+	return a == b
+}
+
+func canonicalHeaders(headers map[string]string) map[string]string {
+	canonical := make(map[string]string, len(headers))
+	for name, value := range headers {
+		canonical[http.CanonicalHeaderKey(name)] = value
+	}
+	return canonical
+}
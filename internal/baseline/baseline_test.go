@@ -0,0 +1,81 @@
+package baseline
+
+import (
+	"reflect"
+	"reqcorder/internal/response"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	base := &response.ResponseObject{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "application/json", "Date": "Mon, 01 Jan 2025 00:00:00 GMT", "X-Request-Id": "req-1", "X-Old": "1"},
+		Body:       `{"id": 1, "price": 1.50, "created_at": "2025-01-01", "items": [{"id": "a", "ts": 1}, {"id": "b", "ts": 2}], "meta": {"page": 1}}`,
+	}
+	tests := []struct {
+		name          string
+		actual        *response.ResponseObject
+		ignore        []string
+		expectedPaths []string
+		expectedKinds []string
+	}{
+		{
+			name: "Same response with reordered keys and volatile headers",
+			actual: &response.ResponseObject{
+				StatusCode: 200,
+				Headers:    map[string]string{"content-type": "application/json", "Date": "Tue, 02 Jan 2025 00:00:00 GMT", "X-Request-Id": "req-1", "X-Old": "1"},
+				Body:       `{"meta": {"page": 1}, "items": [{"ts": 1, "id": "a"}, {"id": "b", "ts": 2}], "created_at": "2025-01-01", "price": 1.5, "id": 1.0}`,
+			},
+		},
+		{
+			name: "Differences are reported by location",
+			actual: &response.ResponseObject{
+				StatusCode: 201,
+				Headers:    map[string]string{"Content-Type": "application/json", "X-Request-Id": "req-2", "X-New": "1"},
+				Body:       `{"id": "1", "price": 1.5, "created_at": "2025-01-02", "items": [{"id": "a", "ts": 3}], "meta": {"page": 1, "next": 2}}`,
+			},
+			expectedPaths: []string{"status", "header:X-New", "header:X-Old", "header:X-Request-Id", "$.created_at", "$.id", "$.items[0].ts", "$.items[1]", "$.meta.next"},
+			expectedKinds: []string{KindChanged, KindAdded, KindRemoved, KindChanged, KindChanged, KindChanged, KindChanged, KindRemoved, KindAdded},
+		},
+		{
+			name: "Ignored locations",
+			actual: &response.ResponseObject{
+				StatusCode: 201,
+				Headers:    map[string]string{"Content-Type": "application/json", "X-Request-Id": "req-2", "X-Old": "1"},
+				Body:       `{"id": 1, "price": 1.5, "created_at": "2025-01-02", "items": [{"id": "a", "ts": 3}, {"id": "b", "ts": 4}], "meta": {"page": 2}}`,
+			},
+			ignore:        []string{"status", "header:x-request-id", "$.created_at", "$.items[*].ts", "$.meta"},
+			expectedPaths: nil,
+		},
+		{
+			name:          "Text bodies",
+			actual:        &response.ResponseObject{StatusCode: 200, Headers: base.Headers, Body: "plain text"},
+			expectedPaths: []string{"body"},
+			expectedKinds: []string{KindChanged},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			differences := Compare(base, tt.actual, tt.ignore)
+			var paths, kinds []string
+			for _, difference := range differences {
+				paths = append(paths, difference.Path)
+				kinds = append(kinds, difference.Kind)
+			}
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Fatalf("Expected differences at %v, received %+v", tt.expectedPaths, differences)
+			}
+			if !reflect.DeepEqual(kinds, tt.expectedKinds) {
+				t.Errorf("Expected kinds %v, received %v", tt.expectedKinds, kinds)
+			}
+		})
+	}
+}
+
+func TestCompare_IgnoreBody(t *testing.T) {
+	base := &response.ResponseObject{StatusCode: 200, Body: "a"}
+	actual := &response.ResponseObject{StatusCode: 200, Body: "b"}
+	if differences := Compare(base, actual, []string{"body"}); len(differences) != 0 {
+		t.Errorf("Expected no differences, received %+v", differences)
+	}
+}
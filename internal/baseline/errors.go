@@ -0,0 +1,7 @@
+package baseline
+
+import "errors"
+
+var (
+	ErrorBaselineMismatch = errors.New("response differs from baseline")
+)
@@ -0,0 +1,149 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/jsonpath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Failure is a capture that could not be extracted from the response.
+type Failure struct {
+	Name   string
+	Source string
+	Reason string
+}
+
+// CaptureError lists every capture that failed for a response.
+type CaptureError struct {
+	Failures []Failure
+}
+
+func (e *CaptureError) Error() string {
+	failures := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		failures[i] = fmt.Sprintf("%s (%s): %s", f.Name, f.Source, f.Reason)
+	}
+	return fmt.Sprintf("%v: %s", ErrorCaptureFailed, strings.Join(failures, "; "))
+}
+
+func (e *CaptureError) Unwrap() error {
+	return ErrorCaptureFailed
+}
+
+// Describe each failed capture on its own line.
+func (e *CaptureError) Describe() string {
+	var sb strings.Builder
+	for _, f := range e.Failures {
+		sb.WriteString(fmt.Sprintf("  %s (%s): %s\n", f.Name, f.Source, f.Reason))
+	}
+	return sb.String()
+}
+
+// Extract the captures of a request from its response.
+// Values that could be extracted are returned even when others fail, so they can still be recorded.
+func Extract(captures map[string]request.Capture, res *response.ResponseObject) (map[string]string, error) {
+	if len(captures) == 0 {
+		return nil, nil
+	}
+	slog.Debug("Extracting captures", "captureCount", len(captures))
+	names := make([]string, 0, len(captures))
+	for name := range captures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make(map[string]string, len(captures))
+	var failures []Failure
+	var body any
+	var bodyErr error
+	bodyDecoded := false
+	for _, name := range names {
+		c := captures[name]
+		var value string
+		var err error
+		switch {
+		case c.JSON != "":
+			if !bodyDecoded {
+				body, bodyErr = jsonpath.Decode([]byte(res.Body))
+				bodyDecoded = true
+			}
+			if bodyErr != nil {
+				err = fmt.Errorf("response body is not JSON: %v", bodyErr)
+				break
+			}
+			value, err = extractJSON(c.JSON, body)
+		case c.Header != "":
+			value, err = extractHeader(c.Header, res)
+		case c.Cookie != "":
+			value, err = extractCookie(c.Cookie, res.Cookies)
+		case c.Status:
+			value = strconv.Itoa(res.StatusCode)
+		case c.Regex != "":
+			value, err = extractRegex(c.Regex, res.Body)
+		default:
+			err = errors.New("no source set")
+		}
+		if err != nil {
+			slog.Error("Failed to capture value", "name", name, "source", c.Source(), "error", err)
+			failures = append(failures, Failure{Name: name, Source: c.Source(), Reason: err.Error()})
+			continue
+		}
+		slog.Debug("Captured value", "name", name, "source", c.Source())
+		values[name] = value
+	}
+	if len(failures) > 0 {
+		return values, &CaptureError{Failures: failures}
+	}
+	return values, nil
+}
+
+func extractJSON(expression string, body any) (string, error) {
+	path, err := jsonpath.Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	value, err := path.Evaluate(body)
+	if err != nil {
+		return "", err
+	}
+	return jsonpath.Format(value), nil
+}
+
+func extractHeader(name string, res *response.ResponseObject) (string, error) {
+	if value, exists := res.Header(name); exists {
+		return value, nil
+	}
+	return "", fmt.Errorf("header %q not found in response", name)
+}
+
+func extractCookie(name string, cookies []*http.Cookie) (string, error) {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie.Value, nil
+		}
+	}
+	return "", fmt.Errorf("cookie %q not set by response", name)
+}
+
+// The first group is captured when the expression has one, otherwise the whole match.
+func extractRegex(expression string, body string) (string, error) {
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return "", err
+	}
+	match := re.FindStringSubmatch(body)
+	if match == nil {
+		return "", errors.New("no match in response body")
+	}
+	if len(match) > 1 {
+		return match[1], nil
+	}
+	return match[0], nil
+}
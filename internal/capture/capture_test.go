@@ -0,0 +1,106 @@
+package capture
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	res := &response.ResponseObject{
+		StatusCode: 201,
+		Headers:    map[string]string{"X-Session": "sess-9"},
+		Body:       `{"data": {"token": "tok-123", "id": 42, "price": 1.50, "active": true, "items": [{"sku": "a"}, {"sku": "b"}], "user-name": "jane"}}`,
+		Cookies:    []*http.Cookie{{Name: "sid", Value: "abc"}},
+	}
+	tests := []struct {
+		name             string
+		captures         map[string]request.Capture
+		expectedValues   map[string]string
+		expectedFailures []string
+	}{
+		{
+			name: "Every source",
+			captures: map[string]request.Capture{
+				"token":   {JSON: "$.data.token"},
+				"id":      {JSON: "$.data.id"},
+				"price":   {JSON: "$.data.price"},
+				"active":  {JSON: "$.data.active"},
+				"last":    {JSON: "$.data.items[-1].sku"},
+				"first":   {JSON: "$.data.items[0]"},
+				"user":    {JSON: "$.data['user-name']"},
+				"session": {Header: "x-session"},
+				"sid":     {Cookie: "sid"},
+				"status":  {Status: true},
+				"quoted":  {Regex: `"token": "([^"]+)"`},
+				"whole":   {Regex: `tok-\d+`},
+			},
+			expectedValues: map[string]string{
+				"token":   "tok-123",
+				"id":      "42",
+				"price":   "1.50",
+				"active":  "true",
+				"last":    "b",
+				"first":   `{"sku":"a"}`,
+				"user":    "jane",
+				"session": "sess-9",
+				"sid":     "abc",
+				"status":  "201",
+				"quoted":  "tok-123",
+				"whole":   "tok-123",
+			},
+		},
+		{
+			name: "Failures keep extracted values",
+			captures: map[string]request.Capture{
+				"token":   {JSON: "$.data.token"},
+				"missing": {JSON: "$.data.missing"},
+				"index":   {JSON: "$.data.items[5]"},
+				"header":  {Header: "X-Missing"},
+				"cookie":  {Cookie: "missing"},
+				"regex":   {Regex: `order-(\d+)`},
+			},
+			expectedValues:   map[string]string{"token": "tok-123"},
+			expectedFailures: []string{"cookie", "header", "index", "missing", "regex"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Extract(tt.captures, res)
+			if !reflect.DeepEqual(values, tt.expectedValues) {
+				t.Errorf("Expected values %v, received %v", tt.expectedValues, values)
+			}
+			if len(tt.expectedFailures) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error, received %v", err)
+				}
+				return
+			}
+			var captureErr *CaptureError
+			if !errors.As(err, &captureErr) || !errors.Is(err, ErrorCaptureFailed) {
+				t.Fatalf("Expected capture error, received %v", err)
+			}
+			var names []string
+			for _, failure := range captureErr.Failures {
+				names = append(names, failure.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expectedFailures) {
+				t.Errorf("Expected failures %v, received %v", tt.expectedFailures, names)
+			}
+		})
+	}
+}
+
+func TestExtract_NonJSONBody(t *testing.T) {
+	res := &response.ResponseObject{StatusCode: 200, Body: "plain text"}
+	values, err := Extract(map[string]request.Capture{"token": {JSON: "$.token"}, "status": {Status: true}}, res)
+	if !errors.Is(err, ErrorCaptureFailed) {
+		t.Fatalf("Expected %v, received %v", ErrorCaptureFailed, err)
+	}
+	if !reflect.DeepEqual(values, map[string]string{"status": "200"}) {
+		t.Errorf("Expected status to be captured, received %v", values)
+	}
+}
@@ -0,0 +1,7 @@
+package capture
+
+import "errors"
+
+var (
+	ErrorCaptureFailed = errors.New("failed to capture response values")
+)
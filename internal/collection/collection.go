@@ -0,0 +1,194 @@
+package collection
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reqcorder/internal/request"
+	"reqcorder/pkg/utils"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Read a collection file. The returned YAML is the file content, which is what gets hashed and recorded.
+func LoadCollection(path string) (*CollectionObject, []byte, error) {
+	slog.Debug("Loading collection", "collectionPath", path)
+	content, err := utils.ReadFile(path)
+	if err != nil {
+		slog.Error("Failed to read collection file", "collectionPath", path, "error", err)
+		return nil, nil, err
+	}
+	var c CollectionObject
+	err = yaml.Unmarshal(content, &c)
+	if err != nil {
+		slog.Error("Failed to decode collection", "collectionPath", path, "error", err)
+		return nil, nil, fmt.Errorf("%w %q: %w", ErrorInvalidCollection, path, err)
+	}
+	if len(c.Requests) == 0 {
+		return nil, nil, fmt.Errorf("%w %q: no requests defined", ErrorInvalidCollection, path)
+	}
+	c.Path = path
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for i := range c.Requests {
+		item := &c.Requests[i]
+		if item.Name != "" {
+			continue
+		}
+		if item.Template != "" {
+			item.Name = strings.TrimSuffix(filepath.Base(item.Template), filepath.Ext(item.Template))
+		} else {
+			item.Name = fmt.Sprintf("request %d", i+1)
+		}
+	}
+	slog.Debug("Collection loaded", slog.Any("collection", &c))
+	return &c, content, nil
+}
+
+// Report whether a file holds a collection rather than a template, which is the case when it lists requests.
+func IsCollectionFile(path string) (bool, error) {
+	content, err := utils.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false, nil
+	}
+	_, exists := doc["requests"]
+	return exists, nil
+}
+
+// Build the request for an item with the collection defaults merged under it.
+// Template paths and relative paths of inline templates are resolved against the collection file.
+func (c *CollectionObject) BuildRequest(item CollectionItem) (*request.RequestObject, []byte, error) {
+	slog.Debug("Building collection request", "name", item.Name)
+	if item.Template != "" {
+		templatePath := c.ResolveTemplatePath(item)
+		return request.LoadTemplateWithDefaults(templatePath, c.Defaults)
+	}
+	return request.BuildTemplate(c.Path, item.Inline, c.Defaults)
+}
+
+// Check the defaults and requests of the collection against the template schema. Issues of the defaults and of inline
+// templates are reported at their position in the collection file. Keys set in the defaults count towards the required
+// keys of every request, since they are merged under it.
+func (c *CollectionObject) Check() ([]request.TemplateIssue, error) {
+	slog.Debug("Checking collection", "collectionPath", c.Path)
+	var defaults ast.Node
+	var requests *ast.SequenceNode
+	if c.Defaults != nil || c.hasInline() {
+		content, err := utils.ReadFile(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseBytes(content, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrorInvalidCollection, c.Path, err)
+		}
+		var pairs []*ast.MappingValueNode
+		if len(file.Docs) > 0 {
+			switch root := file.Docs[0].Body.(type) {
+			case *ast.MappingNode:
+				pairs = root.Values
+			case *ast.MappingValueNode:
+				pairs = []*ast.MappingValueNode{root}
+			}
+		}
+		for _, pair := range pairs {
+			switch pair.Key.String() {
+			case "defaults":
+				defaults = pair.Value
+			case "requests":
+				requests, _ = pair.Value.(*ast.SequenceNode)
+			}
+		}
+		if requests == nil || len(requests.Values) != len(c.Requests) {
+			return nil, fmt.Errorf("%w %q: requests must be a sequence", ErrorInvalidCollection, c.Path)
+		}
+	}
+	var issues []request.TemplateIssue
+	if defaults != nil {
+		issues = append(issues, request.CheckTemplateDefaults(c.Path, defaults)...)
+	}
+	for i, item := range c.Requests {
+		if item.Inline != nil {
+			issues = append(issues, request.CheckInlineTemplate(c.Path, requests.Values[i], []string{"name"}, c.Defaults)...)
+			continue
+		}
+		templateIssues, err := request.CheckTemplateWithDefaults(c.ResolveTemplatePath(item), c.Defaults)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, templateIssues...)
+	}
+	return issues, nil
+}
+
+// Report whether any request of the collection is an inline template.
+func (c *CollectionObject) hasInline() bool {
+	for _, item := range c.Requests {
+		if item.Inline != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve the template path of an item relative to the collection file.
+func (c *CollectionObject) ResolveTemplatePath(item CollectionItem) string {
+	if item.Template == "" || filepath.IsAbs(item.Template) {
+		return item.Template
+	}
+	return filepath.Join(filepath.Dir(c.Path), item.Template)
+}
+
+// Decode an item written either as a template path or as a mapping with a name and a template path or inline template keys.
+func (i *CollectionItem) UnmarshalYAML(unmarshal func(any) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		i.Template = path
+		return nil
+	}
+	var doc map[string]any
+	if err := unmarshal(&doc); err != nil {
+		return fmt.Errorf("%w: a request must be a template path or a mapping", ErrorInvalidCollection)
+	}
+	if name, exists := doc["name"]; exists {
+		i.Name = fmt.Sprint(name)
+		delete(doc, "name")
+	}
+	if template, exists := doc["template"]; exists {
+		path, ok := template.(string)
+		if !ok || path == "" {
+			return fmt.Errorf("%w: template of %q must be a file path", ErrorInvalidCollection, i.Name)
+		}
+		delete(doc, "template")
+		if len(doc) > 0 {
+			return fmt.Errorf("%w: %q sets template together with inline keys, use one or the other", ErrorInvalidCollection, i.Name)
+		}
+		i.Template = path
+		return nil
+	}
+	if len(doc) == 0 {
+		return fmt.Errorf("%w: %q defines neither a template nor inline keys", ErrorInvalidCollection, i.Name)
+	}
+	i.Inline = doc
+	return nil
+}
+
+// Summarize results into passed and failed counts.
+func (r *RunObject) Tally() {
+	r.Passed, r.Failed = 0, 0
+	for _, result := range r.Results {
+		if result.Passed {
+			r.Passed++
+		} else {
+			r.Failed++
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package collection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	err := os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write %q: %v", path, err)
+	}
+}
+
+func TestLoadCollection(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedName  string
+		expectedItems []CollectionItem
+		expectedErr   error
+	}{
+		{
+			name:         "Template paths and inline templates",
+			content:      "name: smoke\nrequests:\n  - get.yaml\n  - name: login\n    template: auth/login.yaml\n  - name: health\n    url: http://localhost/health\n    method: GET\n",
+			expectedName: "smoke",
+			expectedItems: []CollectionItem{
+				{Name: "get", Template: "get.yaml"},
+				{Name: "login", Template: "auth/login.yaml"},
+				{Name: "health", Inline: map[string]any{"url": "http://localhost/health", "method": "GET"}},
+			},
+		},
+		{
+			name:         "Default names from file and position",
+			content:      "requests:\n  - url: http://localhost\n    method: GET\n",
+			expectedName: "collection",
+			expectedItems: []CollectionItem{
+				{Name: "request 1", Inline: map[string]any{"url": "http://localhost", "method": "GET"}},
+			},
+		},
+		{
+			name:        "No requests",
+			content:     "name: empty\n",
+			expectedErr: ErrorInvalidCollection,
+		},
+		{
+			name:        "Template with inline keys",
+			content:     "requests:\n  - name: mixed\n    template: get.yaml\n    method: POST\n",
+			expectedErr: ErrorInvalidCollection,
+		},
+		{
+			name:        "Name only",
+			content:     "requests:\n  - name: nothing\n",
+			expectedErr: ErrorInvalidCollection,
+		},
+		{
+			name:        "Non-string template",
+			content:     "requests:\n  - template: 42\n",
+			expectedErr: ErrorInvalidCollection,
+		},
+		{
+			name:        "Sequence item",
+			content:     "requests:\n  - [get.yaml]\n",
+			expectedErr: ErrorInvalidCollection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "collection.yaml")
+			writeFile(t, path, tt.content)
+			c, content, err := LoadCollection(path)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("Expected content %q, received %q", tt.content, content)
+			}
+			if c.Name != tt.expectedName {
+				t.Errorf("Expected name %q, received %q", tt.expectedName, c.Name)
+			}
+			if !reflect.DeepEqual(c.Requests, tt.expectedItems) {
+				t.Errorf("Expected items %+v, received %+v", tt.expectedItems, c.Requests)
+			}
+		})
+	}
+}
+
+func TestBuildRequest(t *testing.T) {
+	dir := t.TempDir()
+	err := os.Mkdir(filepath.Join(dir, "requests"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to create requests directory: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "requests", "get.yaml"), "url: http://localhost/items\nmethod: GET\nheaders:\n  Accept: text/plain\n")
+	collectionPath := filepath.Join(dir, "collection.yaml")
+	writeFile(t, collectionPath, `defaults:
+  timeout: 5
+  headers:
+    Accept: application/json
+    X-Suite: smoke
+requests:
+  - requests/get.yaml
+  - name: create
+    url: http://localhost/items
+    method: POST
+`)
+	c, _, err := LoadCollection(collectionPath)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+
+	req, templateYaml, err := c.BuildRequest(c.Requests[0])
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if req.TimeoutSeconds != 5 || req.Headers["Accept"] != "text/plain" || req.Headers["X-Suite"] != "smoke" {
+		t.Errorf("Expected defaults merged under the template, received timeout %v and headers %v", req.TimeoutSeconds, req.Headers)
+	}
+	if req.TemplateDir != filepath.Join(dir, "requests") {
+		t.Errorf("Expected template dir %q, received %q", filepath.Join(dir, "requests"), req.TemplateDir)
+	}
+	if !strings.Contains(string(templateYaml), "X-Suite: smoke") {
+		t.Errorf("Expected recorded template to include defaults, received %q", templateYaml)
+	}
+
+	req, _, err = c.BuildRequest(c.Requests[1])
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if req.Method != "POST" || req.TimeoutSeconds != 5 || req.Headers["Accept"] != "application/json" {
+		t.Errorf("Expected inline template with defaults, received method %q, timeout %v and headers %v", req.Method, req.TimeoutSeconds, req.Headers)
+	}
+	if req.TemplateDir != dir {
+		t.Errorf("Expected template dir %q, received %q", dir, req.TemplateDir)
+	}
+}
+
+func TestBuildRequest_InlineExtendsTwice(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), "url: http://localhost/items\nmethod: GET\nheaders:\n  Accept: application/json\n")
+	collectionPath := filepath.Join(dir, "collection.yaml")
+	writeFile(t, collectionPath, `requests:
+  - name: list
+    extends: base.yaml
+    headers:
+      X-Suite: smoke
+`)
+	c, _, err := LoadCollection(collectionPath)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+
+	first, firstYaml, err := c.BuildRequest(c.Requests[0])
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	second, secondYaml, err := c.BuildRequest(c.Requests[0])
+	if err != nil {
+		t.Fatalf("Expected no error on second build, received %v", err)
+	}
+	if first.URL != "http://localhost/items" || first.Headers["X-Suite"] != "smoke" {
+		t.Errorf("Expected inline template merged over its base, received url %q and headers %v", first.URL, first.Headers)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected equal requests, received %+v and %+v", first, second)
+	}
+	if string(firstYaml) != string(secondYaml) {
+		t.Errorf("Expected equal template YAML, received %q and %q", firstYaml, secondYaml)
+	}
+	if _, exists := c.Requests[0].Inline["extends"]; !exists {
+		t.Errorf("Expected inline item to keep extends, received %v", c.Requests[0].Inline)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		expectedIssues []string
+	}{
+		{
+			name:    "Valid inline templates and template paths",
+			content: "requests:\n  - get.yaml\n  - name: health\n    url: http://localhost/health\n    method: GET\n",
+		},
+		{
+			name:    "Unknown keys and types",
+			content: "requests:\n  - name: create\n    url: http://localhost/items\n    method: POST\n    heders:\n      a: b\n    timeout: fast\n",
+			expectedIssues: []string{
+				`collection.yaml:5:5: unknown key "heders", did you mean "headers"?`,
+				`collection.yaml:7:14: timeout must be a number`,
+			},
+		},
+		{
+			name:           "Missing required keys",
+			content:        "requests:\n  - name: partial\n    url: http://localhost\n",
+			expectedIssues: []string{`collection.yaml:2:5: missing required key "method"`},
+		},
+		{
+			name:    "Defaults provide required keys",
+			content: "defaults:\n  method: GET\nrequests:\n  - url: http://localhost\n",
+		},
+		{
+			name:    "Defaults provide required keys of template files",
+			content: "defaults:\n  method: GET\nrequests:\n  - url_only.yaml\n",
+		},
+		{
+			name:           "Template files without defaults",
+			content:        "requests:\n  - url_only.yaml\n",
+			expectedIssues: []string{`url_only.yaml:1:1: missing required key "method"`},
+		},
+		{
+			name:    "Defaults are checked",
+			content: "defaults:\n  timeout: soon\n  heders:\n    a: b\nrequests:\n  - get.yaml\n",
+			expectedIssues: []string{
+				`collection.yaml:2:12: timeout must be a number`,
+				`collection.yaml:3:3: unknown key "heders", did you mean "headers"?`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "get.yaml"), "url: http://localhost/items\nmethod: GET\n")
+			writeFile(t, filepath.Join(dir, "url_only.yaml"), "url: http://localhost/items\n")
+			path := filepath.Join(dir, "collection.yaml")
+			writeFile(t, path, tt.content)
+			c, _, err := LoadCollection(path)
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			issues, err := c.Check()
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if len(issues) != len(tt.expectedIssues) {
+				t.Fatalf("Expected %d issues, received %v", len(tt.expectedIssues), issues)
+			}
+			for i, issue := range issues {
+				expected := filepath.Join(dir, tt.expectedIssues[i])
+				if !strings.HasPrefix(issue.String(), expected) {
+					t.Errorf("Expected issue %q, received %q", expected, issue.String())
+				}
+			}
+		})
+	}
+}
+
+func TestTally(t *testing.T) {
+	run := RunObject{
+		Passed: 5,
+		Results: []RunResult{
+			{Name: "first", Passed: true, Duration: time.Second},
+			{Name: "second", Error: "request failed"},
+			{Name: "third", Passed: true},
+		},
+	}
+	run.Tally()
+	if run.Passed != 2 || run.Failed != 1 {
+		t.Fatalf("Expected 2 passed and 1 failed, received %d passed and %d failed", run.Passed, run.Failed)
+	}
+}
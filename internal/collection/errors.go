@@ -0,0 +1,8 @@
+package collection
+
+import "errors"
+
+var (
+	ErrorInvalidCollection = errors.New("invalid collection")
+	ErrorRunFailed         = errors.New("collection run failed")
+)
@@ -0,0 +1,31 @@
+package collection
+
+import "log/slog"
+
+// Helper function to log pointers to CollectionObject.
+func (c *CollectionObject) LogValue() slog.Value {
+	if c == nil {
+		return slog.StringValue("<nil>")
+	}
+	return slog.GroupValue(
+		slog.String("name", c.Name),
+		slog.String("path", c.Path),
+		slog.Int("defaultCount", len(c.Defaults)),
+		slog.Int("requestCount", len(c.Requests)),
+	)
+}
+
+// Helper function to log RunResult.
+func (r RunResult) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", r.Name),
+		slog.String("method", r.Method),
+		slog.String("url", r.URL),
+		slog.String("requestHash", r.RequestHash),
+		slog.String("responseId", r.ResponseID),
+		slog.Int("statusCode", r.StatusCode),
+		slog.Duration("duration", r.Duration),
+		slog.Bool("passed", r.Passed),
+		slog.String("error", r.Error),
+	)
+}
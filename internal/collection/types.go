@@ -0,0 +1,55 @@
+package collection
+
+import (
+	"reqcorder/internal/response"
+	"time"
+)
+
+// CollectionObject is a named list of requests that run in order and share default template keys.
+type CollectionObject struct {
+	Name     string           `yaml:"name,omitempty"`
+	Defaults map[string]any   `yaml:"defaults,omitempty"`
+	Requests []CollectionItem `yaml:"requests"`
+	Path     string           `yaml:"-"`
+}
+
+// CollectionItem is a request in a collection, either a path to a template file or an inline template.
+type CollectionItem struct {
+	Name     string
+	Template string
+	Inline   map[string]any
+}
+
+// RunObject is the recorded outcome of running a collection.
+type RunObject struct {
+	Collection     string        `yaml:"collection"`
+	CollectionPath string        `yaml:"collection_path"`
+	CollectionHash string        `yaml:"collection_hash"`
+	Environment    string        `yaml:"environment,omitempty"`
+	StartedAt      time.Time     `yaml:"started_at"`
+	Duration       time.Duration `yaml:"duration"`
+	Passed         int           `yaml:"passed"`
+	Failed         int           `yaml:"failed"`
+	Results        []RunResult   `yaml:"results"`
+}
+
+// RunResult is the outcome of a single request in a collection run.
+type RunResult struct {
+	Name         string            `yaml:"name"`
+	Method       string            `yaml:"method,omitempty"`
+	URL          string            `yaml:"url,omitempty"`
+	TemplateHash string            `yaml:"template_hash,omitempty"`
+	RequestHash  string            `yaml:"request_hash,omitempty"`
+	ResponseID   string            `yaml:"response_id,omitempty"`
+	StatusCode   int               `yaml:"status_code,omitempty"`
+	Duration     time.Duration     `yaml:"duration,omitempty"`
+	Captured     map[string]string `yaml:"captured,omitempty"`
+	// Number of assertions evaluated for the response.
+	AssertionCount int `yaml:"assertion_count,omitempty"`
+	// Assertions of the expect block that did not pass.
+	FailedAssertions []response.Assertion `yaml:"failed_assertions,omitempty"`
+	// Locations where the body does not match the JSON Schema of the expect block.
+	SchemaViolations []response.SchemaViolation `yaml:"schema_violations,omitempty"`
+	Passed           bool                       `yaml:"passed"`
+	Error            string                     `yaml:"error,omitempty"`
+}
@@ -0,0 +1,475 @@
+package curl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"reqcorder/internal/request"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// templateComment heads every imported template, so they are told apart from written ones.
+const templateComment = "# Imported by reqcorder from a curl command\n"
+
+// Long options and the short option they stand for. Options without a short form map to themselves.
+var optionAliases = map[string]string{
+	"--request":        "-X",
+	"--header":         "-H",
+	"--data":           "-d",
+	"--data-ascii":     "-d",
+	"--data-raw":       "--data-raw",
+	"--data-binary":    "--data-binary",
+	"--user":           "-u",
+	"--cookie":         "-b",
+	"--user-agent":     "-A",
+	"--referer":        "-e",
+	"--url":            "--url",
+	"--cacert":         "--cacert",
+	"--insecure":       "-k",
+	"--compressed":     "--compressed",
+	"--head":           "-I",
+	"--silent":         "-s",
+	"--show-error":     "-S",
+	"--include":        "-i",
+	"--verbose":        "-v",
+	"--location":       "-L",
+	"--fail":           "-f",
+	"--http1.1":        "--http1.1",
+	"--http2":          "--http2",
+	"--no-buffer":      "-N",
+	"--progress-bar":   "-#",
+	"--globoff":        "-g",
+	"--path-as-is":     "--path-as-is",
+	"--no-keepalive":   "--no-keepalive",
+	"--tr-encoding":    "--tr-encoding",
+	"--fail-with-body": "--fail-with-body",
+}
+
+// Options that take a value.
+var valueOptions = map[string]bool{"-X": true, "-H": true, "-d": true, "--data-raw": true, "--data-binary": true, "-u": true, "-b": true, "-A": true, "-e": true, "--url": true, "--cacert": true}
+
+// Options that only change how curl reports the exchange. They have no place in a template and are skipped.
+var ignoredOptions = map[string]bool{"-s": true, "-S": true, "-i": true, "-v": true, "-L": true, "-f": true, "-N": true, "-#": true, "-g": true, "--http1.1": true, "--http2": true, "--path-as-is": true, "--no-keepalive": true, "--tr-encoding": true, "--fail-with-body": true}
+
+// command collects the options of a curl command line.
+type command struct {
+	url        string
+	method     string
+	headers    []string
+	data       []string
+	dataFiles  []string
+	user       string
+	cookies    []string
+	agent      string
+	cacert     string
+	insecure   bool
+	compressed bool
+	head       bool
+}
+
+// Parse a curl command line, as copied from browser developer tools or documentation, into a template. Relative file
+// paths are made absolute, since templates resolve them against their own directory.
+func Parse(commandLine string) (*Template, error) {
+	slog.Debug("Parsing curl command", "length", len(commandLine))
+	words, err := splitWords(commandLine)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 && (words[0] == "curl" || strings.HasSuffix(words[0], "/curl") || strings.EqualFold(words[0], "curl.exe")) {
+		words = words[1:]
+	}
+	var c command
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		// Return the value of an option, attached to it or in the next word.
+		nextValue := func(option string, attached string) (string, error) {
+			if attached != "" {
+				return attached, nil
+			}
+			if i+1 >= len(words) {
+				return "", fmt.Errorf("%w: option %s requires a value", ErrorInvalidCommand, option)
+			}
+			i++
+			return words[i], nil
+		}
+		switch {
+		case !strings.HasPrefix(word, "-") || word == "-":
+			err = c.apply("--url", word)
+		case strings.HasPrefix(word, "--"):
+			option, known := optionAliases[word]
+			if !known {
+				return nil, fmt.Errorf("%w %q", ErrorUnsupportedOption, word)
+			}
+			value := ""
+			if valueOptions[option] {
+				value, err = nextValue(word, "")
+				if err != nil {
+					return nil, err
+				}
+			}
+			err = c.apply(option, value)
+		default:
+			// Short options can be grouped, as in -sSL, and take their value attached, as in -XPOST.
+			for j := 1; j < len(word) && err == nil; j++ {
+				option := "-" + word[j:j+1]
+				if !isShortOption(option) {
+					return nil, fmt.Errorf("%w %q", ErrorUnsupportedOption, option)
+				}
+				if !valueOptions[option] {
+					err = c.apply(option, "")
+					continue
+				}
+				var value string
+				value, err = nextValue(option, word[j+1:])
+				if err == nil {
+					err = c.apply(option, value)
+				}
+				break
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.template()
+}
+
+// Report whether option is a supported short option.
+func isShortOption(option string) bool {
+	for _, short := range optionAliases {
+		if short == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Record a single option.
+func (c *command) apply(option string, value string) error {
+	switch option {
+	case "--url":
+		if c.url != "" {
+			return fmt.Errorf("%w: more than one URL, %q and %q", ErrorInvalidCommand, c.url, value)
+		}
+		c.url = value
+	case "-X":
+		c.method = strings.ToUpper(value)
+	case "-H":
+		c.headers = append(c.headers, value)
+	case "-e":
+		c.headers = append(c.headers, "Referer: "+value)
+	case "-d", "--data-binary":
+		if name, isFile := strings.CutPrefix(value, "@"); isFile {
+			if name == "-" {
+				return fmt.Errorf("%w: reading data from stdin", ErrorUnsupportedOption)
+			}
+			c.dataFiles = append(c.dataFiles, name)
+			return nil
+		}
+		c.data = append(c.data, value)
+	case "--data-raw":
+		c.data = append(c.data, value)
+	case "-u":
+		c.user = value
+	case "-b":
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("%w: reading cookies from file %q", ErrorUnsupportedOption, value)
+		}
+		c.cookies = append(c.cookies, value)
+	case "-A":
+		c.agent = value
+	case "--cacert":
+		c.cacert = value
+	case "-k":
+		c.insecure = true
+	case "--compressed":
+		c.compressed = true
+	case "-I":
+		c.head = true
+	default:
+		if !ignoredOptions[option] {
+			return fmt.Errorf("%w %q", ErrorUnsupportedOption, option)
+		}
+		slog.Debug("Skipping curl output option", "option", option)
+	}
+	return nil
+}
+
+// Build the template sending the same request as the command.
+func (c *command) template() (*Template, error) {
+	if c.url == "" {
+		return nil, fmt.Errorf("%w: no URL", ErrorInvalidCommand)
+	}
+	t := &Template{URL: c.url}
+	if !strings.Contains(t.URL, "://") {
+		t.URL = "http://" + t.URL
+	}
+	parsedURL, err := url.Parse(t.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid URL %q: %v", ErrorInvalidCommand, c.url, err)
+	}
+	if query, err := url.ParseQuery(parsedURL.RawQuery); err == nil && len(query) > 0 {
+		t.Query = make(map[string]request.MultiValue, len(query))
+		for key, values := range query {
+			t.Query[key] = values
+		}
+		parsedURL.RawQuery = ""
+		t.URL = parsedURL.String()
+	}
+
+	switch {
+	case len(c.dataFiles) > 0 && len(c.data)+len(c.dataFiles) > 1:
+		return nil, fmt.Errorf("%w: combining data from a file with other data", ErrorUnsupportedOption)
+	case len(c.dataFiles) > 0:
+		t.BodyFile, err = filepath.Abs(c.dataFiles[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorInvalidCommand, err)
+		}
+	default:
+		// Like curl, separate data passed several times with &.
+		t.Body = strings.Join(c.data, "&")
+	}
+	hasData := len(c.data) > 0 || len(c.dataFiles) > 0
+	switch {
+	case c.method != "":
+		t.Method = c.method
+	case c.head:
+		t.Method = "HEAD"
+	case hasData:
+		t.Method = "POST"
+	default:
+		t.Method = "GET"
+	}
+
+	hasContentType, hasAuthorization := false, false
+	for _, line := range c.headers {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			// curl sends "Name;" as a header without value.
+			name, found = strings.CutSuffix(strings.TrimSpace(line), ";")
+			if !found {
+				return nil, fmt.Errorf("%w: invalid header %q", ErrorInvalidCommand, line)
+			}
+		} else if value = strings.TrimSpace(value); value == "" {
+			// curl drops the header instead of sending it empty.
+			continue
+		}
+		name = strings.TrimSpace(name)
+		switch strings.ToLower(name) {
+		case "authorization":
+			hasAuthorization = true
+			scheme, credentials, _ := strings.Cut(value, " ")
+			switch strings.ToLower(scheme) {
+			case "bearer":
+				t.Auth, t.AuthType = strings.TrimSpace(credentials), "bearer"
+			case "basic":
+				t.Auth, t.AuthType = strings.TrimSpace(credentials), "basic"
+			default:
+				t.setHeader(name, value)
+			}
+		case "cookie":
+			c.cookies = append(c.cookies, value)
+		case "user-agent":
+			if c.agent == "" {
+				t.UserAgent = value
+			}
+		case "accept-encoding":
+			// With --compressed, the client negotiates compression itself and decodes the body.
+			if !c.compressed {
+				t.setHeader(name, value)
+			}
+		case "content-type":
+			hasContentType = true
+			t.setHeader(name, value)
+		default:
+			t.setHeader(name, value)
+		}
+	}
+	if hasData && !hasContentType {
+		// curl sends data as a form unless told otherwise.
+		t.setHeader("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.user != "" && !hasAuthorization {
+		t.Auth, t.AuthType = basicCredentials(c.user), "basic"
+	}
+	for _, cookies := range c.cookies {
+		for _, pair := range strings.Split(cookies, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			if name == "" {
+				continue
+			}
+			if t.Cookies == nil {
+				t.Cookies = make(map[string]string)
+			}
+			t.Cookies[name] = value
+		}
+	}
+	if c.agent != "" {
+		t.UserAgent = c.agent
+	}
+	if c.insecure {
+		verify := false
+		t.SSLVerify = &verify
+	}
+	if c.cacert != "" {
+		t.CACertPath, err = filepath.Abs(c.cacert)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorInvalidCommand, err)
+		}
+	}
+	slog.Debug("Parsed curl command", "method", t.Method, "url", t.URL, "headerCount", len(t.Headers), "cookieCount", len(t.Cookies))
+	return t, nil
+}
+
+// Set a header, creating the map on first use.
+func (t *Template) setHeader(name string, value string) {
+	if t.Headers == nil {
+		t.Headers = make(map[string]string)
+	}
+	t.Headers[name] = value
+}
+
+// Return the user and password of -u as basic credentials. They are left readable with the base64 template function
+// unless they contain a brace, which the function argument cannot hold.
+func basicCredentials(user string) string {
+	if !strings.Contains(user, ":") {
+		user += ":"
+	}
+	if strings.ContainsAny(user, "{}") {
+		return base64.StdEncoding.EncodeToString([]byte(user))
+	}
+	return "{{base64:" + user + "}}"
+}
+
+// Return the template as YAML, headed by a comment naming its origin.
+func (t *Template) Marshal() ([]byte, error) {
+	templateYaml, err := utils.ConvertToYAML(t)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(templateComment), templateYaml...), nil
+}
+
+// Split a command line into words like a POSIX shell: quotes group words, backslashes escape characters, and
+// backslash-newline continues a line. ANSI-C quoting ($'...'), used by browsers to copy bodies, is decoded.
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case ch == '\\':
+			if i+1 < len(line) {
+				i++
+				if line[i] == '\r' && i+1 < len(line) && line[i+1] == '\n' {
+					i++
+				}
+				if line[i] != '\n' && line[i] != '\r' {
+					word.WriteByte(line[i])
+					inWord = true
+				}
+			}
+		case ch == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated single quote", ErrorInvalidCommand)
+			}
+			word.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case ch == '$' && i+1 < len(line) && line[i+1] == '\'':
+			decoded, consumed, err := decodeANSIC(line[i+2:])
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(decoded)
+			i += consumed + 1
+			inWord = true
+		case ch == '"':
+			closed := false
+			for i++; i < len(line); i++ {
+				if line[i] == '"' {
+					closed = true
+					break
+				}
+				if line[i] == '\\' && i+1 < len(line) && strings.IndexByte("$`\"\\\n", line[i+1]) >= 0 {
+					i++
+					if line[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(line[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("%w: unterminated double quote", ErrorInvalidCommand)
+			}
+			inWord = true
+		default:
+			word.WriteByte(ch)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Decode the body of an ANSI-C quoted string up to its closing quote, returning the bytes consumed with the quote.
+func decodeANSIC(s string) (string, int, error) {
+	var decoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			return decoded.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("%w: unterminated ANSI-C quote", ErrorInvalidCommand)
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				decoded.WriteByte('\n')
+			case 't':
+				decoded.WriteByte('\t')
+			case 'r':
+				decoded.WriteByte('\r')
+			case '0':
+				decoded.WriteByte(0)
+			case 'x', 'u', 'U':
+				digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[i]]
+				end := i + 1
+				for end < len(s) && end < i+1+digits && strings.IndexByte("0123456789abcdefABCDEF", s[end]) >= 0 {
+					end++
+				}
+				value, err := strconv.ParseUint(s[i+1:end], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("%w: invalid escape \\%s", ErrorInvalidCommand, s[i:end])
+				}
+				if s[i] == 'x' {
+					decoded.WriteByte(byte(value))
+				} else {
+					decoded.WriteRune(rune(value))
+				}
+				i = end - 1
+			default:
+				// \\, \', \" and other characters stand for themselves.
+				decoded.WriteByte(s[i])
+			}
+		default:
+			decoded.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("%w: unterminated ANSI-C quote", ErrorInvalidCommand)
+}
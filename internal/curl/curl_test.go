@@ -0,0 +1,178 @@
+package curl
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"reqcorder/internal/request"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	insecure := false
+	caCert, _ := filepath.Abs("ca.pem")
+	bodyFile, _ := filepath.Abs("body.json")
+	tests := []struct {
+		name     string
+		command  string
+		expected Template
+	}{
+		{
+			name:     "Plain GET",
+			command:  "curl example.com/health",
+			expected: Template{URL: "http://example.com/health", Method: "GET"},
+		},
+		{
+			name: "Browser copy",
+			command: `curl 'https://api.example.com/items?page=2&tag=a&tag=b' \
+  -H 'accept: application/json' \
+  -H 'authorization: Bearer token123' \
+  -H 'accept-encoding: gzip' \
+  -H 'cookie: sid=1; theme=dark' \
+  -H 'user-agent: Mozilla/5.0' \
+  --data-raw $'{"note":"it\'s\nfine"}' \
+  --compressed`,
+			expected: Template{
+				URL:       "https://api.example.com/items",
+				Query:     map[string]request.MultiValue{"page": {"2"}, "tag": {"a", "b"}},
+				Method:    "POST",
+				Headers:   map[string]string{"accept": "application/json", "Content-Type": "application/x-www-form-urlencoded"},
+				Cookies:   map[string]string{"sid": "1", "theme": "dark"},
+				Auth:      "token123",
+				AuthType:  "bearer",
+				UserAgent: "Mozilla/5.0",
+				Body:      "{\"note\":\"it's\nfine\"}",
+			},
+		},
+		{
+			name:    "Basic auth, TLS, and grouped options",
+			command: `curl -sSL -XPUT -u alice:s3cret -k --cacert ca.pem -A "my \"agent\"" -b x=y -d a=1 -d b=2 http://127.0.0.1/x`,
+			expected: Template{
+				URL:        "http://127.0.0.1/x",
+				Method:     "PUT",
+				Headers:    map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+				Cookies:    map[string]string{"x": "y"},
+				Auth:       "{{base64:alice:s3cret}}",
+				AuthType:   "basic",
+				UserAgent:  `my "agent"`,
+				Body:       "a=1&b=2",
+				SSLVerify:  &insecure,
+				CACertPath: caCert,
+			},
+		},
+		{
+			name:    "Closing brace in password",
+			command: "curl -u 'u:p}w' https://example.com",
+			expected: Template{
+				URL:      "https://example.com",
+				Method:   "GET",
+				Auth:     "dTpwfXc=",
+				AuthType: "basic",
+			},
+		},
+		{
+			name:    "Opening brace in password",
+			command: "curl -u 'u:{pw' https://example.com",
+			expected: Template{
+				URL:      "https://example.com",
+				Method:   "GET",
+				Auth:     "dTp7cHc=",
+				AuthType: "basic",
+			},
+		},
+		{
+			name:    "Data file and explicit headers",
+			command: "curl --request PATCH --header 'Content-Type: application/json' --header 'X-Empty;' --header 'Accept-Encoding: gzip' --data-binary @body.json https://example.com",
+			expected: Template{
+				URL:      "https://example.com",
+				Method:   "PATCH",
+				Headers:  map[string]string{"Content-Type": "application/json", "X-Empty": "", "Accept-Encoding": "gzip"},
+				BodyFile: bodyFile,
+			},
+		},
+		{
+			name:    "Authorization header wins over user",
+			command: "curl -I -u bob -H 'Authorization: Basic Ym9iOg==' https://example.com",
+			expected: Template{
+				URL:      "https://example.com",
+				Method:   "HEAD",
+				Auth:     "Ym9iOg==",
+				AuthType: "basic",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := Parse(tt.command)
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if !reflect.DeepEqual(*template, tt.expected) {
+				t.Errorf("Expected %+v, received %+v", tt.expected, *template)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		expected error
+	}{
+		{name: "No URL", command: "curl -X POST", expected: ErrorInvalidCommand},
+		{name: "Two URLs", command: "curl http://a http://b", expected: ErrorInvalidCommand},
+		{name: "Missing value", command: "curl http://a -H", expected: ErrorInvalidCommand},
+		{name: "Unterminated quote", command: `curl "http://a`, expected: ErrorInvalidCommand},
+		{name: "Unknown long option", command: "curl --proxy http://p http://a", expected: ErrorUnsupportedOption},
+		{name: "Unknown short option", command: "curl -F a=1 http://a", expected: ErrorUnsupportedOption},
+		{name: "Cookie file", command: "curl -b cookies.txt http://a", expected: ErrorUnsupportedOption},
+		{name: "Data from stdin", command: "curl -d @- http://a", expected: ErrorUnsupportedOption},
+		{name: "File mixed with data", command: "curl -d @a.json -d b=1 http://a", expected: ErrorUnsupportedOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.command); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, received %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{line: `a  b	c`, expected: []string{"a", "b", "c"}},
+		{line: `'it'\''s' "say \"hi\" \$HOME" plain\ word`, expected: []string{"it's", `say "hi" $HOME`, "plain word"}},
+		{line: "a \\\n  b \\\r\n c", expected: []string{"a", "b", "c"}},
+		{line: `$'tab\there\x41é' ''`, expected: []string{"tab\there" + "A" + "é", ""}},
+		{line: `pre'quoted'"joined"`, expected: []string{"prequotedjoined"}},
+	}
+	for _, tt := range tests {
+		words, err := splitWords(tt.line)
+		if err != nil {
+			t.Fatalf("Expected no error for %q, received %v", tt.line, err)
+		}
+		if !reflect.DeepEqual(words, tt.expected) {
+			t.Errorf("Expected %q for %q, received %q", tt.expected, tt.line, words)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	template, err := Parse("curl https://example.com -H 'X-Id: 1'")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	templateYaml, err := template.Marshal()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	expected := templateComment + "url: https://example.com\nmethod: GET\nheaders:\n  X-Id: \"1\"\n"
+	if !strings.HasPrefix(string(templateYaml), templateComment) || string(templateYaml) != expected {
+		t.Errorf("Expected %q, received %q", expected, templateYaml)
+	}
+}
@@ -0,0 +1,8 @@
+package curl
+
+import "errors"
+
+var (
+	ErrorInvalidCommand    = errors.New("invalid curl command")
+	ErrorUnsupportedOption = errors.New("unsupported curl option")
+)
@@ -0,0 +1,19 @@
+package curl
+
+import "reqcorder/internal/request"
+
+// Template is the template written for an imported curl command, with the keys the command can set.
+type Template struct {
+	URL        string                        `yaml:"url"`
+	Query      map[string]request.MultiValue `yaml:"query,omitempty"`
+	Method     string                        `yaml:"method"`
+	Headers    map[string]string             `yaml:"headers,omitempty"`
+	Cookies    map[string]string             `yaml:"cookies,omitempty"`
+	Auth       string                        `yaml:"auth,omitempty"`
+	AuthType   string                        `yaml:"auth_type,omitempty"`
+	UserAgent  string                        `yaml:"user_agent,omitempty"`
+	Body       string                        `yaml:"body,omitempty"`
+	BodyFile   string                        `yaml:"body_file,omitempty"`
+	SSLVerify  *bool                         `yaml:"ssl_verify,omitempty"`
+	CACertPath string                        `yaml:"ca_cert_path,omitempty"`
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
-// Generate a git-style diff between two resources (response, request, or template).
+// Generate a git-style diff between two resources (response, request, template, or inbound).
 func DefaultDiff(w io.Writer, recordStorePath string, source string, target string, resource string) error {
 	slog.Debug("Generating git-style diff", "recordStorePath", recordStorePath, "source", source, "target", target, "resource", resource)
 	text1, text2, err := getTexts(recordStorePath, source, target, resource)
@@ -28,7 +28,7 @@ func DefaultDiff(w io.Writer, recordStorePath string, source string, target stri
 	return nil
 }
 
-// Generate an inline diff between two resources (response, request, or template).
+// Generate an inline diff between two resources (response, request, template, or inbound).
 func InlineDiff(w io.Writer, recordStorePath string, source string, target string, resource string) error {
 	slog.Debug("Generating inline diff", "recordStorePath", recordStorePath, "source", source, "target", target, "resource", resource)
 	text1, text2, err := getTexts(recordStorePath, source, target, resource)
@@ -94,6 +94,22 @@ func (d *DiffStore) getTemplateByHash(templateHash string) (string, error) {
 	return string(recordStore.TemplateYaml), nil
 }
 
+// Get inbound request content by its ID for diff comparison.
+func (d *DiffStore) getInboundByID(inboundID string) (string, error) {
+	slog.Debug("Getting inbound request by ID for diff", "inboundID", inboundID, "recordStorePath", d.RecordStorePath)
+	inboundStore := &record.InboundStore{
+		RecordStorePath: d.RecordStorePath,
+		InboundID:       inboundID,
+	}
+	err := inboundStore.GetInboundByID()
+	if err != nil {
+		slog.Error("Failed to get inbound request by ID for diff", "error", err, "inboundID", inboundID)
+		return "", err
+	}
+	slog.Debug("Successfully retrieved inbound request for diff", "inboundID", inboundID)
+	return string(inboundStore.InboundYaml), nil
+}
+
 // Get text content for two resources based on their type and identifiers.
 func getTexts(recordStorePath string, source string, target string, resource string) (string, string, error) {
 	slog.Debug("Getting texts for diff", "recordStorePath", recordStorePath, "source", source, "target", target, "resource", resource)
@@ -143,6 +159,20 @@ func getTexts(recordStorePath string, source string, target string, resource str
 		}
 		slog.Debug("Successfully retrieved template texts for diff", "sourceLength", len(text1), "targetLength", len(text2))
 		return text1, text2, nil
+	case "inbound":
+		slog.Debug("Getting inbound request texts for diff", "sourceInboundID", source, "targetInboundID", target)
+		text1, err := diffStore.getInboundByID(source)
+		if err != nil {
+			slog.Error("Failed to get source inbound request for diff", "error", err, "inboundID", source)
+			return "", "", err
+		}
+		text2, err := diffStore.getInboundByID(target)
+		if err != nil {
+			slog.Error("Failed to get target inbound request for diff", "error", err, "inboundID", target)
+			return "", "", err
+		}
+		slog.Debug("Successfully retrieved inbound request texts for diff", "sourceLength", len(text1), "targetLength", len(text2))
+		return text1, text2, nil
 	default:
 		slog.Error("Invalid diff type", "resource", resource)
 		return "", "", fmt.Errorf("%w", ErrorInvalidDiffType)
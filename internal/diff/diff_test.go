@@ -342,6 +342,41 @@ func TestFailedGetTemplateByHash_RecordFailure(t *testing.T) {
 	}
 }
 
+func TestSuccessfulGetInboundByID(t *testing.T) {
+	root := t.TempDir()
+	inboundStore := &record.InboundStore{
+		RecordStorePath: root,
+		InboundYaml:     []byte("method: POST\npath: /hooks\n"),
+	}
+	err := inboundStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+
+	diffStore := &DiffStore{
+		RecordStorePath: root,
+	}
+	content, err := diffStore.getInboundByID(inboundStore.InboundID)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if content != string(inboundStore.InboundYaml) {
+		t.Fatalf("Expected %q, received %q\n", inboundStore.InboundYaml, content)
+	}
+}
+
+func TestFailedGetInboundByID_RecordFailure(t *testing.T) {
+	root := t.TempDir()
+	diffStore := &DiffStore{
+		RecordStorePath: root,
+	}
+	_, err := diffStore.getInboundByID("nonexistent")
+	expectedErr := record.ErrorFailedToGetInbound
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Expected error %v, received %v\n", expectedErr, err)
+	}
+}
+
 func TestSuccessfulGetTexts(t *testing.T) {
 	root := t.TempDir()
 	templateYaml := []byte("key: value")
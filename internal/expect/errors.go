@@ -0,0 +1,9 @@
+package expect
+
+import "errors"
+
+var (
+	ErrorAssertionsFailed = errors.New("response assertions failed")
+	ErrorBodyNotJSON      = errors.New("response body is not JSON")
+	ErrorSchemaViolations = errors.New("response body does not match the JSON Schema")
+)
@@ -0,0 +1,333 @@
+package expect
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/jsonpath"
+	"reqcorder/pkg/jsonschema"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+const missing = "<missing>"
+
+// Evaluate the expect block of a request against its response. Assertions are returned in a stable order,
+// along with the schema violations found when the block references a JSON Schema.
+func Evaluate(e *request.Expectation, res *response.ResponseObject) ([]response.Assertion, []response.SchemaViolation) {
+	if e == nil || res == nil {
+		return nil, nil
+	}
+	slog.Debug("Evaluating response assertions", "statusCode", res.StatusCode)
+	var assertions []response.Assertion
+	if len(e.Status) > 0 {
+		assertions = append(assertions, checkStatus(e.Status, res.StatusCode))
+	}
+	for _, name := range utils.SortedKeys(e.Headers) {
+		value, exists := res.Header(name)
+		assertions = append(assertions, checkText("header "+name, e.Headers[name], value, exists)...)
+	}
+	if len(e.JSON) > 0 {
+		doc, err := jsonpath.Decode([]byte(res.Body))
+		for _, expression := range utils.SortedKeys(e.JSON) {
+			matcher := e.JSON[expression]
+			if err != nil {
+				assertions = append(assertions, fail("json "+expression, describeMatcher(matcher), "<body is not JSON>"))
+				continue
+			}
+			path, _ := jsonpath.Parse(expression)
+			value, evalErr := path.Evaluate(doc)
+			assertions = append(assertions, checkJSON("json "+expression, matcher, value, evalErr == nil)...)
+		}
+	}
+	if e.Body != nil {
+		assertions = append(assertions, checkText("body", *e.Body, res.Body, true)...)
+	}
+	if limit, err := e.LatencyLimit(); err == nil && limit > 0 {
+		assertions = append(assertions, response.Assertion{
+			Check:    "latency",
+			Expected: "<= " + limit.String(),
+			Actual:   res.Timing.Total.String(),
+			Passed:   res.Timing.Total <= limit,
+		})
+	}
+	if e.MaxSize > 0 {
+		assertions = append(assertions, response.Assertion{
+			Check:    "size",
+			Expected: fmt.Sprintf("<= %d bytes", e.MaxSize),
+			Actual:   fmt.Sprintf("%d bytes", res.Size),
+			Passed:   res.Size <= e.MaxSize,
+		})
+	}
+	var violations []response.SchemaViolation
+	if e.Schema != "" {
+		var assertion response.Assertion
+		assertion, violations = checkSchema(e, res.Body)
+		assertions = append(assertions, assertion)
+	}
+	slog.Debug("Response assertions evaluated", "assertionCount", len(assertions), "failedCount", Failed(assertions), "violationCount", len(violations))
+	return assertions, violations
+}
+
+// Validate a body against a JSON Schema. The error reports a body that is not JSON.
+func ValidateSchema(schema *jsonschema.Schema, body string) ([]response.SchemaViolation, error) {
+	found, err := schema.ValidateJSON([]byte(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorBodyNotJSON, err)
+	}
+	var violations []response.SchemaViolation
+	for _, violation := range found {
+		violations = append(violations, response.SchemaViolation{Path: violation.InstancePath, Keyword: violation.SchemaPath, Message: violation.Message})
+	}
+	slog.Debug("Validated body against JSON Schema", "schema", schema.Path(), "violationCount", len(violations))
+	return violations, nil
+}
+
+// The schema check passes when the body is JSON without violations.
+func checkSchema(e *request.Expectation, body string) (response.Assertion, []response.SchemaViolation) {
+	assertion := response.Assertion{Check: "schema", Expected: "matches " + filepath.Base(e.Schema)}
+	schema, err := e.JSONSchema()
+	if err != nil {
+		assertion.Actual = err.Error()
+		return assertion, nil
+	}
+	violations, err := ValidateSchema(schema, body)
+	switch {
+	case err != nil:
+		assertion.Actual = "<body is not JSON>"
+	case len(violations) == 1:
+		assertion.Actual = "1 violation"
+	case len(violations) > 1:
+		assertion.Actual = fmt.Sprintf("%d violations", len(violations))
+	default:
+		assertion.Actual = "valid"
+		assertion.Passed = true
+	}
+	return assertion, violations
+}
+
+// Count the failed assertions.
+func Failed(assertions []response.Assertion) int {
+	failed := 0
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// The status passes when it matches any exact code or class.
+func checkStatus(expected request.MultiValue, code int) response.Assertion {
+	actual := strconv.Itoa(code)
+	passed := false
+	for _, status := range expected {
+		status = strings.ToLower(status)
+		if status == actual || (strings.HasSuffix(status, "xx") && len(actual) == 3 && status[0] == actual[0]) {
+			passed = true
+			break
+		}
+	}
+	return response.Assertion{Check: "status", Expected: strings.Join(expected, " or "), Actual: actual, Passed: passed}
+}
+
+// Check a header or the body, which are compared as text.
+func checkText(check string, m request.Matcher, value string, exists bool) []response.Assertion {
+	var assertions []response.Assertion
+	actual := value
+	if !exists {
+		actual = missing
+	}
+	if m.Exists != nil {
+		assertions = append(assertions, response.Assertion{Check: check + " exists", Expected: strconv.FormatBool(*m.Exists), Actual: strconv.FormatBool(exists), Passed: exists == *m.Exists})
+	}
+	if m.Equals != nil {
+		expected := formatValue(m.Equals)
+		assertions = append(assertions, response.Assertion{Check: check + " equals", Expected: expected, Actual: actual, Passed: exists && value == expected})
+	}
+	if m.Contains != nil {
+		expected := formatValue(m.Contains)
+		assertions = append(assertions, response.Assertion{Check: check + " contains", Expected: expected, Actual: actual, Passed: exists && strings.Contains(value, expected)})
+	}
+	if m.Regex != "" {
+		re, err := regexp.Compile(m.Regex)
+		assertions = append(assertions, response.Assertion{Check: check + " matches", Expected: m.Regex, Actual: actual, Passed: err == nil && exists && re.MatchString(value)})
+	}
+	return assertions
+}
+
+// Check a value selected by JSONPath. Equality compares JSON values, so 1 and 1.0 are equal but 1 and "1" are not.
+func checkJSON(check string, m request.Matcher, value any, exists bool) []response.Assertion {
+	var assertions []response.Assertion
+	actual := missing
+	if exists {
+		actual = jsonpath.Format(value)
+	}
+	if m.Exists != nil {
+		assertions = append(assertions, response.Assertion{Check: check + " exists", Expected: strconv.FormatBool(*m.Exists), Actual: strconv.FormatBool(exists), Passed: exists == *m.Exists})
+	}
+	if m.Equals != nil {
+		assertions = append(assertions, response.Assertion{Check: check + " equals", Expected: formatValue(m.Equals), Actual: actual, Passed: exists && equalJSON(value, canonical(m.Equals))})
+	}
+	if m.Contains != nil {
+		assertions = append(assertions, response.Assertion{Check: check + " contains", Expected: formatValue(m.Contains), Actual: actual, Passed: exists && containsJSON(value, canonical(m.Contains))})
+	}
+	if m.Regex != "" {
+		re, err := regexp.Compile(m.Regex)
+		assertions = append(assertions, response.Assertion{Check: check + " matches", Expected: m.Regex, Actual: actual, Passed: err == nil && exists && re.MatchString(actual)})
+	}
+	if m.Type != "" {
+		actualType := missing
+		if exists {
+			actualType = jsonType(value)
+		}
+		expectedType := strings.ToLower(m.Type)
+		passed := actualType == expectedType || (expectedType == "number" && actualType == "integer")
+		assertions = append(assertions, response.Assertion{Check: check + " type", Expected: expectedType, Actual: actualType, Passed: passed})
+	}
+	return assertions
+}
+
+func fail(check string, expected string, actual string) response.Assertion {
+	return response.Assertion{Check: check, Expected: expected, Actual: actual, Passed: false}
+}
+
+// Summarize the checks of a matcher for assertions that cannot be evaluated.
+func describeMatcher(m request.Matcher) string {
+	var parts []string
+	if m.Exists != nil {
+		parts = append(parts, "exists "+strconv.FormatBool(*m.Exists))
+	}
+	if m.Equals != nil {
+		parts = append(parts, "equals "+formatValue(m.Equals))
+	}
+	if m.Contains != nil {
+		parts = append(parts, "contains "+formatValue(m.Contains))
+	}
+	if m.Regex != "" {
+		parts = append(parts, "matches "+m.Regex)
+	}
+	if m.Type != "" {
+		parts = append(parts, "type "+strings.ToLower(m.Type))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Render an expected value from the template as text.
+func formatValue(value any) string {
+	return jsonpath.Format(canonical(value))
+}
+
+// Convert a value decoded from YAML into the form produced by decoding JSON, with json.Number for numbers.
+func canonical(value any) any {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	doc, err := jsonpath.Decode(content)
+	if err != nil {
+		return value
+	}
+	return doc
+}
+
+// Compare two values decoded from JSON. Numbers are compared by value.
+func equalJSON(a any, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		return errX == nil && errY == nil && fx == fy
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, exists := y[key]
+			if !exists || !equalJSON(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalJSON(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// Report whether a string contains a substring, an array contains an element, or an object contains the expected keys and values.
+func containsJSON(value any, expected any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, jsonpath.Format(expected))
+	case []any:
+		for _, item := range v {
+			if equalJSON(item, expected) {
+				return true
+			}
+		}
+		return false
+	case map[string]any:
+		subset, ok := expected.(map[string]any)
+		if !ok {
+			key, isKey := expected.(string)
+			_, exists := v[key]
+			return isKey && exists
+		}
+		for key, expectedValue := range subset {
+			actualValue, exists := v[key]
+			if !exists || !equalJSON(actualValue, expectedValue) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Name the JSON type of a value. Numbers written without a fraction or exponent are integers.
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,240 @@
+package expect
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/jsonschema"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestEvaluate(t *testing.T) {
+	res := &response.ResponseObject{
+		StatusCode: 201,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8", "X-Request-Id": "req-42"},
+		Body:       `{"id": 42, "price": 9.5, "name": "jane doe", "tags": ["a", "b"], "user": {"id": 7, "role": "admin"}, "deleted": null}`,
+		Size:       120,
+		Timing:     response.ResponseTimes{Total: 150 * time.Millisecond},
+	}
+	tests := []struct {
+		name           string
+		expect         string
+		expectedChecks []string
+		expectedPassed []bool
+	}{
+		{
+			name:           "Status code and class",
+			expect:         "status: [200, 2xx]",
+			expectedChecks: []string{"status"},
+			expectedPassed: []bool{true},
+		},
+		{
+			name:           "Status mismatch",
+			expect:         "status: 200",
+			expectedChecks: []string{"status"},
+			expectedPassed: []bool{false},
+		},
+		{
+			name:           "Headers",
+			expect:         "headers:\n  content-type:\n    contains: application/json\n  X-Request-Id:\n    regex: '^req-\\d+$'\n  X-Missing:\n    exists: false\n  X-Other: value",
+			expectedChecks: []string{"header X-Missing exists", "header X-Other equals", "header X-Request-Id matches", "header content-type contains"},
+			expectedPassed: []bool{true, false, true, true},
+		},
+		{
+			name:           "JSON equality compares values",
+			expect:         "json:\n  $.id: 42.0\n  $.name: jane doe\n  $.user: {id: 7, role: admin}\n  $.tags: [a, b]\n  $.price: \"9.5\"",
+			expectedChecks: []string{"json $.id equals", "json $.name equals", "json $.price equals", "json $.tags equals", "json $.user equals"},
+			expectedPassed: []bool{true, true, false, true, true},
+		},
+		{
+			name:           "JSON contains, type, regex, and exists",
+			expect:         "json:\n  $.tags:\n    contains: b\n  $.user:\n    contains: {role: admin}\n  $.name:\n    contains: doe\n    regex: '^jane'\n  $.id:\n    type: integer\n  $.price:\n    type: number\n  $.deleted:\n    type: null\n  $.missing:\n    exists: true",
+			expectedChecks: []string{"json $.deleted type", "json $.id type", "json $.missing exists", "json $.name contains", "json $.name matches", "json $.price type", "json $.tags contains", "json $.user contains"},
+			expectedPassed: []bool{true, true, false, true, true, true, true, true},
+		},
+		{
+			name:           "Body, latency, and size",
+			expect:         "body:\n  regex: '\"role\": \"admin\"'\nmax_latency: 100\nmax_size: 200",
+			expectedChecks: []string{"body matches", "latency", "size"},
+			expectedPassed: []bool{true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e request.Expectation
+			err := yaml.Unmarshal([]byte(tt.expect), &e)
+			if err != nil {
+				t.Fatalf("Failed to decode expect block: %v", err)
+			}
+			assertions, _ := Evaluate(&e, res)
+			var checks []string
+			var passed []bool
+			for _, assertion := range assertions {
+				checks = append(checks, assertion.Check)
+				passed = append(passed, assertion.Passed)
+			}
+			if !reflect.DeepEqual(checks, tt.expectedChecks) {
+				t.Fatalf("Expected checks %v, received %v", tt.expectedChecks, checks)
+			}
+			if !reflect.DeepEqual(passed, tt.expectedPassed) {
+				t.Errorf("Expected results %v, received %v for %+v", tt.expectedPassed, passed, assertions)
+			}
+		})
+	}
+}
+
+func TestEvaluate_NonJSONBody(t *testing.T) {
+	res := &response.ResponseObject{StatusCode: 200, Body: "plain text"}
+	e := &request.Expectation{JSON: map[string]request.Matcher{"$.id": {Equals: 1}}}
+	assertions, _ := Evaluate(e, res)
+	if len(assertions) != 1 || assertions[0].Passed || assertions[0].Actual != "<body is not JSON>" {
+		t.Fatalf("Expected a failed assertion for a non JSON body, received %+v", assertions)
+	}
+	if Failed(assertions) != 1 {
+		t.Errorf("Expected 1 failed assertion, received %d", Failed(assertions))
+	}
+	if assertions, _ := Evaluate(nil, res); assertions != nil {
+		t.Error("Expected no assertions without an expect block")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	dir := t.TempDir()
+	common := `{"$defs": {"id": {"type": "integer", "minimum": 1}}}`
+	if err := os.WriteFile(filepath.Join(dir, "common.json"), []byte(common), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	user := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id", "name", "role"],
+		"properties": {
+			"id": {"$ref": "common.json#/$defs/id"},
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z ]+$"},
+			"role": {"enum": ["admin", "member"]},
+			"price": {"type": "number", "multipleOf": 0.01, "exclusiveMaximum": 100},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"address": {"$ref": "#/$defs/address"},
+			"contact": {"oneOf": [{"required": ["email"]}, {"required": ["phone"]}]}
+		},
+		"additionalProperties": false,
+		"$defs": {
+			"address": {"type": "object", "required": ["city"], "properties": {"city": {"type": "string"}}}
+		}
+	}`
+	schemaPath := filepath.Join(dir, "user.json")
+	if err := os.WriteFile(schemaPath, []byte(user), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	schema, err := jsonschema.Load(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	tests := []struct {
+		name          string
+		body          string
+		expectedPaths []string
+		expectedErr   error
+	}{
+		{
+			name: "Valid body",
+			body: `{"id": 1, "name": "jane doe", "role": "admin", "price": 9.99, "tags": ["a", "b"], "address": {"city": "Pune"}, "contact": {"email": "j@example.com"}}`,
+		},
+		{
+			name:          "Integer written with a fraction",
+			body:          `{"id": 2.0, "name": "jane", "role": "member"}`,
+			expectedPaths: nil,
+		},
+		{
+			name:          "Violations point at failing values",
+			body:          `{"id": 0, "name": "J", "role": "owner", "price": 100.001, "tags": ["a", "a", 3, "d"], "address": {}, "contact": {"email": "e", "phone": "p"}, "extra": true}`,
+			expectedPaths: []string{"/address", "/contact", "/extra", "/id", "/name", "/name", "/price", "/price", "/role", "/tags", "/tags", "/tags/2"},
+		},
+		{
+			name:          "Missing required properties are reported on the parent",
+			body:          `{"name": "jane"}`,
+			expectedPaths: []string{"", ""},
+		},
+		{
+			name:          "Wrong root type",
+			body:          `[1, 2]`,
+			expectedPaths: []string{""},
+		},
+		{
+			name:        "Body is not JSON",
+			body:        "plain text",
+			expectedErr: ErrorBodyNotJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ValidateSchema(schema, tt.body)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+			var paths []string
+			for _, violation := range violations {
+				paths = append(paths, violation.Path)
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("Expected violations at %q, received %+v", tt.expectedPaths, violations)
+			}
+		})
+	}
+}
+
+func TestLoadSchema_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr error
+	}{
+		{name: "Not JSON", content: "type: object", expectedErr: jsonschema.ErrorInvalidSchema},
+		{name: "Not an object", content: `"object"`, expectedErr: jsonschema.ErrorInvalidSchema},
+		{name: "Unresolved reference", content: `{"$ref": "#/$defs/missing"}`, expectedErr: jsonschema.ErrorInvalidSchema},
+		{name: "Invalid pattern", content: `{"pattern": "("}`, expectedErr: jsonschema.ErrorInvalidSchema},
+		{name: "Missing referenced file", content: `{"$ref": "missing.json"}`, expectedErr: jsonschema.ErrorFailedToLoadSchema},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strconv.Itoa(i)+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write schema: %v", err)
+			}
+			_, err := jsonschema.Load(path)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, received %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Schema(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(schemaPath, []byte(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	e := &request.Expectation{Schema: schemaPath}
+	assertions, violations := Evaluate(e, &response.ResponseObject{StatusCode: 200, Body: `{"id": "42"}`})
+	if len(assertions) != 1 || assertions[0].Check != "schema" || assertions[0].Passed || assertions[0].Actual != "1 violation" {
+		t.Fatalf("Expected a failed schema assertion, received %+v", assertions)
+	}
+	expected := []response.SchemaViolation{{Path: "/id", Keyword: "/properties/id/type", Message: "expected integer, found string"}}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %+v, received %+v", expected, violations)
+	}
+	assertions, violations = Evaluate(e, &response.ResponseObject{StatusCode: 200, Body: `{"id": 42}`})
+	if len(assertions) != 1 || !assertions[0].Passed || violations != nil {
+		t.Errorf("Expected a passed schema assertion, received %+v and %+v", assertions, violations)
+	}
+}
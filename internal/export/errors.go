@@ -0,0 +1,8 @@
+package export
+
+import "errors"
+
+var (
+	ErrorInvalidFormat  = errors.New("invalid export format")
+	ErrorFailedToEncode = errors.New("failed to encode request body for export")
+)
@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"reqcorder/internal/request"
+	"reqcorder/pkg/utils"
+	"strings"
+)
+
+// Render the stored request as a command or code snippet in the given format. With redact, credentials and cookie
+// values are replaced by RedactedValue.
+func Render(r *request.RequestObject, format string, redact bool) (string, error) {
+	slog.Debug("Rendering request export", "format", format, "redact", redact, "templateHash", r.TemplateHash)
+	s, err := newSnippet(r, redact)
+	if err != nil {
+		slog.Error("Failed to prepare request for export", "error", err)
+		return "", err
+	}
+	switch format {
+	case FormatCurl:
+		return renderCurl(s), nil
+	case FormatHTTPie:
+		return renderHTTPie(s), nil
+	case FormatGo:
+		return renderGo(s)
+	case FormatPythonRequests:
+		return renderPythonRequests(s), nil
+	case FormatFetch:
+		return renderFetch(s), nil
+	default:
+		slog.Error("Invalid export format", "format", format)
+		return "", fmt.Errorf("%w %q, expected one of %s", ErrorInvalidFormat, format, strings.Join(Formats, ", "))
+	}
+}
+
+// Flatten the request into the URL, headers, cookies, and body the initiator would send.
+func newSnippet(r *request.RequestObject, redact bool) (*snippet, error) {
+	requestURL, err := r.FullURL()
+	if err != nil {
+		return nil, err
+	}
+	s := &snippet{
+		Method:    strings.ToUpper(r.Method),
+		URL:       requestURL,
+		Multipart: r.Multipart,
+		Insecure:  r.SSLVerify != nil && !*r.SSLVerify,
+	}
+	if !s.Insecure {
+		s.CACert = r.CACertPath
+	}
+	headers := make(map[string]string)
+	for name, value := range r.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	contentType := ""
+	switch {
+	case r.JSON != nil:
+		s.Body, err = r.EncodeJSON()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorFailedToEncode, err)
+		}
+		contentType = "application/json"
+	case len(r.Form) > 0:
+		s.Body = r.EncodeForm()
+		contentType = "application/x-www-form-urlencoded"
+	case r.Multipart != nil:
+		// The client generates the boundary, so the recorded content type cannot be reused.
+		delete(headers, "Content-Type")
+	case r.BodyFile != "" && r.Body == "" && r.BodyFileSize > 0:
+		s.BodyFile = r.BodyFile
+	default:
+		s.Body = r.Body
+	}
+	if _, exists := headers["Content-Type"]; !exists && contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+	authHeader := "Authorization"
+	if r.AuthHeaderName != "" {
+		authHeader = http.CanonicalHeaderKey(r.AuthHeaderName)
+	}
+	if r.Auth != "" {
+		headers[authHeader] = r.Auth
+	}
+	if r.UserAgent != "" {
+		headers["User-Agent"] = r.UserAgent
+	}
+	sensitive := map[string]bool{authHeader: true, "Authorization": true, "Proxy-Authorization": true, "Cookie": true}
+	for _, name := range utils.SortedKeys(headers) {
+		value := headers[name]
+		if redact && sensitive[name] {
+			value = RedactedValue
+		}
+		s.Headers = append(s.Headers, header{Name: name, Value: value})
+	}
+	for _, name := range utils.SortedKeys(r.Cookies) {
+		value := r.Cookies[name]
+		if redact {
+			value = RedactedValue
+		}
+		s.Cookies = append(s.Cookies, header{Name: name, Value: value})
+	}
+	return s, nil
+}
+
+// Return the cookies as a Cookie header value.
+func (s *snippet) cookieHeader() string {
+	pairs := make([]string, len(s.Cookies))
+	for i, cookie := range s.Cookies {
+		pairs[i] = cookie.Name + "=" + cookie.Value
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// Return the multipart fields as name and value pairs in the order the initiator writes them.
+func multipartFields(m *request.MultipartObject) []header {
+	var fields []header
+	for _, name := range utils.SortedKeys(m.Fields) {
+		for _, value := range m.Fields[name] {
+			fields = append(fields, header{Name: name, Value: value})
+		}
+	}
+	return fields
+}
+
+// Return the file name sent for a multipart file, defaulting to the base name of its path.
+func multipartFilename(file request.MultipartFile) string {
+	if file.Filename != "" {
+		return file.Filename
+	}
+	return filepath.Base(file.Path)
+}
+
+// Return the content type sent for a multipart file.
+func multipartContentType(file request.MultipartFile) string {
+	if file.ContentType != "" {
+		return file.ContentType
+	}
+	return "application/octet-stream"
+}
+
+// Quote a value for POSIX shells, leaving it bare when it has no special characters.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Quote a value as a double quoted string literal, valid in both Python and JavaScript.
+func stringLiteral(s string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string cannot fail.
+	encoder.Encode(s)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
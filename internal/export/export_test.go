@@ -0,0 +1,217 @@
+package export
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"reqcorder/internal/request"
+	"strings"
+	"testing"
+)
+
+func jsonRequest() *request.RequestObject {
+	return &request.RequestObject{
+		URL:       "https://api.example.com/users",
+		Method:    "POST",
+		Query:     map[string]request.MultiValue{"q": {"it's"}},
+		Headers:   map[string]string{"x-trace": `a'b "c"`},
+		Cookies:   map[string]string{"session": "abc123"},
+		Auth:      "Bearer secret",
+		UserAgent: "ReqCorder",
+		JSON:      map[string]any{"name": "O'Brien"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	insecure := false
+	multipartRequest := &request.RequestObject{
+		URL:            "https://api.example.com/upload",
+		Method:         "PUT",
+		Auth:           "key-123",
+		AuthHeaderName: "x-api-key",
+		SSLVerify:      &insecure,
+		Headers:        map[string]string{"Content-Type": "multipart/form-data; boundary=recorded"},
+		Multipart: &request.MultipartObject{
+			Fields: map[string]request.MultiValue{"title": {"v1; v2"}},
+			Files:  []request.MultipartFile{{Field: "doc", Path: "/tmp/a b.txt", ContentType: "text/plain"}},
+		},
+	}
+	fileRequest := &request.RequestObject{
+		URL:          "https://api.example.com/raw",
+		Method:       "GET",
+		BodyFile:     "/tmp/body.bin",
+		BodyFileSize: 10,
+		CACertPath:   "/etc/ca.pem",
+	}
+	tests := []struct {
+		name     string
+		request  *request.RequestObject
+		format   string
+		redact   bool
+		expected string
+	}{
+		{
+			name:    "Curl with quoting",
+			request: jsonRequest(),
+			format:  FormatCurl,
+			expected: `curl -X POST 'https://api.example.com/users?q=it%27s' \
+  -H 'Authorization: Bearer secret' \
+  -H 'Content-Type: application/json' \
+  -H 'User-Agent: ReqCorder' \
+  -H 'X-Trace: a'\''b "c"' \
+  -b session=abc123 \
+  --data-raw '{"name":"O'\''Brien"}'
+`,
+		},
+		{
+			name:    "Curl redacted",
+			request: jsonRequest(),
+			format:  FormatCurl,
+			redact:  true,
+			expected: `curl -X POST 'https://api.example.com/users?q=it%27s' \
+  -H 'Authorization: REDACTED' \
+  -H 'Content-Type: application/json' \
+  -H 'User-Agent: ReqCorder' \
+  -H 'X-Trace: a'\''b "c"' \
+  -b session=REDACTED \
+  --data-raw '{"name":"O'\''Brien"}'
+`,
+		},
+		{
+			name:    "Curl multipart with custom auth header",
+			request: multipartRequest,
+			format:  FormatCurl,
+			redact:  true,
+			expected: `curl -X PUT https://api.example.com/upload \
+  -H 'X-Api-Key: REDACTED' \
+  --form-string 'title=v1; v2' \
+  -F 'doc=@"/tmp/a b.txt";filename="a b.txt";type=text/plain' \
+  -k
+`,
+		},
+		{
+			name:    "Curl body file and CA certificate",
+			request: fileRequest,
+			format:  FormatCurl,
+			expected: `curl -X GET https://api.example.com/raw \
+  --data-binary @/tmp/body.bin \
+  --cacert /etc/ca.pem
+`,
+		},
+		{
+			name:    "HTTPie",
+			request: jsonRequest(),
+			format:  FormatHTTPie,
+			expected: `http --raw '{"name":"O'\''Brien"}' POST 'https://api.example.com/users?q=it%27s' \
+  'Authorization:Bearer secret' \
+  Content-Type:application/json \
+  User-Agent:ReqCorder \
+  'X-Trace:a'\''b "c"' \
+  Cookie:session=abc123
+`,
+		},
+		{
+			name:    "Python requests",
+			request: multipartRequest,
+			format:  FormatPythonRequests,
+			expected: `import requests
+
+response = requests.request(
+    "PUT",
+    "https://api.example.com/upload",
+    headers={
+        "X-Api-Key": "key-123",
+    },
+    data=[
+        ("title", "v1; v2"),
+    ],
+    files=[
+        ("doc", ("a b.txt", open("/tmp/a b.txt", "rb"), "text/plain")),
+    ],
+    verify=False,
+)
+print(response.status_code)
+print(response.text)
+`,
+		},
+		{
+			name:    "Fetch",
+			request: jsonRequest(),
+			format:  FormatFetch,
+			redact:  true,
+			expected: `const response = await fetch("https://api.example.com/users?q=it%27s", {
+  method: "POST",
+  headers: {
+    "Authorization": "REDACTED",
+    "Content-Type": "application/json",
+    "User-Agent": "ReqCorder",
+    "X-Trace": "a'b \"c\"",
+    "Cookie": "session=REDACTED",
+  },
+  body: "{\"name\":\"O'Brien\"}",
+});
+console.log(response.status);
+console.log(await response.text());
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, err := Render(tt.request, tt.format, tt.redact)
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if snippet != tt.expected {
+				t.Errorf("Expected\n%s\nreceived\n%s", tt.expected, snippet)
+			}
+		})
+	}
+}
+
+func TestRender_Go(t *testing.T) {
+	insecure := false
+	requests := []*request.RequestObject{
+		jsonRequest(),
+		{URL: "https://example.com", Method: "GET", SSLVerify: &insecure},
+		{URL: "https://example.com", Method: "POST", BodyFile: "/tmp/body.bin", BodyFileSize: 1, CACertPath: "/etc/ca.pem"},
+		{URL: "https://example.com", Method: "POST", Multipart: &request.MultipartObject{
+			Fields: map[string]request.MultiValue{"a": {"1", "2"}},
+			Files:  []request.MultipartFile{{Field: "f", Path: "/tmp/1"}, {Field: "f", Path: "/tmp/2"}},
+		}},
+	}
+	for _, r := range requests {
+		snippet, err := Render(r, FormatGo, false)
+		if err != nil {
+			t.Fatalf("Expected no error, received %v", err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "main.go", snippet, 0); err != nil {
+			t.Errorf("Expected a valid Go program, received %v\n%s", err, snippet)
+		}
+		if !strings.Contains(snippet, `http.NewRequest("`+r.Method+`", `) {
+			t.Errorf("Expected the request to be built, received\n%s", snippet)
+		}
+	}
+}
+
+func TestRender_InvalidFormat(t *testing.T) {
+	if _, err := Render(jsonRequest(), "ruby", false); !errors.Is(err, ErrorInvalidFormat) {
+		t.Errorf("Expected %v, received %v", ErrorInvalidFormat, err)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":                "''",
+		"plain/path.txt":  "plain/path.txt",
+		"two words":       "'two words'",
+		"it's":            `'it'\''s'`,
+		"$HOME":           "'$HOME'",
+		"line\nbreak":     "'line\nbreak'",
+		"key=value:a,b@c": "key=value:a,b@c",
+	}
+	for input, expected := range tests {
+		if quoted := shellQuote(input); quoted != expected {
+			t.Errorf("Expected %s for %q, received %s", expected, input, quoted)
+		}
+	}
+}
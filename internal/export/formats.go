@@ -0,0 +1,252 @@
+package export
+
+import (
+	"fmt"
+	"go/format"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// Separator between the arguments of a shell command, one argument per line.
+const continuation = " \\\n  "
+
+// Render a curl command.
+func renderCurl(s *snippet) string {
+	command := "curl"
+	hasBody := s.Body != "" || s.BodyFile != "" || s.Multipart != nil
+	if s.Method == "HEAD" {
+		command += " --head"
+	} else if s.Method != "GET" || hasBody {
+		command += " -X " + s.Method
+	}
+	args := []string{command + " " + shellQuote(s.URL)}
+	for _, h := range s.Headers {
+		args = append(args, "-H "+shellQuote(h.Name+": "+h.Value))
+	}
+	if len(s.Cookies) > 0 {
+		args = append(args, "-b "+shellQuote(s.cookieHeader()))
+	}
+	switch {
+	case s.Multipart != nil:
+		for _, field := range multipartFields(s.Multipart) {
+			args = append(args, "--form-string "+shellQuote(field.Name+"="+field.Value))
+		}
+		for _, file := range s.Multipart.Files {
+			part := fmt.Sprintf("%s=@%s;filename=%s;type=%s", file.Field, curlQuote(file.Path), curlQuote(multipartFilename(file)), multipartContentType(file))
+			args = append(args, "-F "+shellQuote(part))
+		}
+	case s.BodyFile != "":
+		args = append(args, "--data-binary "+shellQuote("@"+s.BodyFile))
+	case s.Body != "":
+		args = append(args, "--data-raw "+shellQuote(s.Body))
+	}
+	if s.Insecure {
+		args = append(args, "-k")
+	}
+	if s.CACert != "" {
+		args = append(args, "--cacert "+shellQuote(s.CACert))
+	}
+	return strings.Join(args, continuation) + "\n"
+}
+
+// Quote a value inside a curl form argument, where ; and , would otherwise end it.
+func curlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Render an HTTPie command.
+func renderHTTPie(s *snippet) string {
+	command := "http"
+	if s.Insecure {
+		command += " --verify=no"
+	} else if s.CACert != "" {
+		command += " " + shellQuote("--verify="+s.CACert)
+	}
+	if s.Multipart != nil {
+		command += " --multipart"
+	}
+	if s.Body != "" {
+		command += " --raw " + shellQuote(s.Body)
+	}
+	args := []string{command + " " + s.Method + " " + shellQuote(s.URL)}
+	for _, h := range s.Headers {
+		// An empty value after a colon removes the header in HTTPie, a semicolon sends it empty.
+		if h.Value == "" {
+			args = append(args, shellQuote(h.Name+";"))
+		} else {
+			args = append(args, shellQuote(h.Name+":"+h.Value))
+		}
+	}
+	if len(s.Cookies) > 0 {
+		args = append(args, shellQuote("Cookie:"+s.cookieHeader()))
+	}
+	if s.Multipart != nil {
+		for _, field := range multipartFields(s.Multipart) {
+			args = append(args, shellQuote(field.Name+"="+field.Value))
+		}
+		for _, file := range s.Multipart.Files {
+			args = append(args, shellQuote(file.Field+"@"+file.Path+";type="+multipartContentType(file)))
+		}
+	}
+	if s.BodyFile != "" {
+		args = append(args, shellQuote("@"+s.BodyFile))
+	}
+	return strings.Join(args, continuation) + "\n"
+}
+
+// Render a Python script using the requests library.
+func renderPythonRequests(s *snippet) string {
+	var b strings.Builder
+	b.WriteString("import requests\n\nresponse = requests.request(\n")
+	fmt.Fprintf(&b, "    %s,\n    %s,\n", stringLiteral(s.Method), stringLiteral(s.URL))
+	if len(s.Headers) > 0 {
+		b.WriteString("    headers={\n")
+		for _, h := range s.Headers {
+			fmt.Fprintf(&b, "        %s: %s,\n", stringLiteral(h.Name), stringLiteral(h.Value))
+		}
+		b.WriteString("    },\n")
+	}
+	if len(s.Cookies) > 0 {
+		b.WriteString("    cookies={\n")
+		for _, c := range s.Cookies {
+			fmt.Fprintf(&b, "        %s: %s,\n", stringLiteral(c.Name), stringLiteral(c.Value))
+		}
+		b.WriteString("    },\n")
+	}
+	switch {
+	case s.Multipart != nil:
+		if fields := multipartFields(s.Multipart); len(fields) > 0 {
+			b.WriteString("    data=[\n")
+			for _, field := range fields {
+				fmt.Fprintf(&b, "        (%s, %s),\n", stringLiteral(field.Name), stringLiteral(field.Value))
+			}
+			b.WriteString("    ],\n")
+		}
+		b.WriteString("    files=[\n")
+		for _, file := range s.Multipart.Files {
+			fmt.Fprintf(&b, "        (%s, (%s, open(%s, \"rb\"), %s)),\n", stringLiteral(file.Field), stringLiteral(multipartFilename(file)), stringLiteral(file.Path), stringLiteral(multipartContentType(file)))
+		}
+		b.WriteString("    ],\n")
+	case s.BodyFile != "":
+		fmt.Fprintf(&b, "    data=open(%s, \"rb\"),\n", stringLiteral(s.BodyFile))
+	case s.Body != "":
+		fmt.Fprintf(&b, "    data=%s.encode(),\n", stringLiteral(s.Body))
+	}
+	if s.Insecure {
+		b.WriteString("    verify=False,\n")
+	} else if s.CACert != "" {
+		fmt.Fprintf(&b, "    verify=%s,\n", stringLiteral(s.CACert))
+	}
+	b.WriteString(")\nprint(response.status_code)\nprint(response.text)\n")
+	return b.String()
+}
+
+// Render a JavaScript module using fetch. Files are read with Node.js, which must be told how to verify TLS.
+func renderFetch(s *snippet) string {
+	var b strings.Builder
+	if s.BodyFile != "" || (s.Multipart != nil && len(s.Multipart.Files) > 0) {
+		b.WriteString("import { readFile } from \"node:fs/promises\";\n\n")
+	}
+	if s.Insecure {
+		b.WriteString("// TLS verification is disabled for this request: run Node.js with NODE_TLS_REJECT_UNAUTHORIZED=0\n")
+	} else if s.CACert != "" {
+		fmt.Fprintf(&b, "// Trust the custom CA certificate: run Node.js with NODE_EXTRA_CA_CERTS=%s\n", s.CACert)
+	}
+	if s.Multipart != nil {
+		b.WriteString("const body = new FormData();\n")
+		for _, field := range multipartFields(s.Multipart) {
+			fmt.Fprintf(&b, "body.append(%s, %s);\n", stringLiteral(field.Name), stringLiteral(field.Value))
+		}
+		for _, file := range s.Multipart.Files {
+			fmt.Fprintf(&b, "body.append(%s, new Blob([await readFile(%s)], { type: %s }), %s);\n", stringLiteral(file.Field), stringLiteral(file.Path), stringLiteral(multipartContentType(file)), stringLiteral(multipartFilename(file)))
+		}
+	}
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n  method: %s,\n", stringLiteral(s.URL), stringLiteral(s.Method))
+	if len(s.Headers) > 0 || len(s.Cookies) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, h := range s.Headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", stringLiteral(h.Name), stringLiteral(h.Value))
+		}
+		if len(s.Cookies) > 0 {
+			fmt.Fprintf(&b, "    \"Cookie\": %s,\n", stringLiteral(s.cookieHeader()))
+		}
+		b.WriteString("  },\n")
+	}
+	switch {
+	case s.Multipart != nil:
+		b.WriteString("  body,\n")
+	case s.BodyFile != "":
+		fmt.Fprintf(&b, "  body: await readFile(%s),\n", stringLiteral(s.BodyFile))
+	case s.Body != "":
+		fmt.Fprintf(&b, "  body: %s,\n", stringLiteral(s.Body))
+	}
+	b.WriteString("});\nconsole.log(response.status);\nconsole.log(await response.text());\n")
+	return b.String()
+}
+
+// Render a Go program using net/http, formatted with gofmt.
+func renderGo(s *snippet) (string, error) {
+	imports := map[string]bool{"fmt": true, "io": true, "net/http": true}
+	var b strings.Builder
+	body := "nil"
+	switch {
+	case s.Multipart != nil:
+		imports["bytes"], imports["mime/multipart"] = true, true
+		body = "&body"
+		b.WriteString("var body bytes.Buffer\nwriter := multipart.NewWriter(&body)\n")
+		for _, field := range multipartFields(s.Multipart) {
+			fmt.Fprintf(&b, "if err := writer.WriteField(%s, %s); err != nil {\npanic(err)\n}\n", strconv.Quote(field.Name), strconv.Quote(field.Value))
+		}
+		for i, file := range s.Multipart.Files {
+			imports["os"], imports["net/textproto"] = true, true
+			disposition := fmt.Sprintf("form-data; name=%s; filename=%s", curlQuote(file.Field), curlQuote(multipartFilename(file)))
+			fmt.Fprintf(&b, "file%d, err := os.Open(%s)\nif err != nil {\npanic(err)\n}\ndefer file%d.Close()\n", i, strconv.Quote(file.Path), i)
+			fmt.Fprintf(&b, "part%d, err := writer.CreatePart(textproto.MIMEHeader{\n\"Content-Disposition\": {%s},\n\"Content-Type\": {%s},\n})\n", i, strconv.Quote(disposition), strconv.Quote(multipartContentType(file)))
+			fmt.Fprintf(&b, "if err != nil {\npanic(err)\n}\nif _, err := io.Copy(part%d, file%d); err != nil {\npanic(err)\n}\n", i, i)
+		}
+		b.WriteString("if err := writer.Close(); err != nil {\npanic(err)\n}\n")
+	case s.BodyFile != "":
+		imports["os"] = true
+		body = "body"
+		fmt.Fprintf(&b, "body, err := os.Open(%s)\nif err != nil {\npanic(err)\n}\ndefer body.Close()\n", strconv.Quote(s.BodyFile))
+	case s.Body != "":
+		imports["strings"] = true
+		body = "body"
+		fmt.Fprintf(&b, "body := strings.NewReader(%s)\n", strconv.Quote(s.Body))
+	}
+	fmt.Fprintf(&b, "req, err := http.NewRequest(%s, %s, %s)\nif err != nil {\npanic(err)\n}\n", strconv.Quote(s.Method), strconv.Quote(s.URL), body)
+	for _, h := range s.Headers {
+		fmt.Fprintf(&b, "req.Header.Set(%s, %s)\n", strconv.Quote(h.Name), strconv.Quote(h.Value))
+	}
+	if s.Multipart != nil {
+		b.WriteString("req.Header.Set(\"Content-Type\", writer.FormDataContentType())\n")
+	}
+	for _, c := range s.Cookies {
+		fmt.Fprintf(&b, "req.AddCookie(&http.Cookie{Name: %s, Value: %s})\n", strconv.Quote(c.Name), strconv.Quote(c.Value))
+	}
+	switch {
+	case s.Insecure:
+		imports["crypto/tls"] = true
+		b.WriteString("client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}\n")
+	case s.CACert != "":
+		imports["crypto/tls"], imports["crypto/x509"], imports["os"] = true, true, true
+		fmt.Fprintf(&b, "pem, err := os.ReadFile(%s)\nif err != nil {\npanic(err)\n}\nroots := x509.NewCertPool()\nroots.AppendCertsFromPEM(pem)\n", strconv.Quote(s.CACert))
+		b.WriteString("client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}\n")
+	default:
+		b.WriteString("client := &http.Client{}\n")
+	}
+	b.WriteString("res, err := client.Do(req)\nif err != nil {\npanic(err)\n}\ndefer res.Body.Close()\n")
+	b.WriteString("resBody, err := io.ReadAll(res.Body)\nif err != nil {\npanic(err)\n}\nfmt.Println(res.Status)\nfmt.Println(string(resBody))\n")
+
+	paths := utils.SortedKeys(imports)
+	for i, path := range paths {
+		paths[i] = strconv.Quote(path)
+	}
+	source := "package main\n\nimport (\n" + strings.Join(paths, "\n") + "\n)\n\nfunc main() {\n" + b.String() + "}\n"
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrorFailedToEncode, err)
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,37 @@
+package export
+
+import "reqcorder/internal/request"
+
+const (
+	FormatCurl           = "curl"
+	FormatHTTPie         = "httpie"
+	FormatGo             = "go"
+	FormatPythonRequests = "python-requests"
+	FormatFetch          = "fetch"
+)
+
+// Formats lists the supported export formats in the order they are documented.
+var Formats = []string{FormatCurl, FormatHTTPie, FormatGo, FormatPythonRequests, FormatFetch}
+
+// RedactedValue replaces credentials and cookie values when redacting.
+const RedactedValue = "REDACTED"
+
+// header is a single request header, kept as a pair so snippets list headers in a stable order.
+type header struct {
+	Name  string
+	Value string
+}
+
+// snippet is a stored request flattened into what is sent on the wire, shared by all formats.
+type snippet struct {
+	Method  string
+	URL     string
+	Headers []header
+	Cookies []header
+	// Body is the text body. BodyFile is set instead when the body is streamed from a file.
+	Body      string
+	BodyFile  string
+	Multipart *request.MultipartObject
+	Insecure  bool
+	CACert    string
+}
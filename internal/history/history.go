@@ -3,10 +3,14 @@ package history
 import (
 	"fmt"
 	"log/slog"
+	"reqcorder/internal/collection"
+	"reqcorder/internal/inbound"
 	"reqcorder/internal/record"
 	"reqcorder/pkg/utils"
 	"strconv"
 	"time"
+
+	"github.com/goccy/go-yaml"
 )
 
 // Retrieve sorted responses for a specific template hash with optional limit.
@@ -166,9 +170,14 @@ func (h *HistoryStore) GetAllRequestsSorted(limit uint64) ([][]string, error) {
 			slog.Error("Failed to get request by hash", "error", err, "requestHash", fileInfo.RequestHash)
 			return nil, err
 		}
+		environment := recordStore.Request.Environment
+		if environment == "" {
+			environment = "-"
+		}
 		data = append(data, []string{
 			recordStore.RequestHash,
 			fileInfo.TemplateHash,
+			environment,
 			fileInfo.ModTime.UTC().String(),
 		})
 	}
@@ -242,12 +251,24 @@ func (h *HistoryStore) GetAllTemplatesSorted(limit uint64) ([][]string, error) {
 		allFiles = allFiles[:min(len(allFiles), int(limit))]
 		slog.Debug("Applied limit to templates", "originalCount", len(allFiles), "limitedCount", limit)
 	}
+	baselineStore := &record.BaselineStore{
+		RecordStorePath: h.RecordStorePath,
+	}
+	baselines, err := baselineStore.GetBaselines()
+	if err != nil {
+		slog.Warn("Failed to get baselines", "error", err)
+	}
 	var data [][]string
 	for i, fileInfo := range allFiles {
 		slog.Debug("Processing template file", "index", i, "templateHash", fileInfo.TemplateHash)
+		baseline := "-"
+		if responseID, exists := baselines[fileInfo.TemplateHash]; exists {
+			baseline = responseID
+		}
 		data = append(data, []string{
 			fileInfo.TemplateHash,
 			fileInfo.ModTime.UTC().String(),
+			baseline,
 		})
 	}
 	slog.Debug("Successfully retrieved all templates sorted", "dataCount", len(data))
@@ -277,3 +298,168 @@ func (h *HistoryStore) GetTemplateByHash(templateHash string) (string, error) {
 	slog.Debug("Successfully formatted template", "templateHash", templateHash)
 	return result, nil
 }
+
+// Retrieve all collection runs sorted by descending order of modification time with optional limit.
+func (h *HistoryStore) GetAllRunsSorted(limit uint64) ([][]string, error) {
+	slog.Debug("Getting all runs sorted by modification time", "limit", limit)
+	runStore := &record.RunStore{
+		RecordStorePath: h.RecordStorePath,
+	}
+	allFiles, err := runStore.GetSortedRuns()
+	if err != nil {
+		slog.Warn("Failed to get sorted runs (may not be an error if directory is empty)", "error", err)
+	}
+	slog.Debug("Retrieved runs count", "count", len(allFiles))
+	if limit > 0 {
+		allFiles = allFiles[:min(len(allFiles), int(limit))]
+	}
+	var data [][]string
+	for i, fileInfo := range allFiles {
+		slog.Debug("Processing run file", "index", i, "runID", fileInfo.RunID)
+		runStore.RunID = fileInfo.RunID
+		run, err := h.getRun(runStore)
+		if err != nil {
+			return nil, err
+		}
+		statusIndicator := " ✅"
+		if run.Failed > 0 {
+			statusIndicator = " ❌"
+		}
+		data = append(data, []string{
+			fileInfo.RunID,
+			run.Collection,
+			fmt.Sprintf("%d/%d%s", run.Passed, run.Passed+run.Failed, statusIndicator),
+			run.Duration.String(),
+			fileInfo.ModTime.UTC().String(),
+		})
+	}
+	slog.Debug("Successfully retrieved all runs sorted", "dataCount", len(data))
+	return data, nil
+}
+
+// Retrieve a specific collection run by its ID.
+func (h *HistoryStore) GetRunByID(runID string) (string, error) {
+	slog.Debug("Getting run by ID", "runID", runID)
+	runStore := &record.RunStore{
+		RecordStorePath: h.RecordStorePath,
+		RunID:           runID,
+	}
+	err := runStore.GetRunByID()
+	if err != nil {
+		slog.Error("Failed to get run by ID", "error", err)
+		return "", err
+	}
+	run, err := utils.Prettify(string(runStore.RunYaml))
+	if err != nil {
+		slog.Error("Failed to prettify run YAML", "error", err)
+		return "", err
+	}
+	result := fmt.Sprintf("\nCollection Hash: %s\n", runStore.CollectionHash)
+	result += "Run:\n\n"
+	result += run
+	slog.Debug("Successfully formatted run", "runID", runID)
+	return result, nil
+}
+
+// Retrieve the summary of a monitor session by its ID.
+func (h *HistoryStore) GetMonitorByID(monitorID string) (string, error) {
+	slog.Debug("Getting monitor by ID", "monitorID", monitorID)
+	monitorStore := &record.MonitorStore{
+		RecordStorePath: h.RecordStorePath,
+		MonitorID:       monitorID,
+	}
+	err := monitorStore.GetMonitorByID()
+	if err != nil {
+		slog.Error("Failed to get monitor by ID", "error", err)
+		return "", err
+	}
+	monitor, err := utils.Prettify(string(monitorStore.MonitorYaml))
+	if err != nil {
+		slog.Error("Failed to prettify monitor YAML", "error", err)
+		return "", err
+	}
+	result := "\nMonitor:\n\n"
+	result += monitor
+	slog.Debug("Successfully formatted monitor", "monitorID", monitorID)
+	return result, nil
+}
+
+// Retrieve all inbound requests sorted by descending order of modification time with optional limit.
+func (h *HistoryStore) GetAllInboundSorted(limit uint64) ([][]string, error) {
+	slog.Debug("Getting all inbound requests sorted by modification time", "limit", limit)
+	inboundStore := &record.InboundStore{
+		RecordStorePath: h.RecordStorePath,
+	}
+	allFiles, err := inboundStore.GetSortedInbound()
+	if err != nil {
+		slog.Warn("Failed to get sorted inbound requests (may not be an error if directory is empty)", "error", err)
+	}
+	slog.Debug("Retrieved inbound requests count", "count", len(allFiles))
+	if limit > 0 {
+		allFiles = allFiles[:min(len(allFiles), int(limit))]
+	}
+	var data [][]string
+	for i, fileInfo := range allFiles {
+		slog.Debug("Processing inbound file", "index", i, "inboundID", fileInfo.InboundID)
+		inboundStore.InboundID = fileInfo.InboundID
+		err := inboundStore.GetInboundByID()
+		if err != nil {
+			return nil, err
+		}
+		var req inbound.Request
+		err = yaml.Unmarshal(inboundStore.InboundYaml, &req)
+		if err != nil {
+			slog.Error("Failed to decode inbound request", "error", err, "inboundID", fileInfo.InboundID)
+			return nil, fmt.Errorf("%w: %v", utils.ErrorFailedToUnmarshalYAML, err)
+		}
+		data = append(data, []string{
+			fileInfo.InboundID,
+			req.Method,
+			req.Target(),
+			req.RemoteAddr,
+			strconv.FormatInt(req.Size, 10) + " B",
+			req.ReceivedAt.UTC().Format("2006-01-02 15:04:05 +0000 UTC"),
+		})
+	}
+	slog.Debug("Successfully retrieved all inbound requests sorted", "dataCount", len(data))
+	return data, nil
+}
+
+// Retrieve a specific inbound request by its ID.
+func (h *HistoryStore) GetInboundByID(inboundID string) (string, error) {
+	slog.Debug("Getting inbound request by ID", "inboundID", inboundID)
+	inboundStore := &record.InboundStore{
+		RecordStorePath: h.RecordStorePath,
+		InboundID:       inboundID,
+	}
+	err := inboundStore.GetInboundByID()
+	if err != nil {
+		slog.Error("Failed to get inbound request by ID", "error", err)
+		return "", err
+	}
+	req, err := utils.Prettify(string(inboundStore.InboundYaml))
+	if err != nil {
+		slog.Error("Failed to prettify inbound YAML", "error", err)
+		return "", err
+	}
+	result := "\nInbound Request:\n\n"
+	result += req
+	slog.Debug("Successfully formatted inbound request", "inboundID", inboundID)
+	return result, nil
+}
+
+// Read and decode the run referenced by the run store.
+func (h *HistoryStore) getRun(runStore *record.RunStore) (*collection.RunObject, error) {
+	err := runStore.GetRunByID()
+	if err != nil {
+		slog.Error("Failed to get run by ID", "error", err, "runID", runStore.RunID)
+		return nil, err
+	}
+	var run collection.RunObject
+	err = yaml.Unmarshal(runStore.RunYaml, &run)
+	if err != nil {
+		slog.Error("Failed to decode run", "error", err, "runID", runStore.RunID)
+		return nil, fmt.Errorf("%w: %v", utils.ErrorFailedToUnmarshalYAML, err)
+	}
+	return &run, nil
+}
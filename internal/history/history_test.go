@@ -187,8 +187,8 @@ func TestSuccessfulGetAllRequestsSorted(t *testing.T) {
 	}
 
 	for _, request := range requests {
-		if len(request) != 3 {
-			t.Fatalf("Expected 3 fields in request data, got %d\n", len(request))
+		if len(request) != 4 {
+			t.Fatalf("Expected 4 fields in request data, got %d\n", len(request))
 		}
 		if request[0] == "" {
 			t.Fatal("Request hash cannot be empty")
@@ -196,7 +196,10 @@ func TestSuccessfulGetAllRequestsSorted(t *testing.T) {
 		if request[1] == "" {
 			t.Fatal("Template hash cannot be empty")
 		}
-		if request[2] == "" {
+		if request[2] != "-" {
+			t.Fatalf("Expected environment placeholder %q, received %q\n", "-", request[2])
+		}
+		if request[3] == "" {
 			t.Fatal("Timestamp cannot be empty")
 		}
 	}
@@ -929,8 +932,11 @@ func TestSuccessfulGetAllTemplatesSorted(t *testing.T) {
 	}
 
 	for _, template := range templates {
-		if len(template) != 2 {
-			t.Fatalf("Expected 2 fields in template data, received %d\n", len(template))
+		if len(template) != 3 {
+			t.Fatalf("Expected 3 fields in template data, received %d\n", len(template))
+		}
+		if template[2] != "-" {
+			t.Fatalf("Expected no baseline, received %q\n", template[2])
 		}
 		if template[0] == "" {
 			t.Fatal("Template hash cannot be empty")
@@ -1021,3 +1027,82 @@ func TestLogValue_FileInfo(t *testing.T) {
 		t.Errorf("LogValue() = %v, want %v", result, expected)
 	}
 }
+
+func TestSuccessfulGetRunByID(t *testing.T) {
+	root := t.TempDir()
+	runStore := &record.RunStore{
+		RecordStorePath: root,
+		CollectionYaml:  []byte("requests:\n  - get.yaml\n"),
+		RunYaml:         []byte("collection: smoke\nduration: 1s\npassed: 1\nfailed: 1\n"),
+	}
+	err := runStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	historyStore := &HistoryStore{
+		RecordStorePath: root,
+	}
+	res, err := historyStore.GetRunByID(runStore.RunID)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	expectedPrefix := "\nCollection Hash: " + runStore.CollectionHash + "\nRun:\n\n"
+	if len(res) < len(expectedPrefix) || res[:len(expectedPrefix)] != expectedPrefix {
+		t.Fatalf("Expected output starting with %q, received %q\n", expectedPrefix, res)
+	}
+	data, err := historyStore.GetAllRunsSorted(10)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if len(data) != 1 || data[0][0] != runStore.RunID || data[0][1] != "smoke" || data[0][2] != "1/2 ❌" || data[0][3] != "1s" {
+		t.Fatalf("Received incorrect run rows %v\n", data)
+	}
+}
+
+func TestFailedGetRunByID_NotFound(t *testing.T) {
+	root := t.TempDir()
+	historyStore := &HistoryStore{
+		RecordStorePath: root,
+	}
+	_, err := historyStore.GetRunByID(illegalHash)
+	if err == nil {
+		t.Fatal("Expected error, received nil")
+	}
+	expectedErr := record.ErrorFailedToReadDirectory
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Expected %v, received %v\n", expectedErr, err)
+	}
+}
+
+func TestSuccessfulGetInboundByID(t *testing.T) {
+	root := t.TempDir()
+	inboundStore := &record.InboundStore{
+		RecordStorePath: root,
+		InboundYaml:     []byte("method: POST\npath: /hooks\nquery:\n  id: [\"7\"]\nremote_addr: 127.0.0.1:5000\nbody: paid\nsize_bytes: 4\n"),
+	}
+	err := inboundStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	historyStore := &HistoryStore{
+		RecordStorePath: root,
+	}
+	res, err := historyStore.GetInboundByID(inboundStore.InboundID)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	expectedPrefix := "\nInbound Request:\n\n"
+	if len(res) < len(expectedPrefix) || res[:len(expectedPrefix)] != expectedPrefix {
+		t.Fatalf("Expected output starting with %q, received %q\n", expectedPrefix, res)
+	}
+	data, err := historyStore.GetAllInboundSorted(10)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if len(data) != 1 || data[0][0] != inboundStore.InboundID || data[0][1] != "POST" || data[0][2] != "/hooks?id=7" || data[0][3] != "127.0.0.1:5000" || data[0][4] != "4 B" {
+		t.Fatalf("Received incorrect inbound rows %v\n", data)
+	}
+	if _, err := historyStore.GetInboundByID(illegalHash); !errors.Is(err, record.ErrorFailedToGetInbound) {
+		t.Fatalf("Expected %v, received %v\n", record.ErrorFailedToGetInbound, err)
+	}
+}
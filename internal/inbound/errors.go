@@ -0,0 +1,7 @@
+package inbound
+
+import "errors"
+
+var (
+	ErrorFailedToLoadCertificate = errors.New("failed to load TLS certificate")
+)
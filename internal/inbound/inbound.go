@@ -0,0 +1,137 @@
+package inbound
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxBodySize is the largest request body kept. Longer bodies are truncated and answered with 413.
+const MaxBodySize = 32 << 20
+
+// Record the request and answer it with the canned reply.
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	req := &Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Host:       r.Host,
+		Proto:      r.Proto,
+		TLS:        r.TLS != nil,
+		RemoteAddr: r.RemoteAddr,
+		Headers:    flattenHeaders(r.Header),
+		ReceivedAt: start.UTC(),
+	}
+	if query := r.URL.Query(); len(query) > 0 {
+		req.Query = query
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	req.Timing.ReadBody = time.Since(start)
+	req.Body = string(body)
+	req.Size = int64(len(body))
+	status := rc.Reply.StatusCode
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if !errors.As(err, &maxBytesErr) {
+			slog.Warn("Failed to read inbound request body", "path", req.Path, "error", err)
+		} else {
+			slog.Warn("Inbound request body too large, truncating", "path", req.Path, "limit", MaxBodySize)
+			req.Truncated = true
+			status = http.StatusRequestEntityTooLarge
+		}
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if rc.Reply.Delay > 0 {
+		select {
+		case <-time.After(rc.Reply.Delay):
+		case <-r.Context().Done():
+		}
+	}
+	for key, value := range rc.Reply.Headers {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(status)
+	if _, err := io.WriteString(w, rc.Reply.Body); err != nil {
+		slog.Warn("Failed to write reply to inbound request", "path", req.Path, "error", err)
+	}
+	req.ReplyStatus = status
+	req.Timing.Total = time.Since(start)
+	slog.Debug("Received inbound request", "method", req.Method, "path", req.Path, "size", req.Size, "remoteAddr", req.RemoteAddr)
+	if rc.OnReceive != nil {
+		rc.OnReceive(req)
+	}
+}
+
+// Return the request target, the path with its query.
+func (r *Request) Target() string {
+	if len(r.Query) == 0 {
+		return r.Path
+	}
+	return r.Path + "?" + url.Values(r.Query).Encode()
+}
+
+// Load the certificate to serve HTTPS with. Without files, a self-signed certificate for hosts is generated, which
+// senders must be told to accept.
+func LoadCertificate(certFile string, keyFile string, hosts []string) (tls.Certificate, error) {
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("%w %q: %v", ErrorFailedToLoadCertificate, certFile, err)
+		}
+		return cert, nil
+	}
+	slog.Debug("Generating self-signed certificate", "hosts", hosts)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("%w: %v", ErrorFailedToLoadCertificate, err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("%w: %v", ErrorFailedToLoadCertificate, err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "ReqCorder listener", Organization: []string{"ReqCorder"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 0, 30),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("%w: %v", ErrorFailedToLoadCertificate, err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// Convert headers to map.
+func flattenHeaders(headers http.Header) map[string]string {
+	flat := make(map[string]string)
+	for key, values := range headers {
+		flat[key] = strings.Join(values, ", ")
+	}
+	return flat
+}
@@ -0,0 +1,74 @@
+package inbound
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTP(t *testing.T) {
+	var received *Request
+	receiver := &Receiver{
+		Reply: Reply{StatusCode: http.StatusAccepted, Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"ok":true}`},
+		OnReceive: func(req *Request) {
+			received = req
+		},
+	}
+	r := httptest.NewRequest("POST", "/hooks/stripe?b=2&a=1", strings.NewReader(`{"event":"paid"}`))
+	r.Header.Set("Stripe-Signature", "t=1,v1=abc")
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, r)
+
+	if recorder.Code != http.StatusAccepted || recorder.Body.String() != `{"ok":true}` || recorder.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected reply %d %q %v", recorder.Code, recorder.Body.String(), recorder.Header())
+	}
+	if received == nil {
+		t.Fatal("Expected the request to be handed over")
+	}
+	if received.Method != "POST" || received.Path != "/hooks/stripe" || received.Body != `{"event":"paid"}` || received.Size != 16 {
+		t.Errorf("Unexpected request %+v", received)
+	}
+	if !reflect.DeepEqual(received.Query, map[string][]string{"a": {"1"}, "b": {"2"}}) || received.Target() != "/hooks/stripe?a=1&b=2" {
+		t.Errorf("Unexpected query %v", received.Query)
+	}
+	if received.Headers["Stripe-Signature"] != "t=1,v1=abc" || received.RemoteAddr == "" || received.ReplyStatus != http.StatusAccepted {
+		t.Errorf("Unexpected request metadata %+v", received)
+	}
+	if received.ReceivedAt.IsZero() || received.Timing.Total < received.Timing.ReadBody {
+		t.Errorf("Unexpected timing %+v", received.Timing)
+	}
+}
+
+func TestServeHTTP_Truncated(t *testing.T) {
+	var received *Request
+	receiver := &Receiver{OnReceive: func(req *Request) { received = req }}
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest("PUT", "/upload", strings.NewReader(strings.Repeat("x", MaxBodySize+1))))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, received %d", recorder.Code)
+	}
+	if !received.Truncated || received.Size != MaxBodySize {
+		t.Errorf("Expected a truncated body of %d bytes, received %d", MaxBodySize, received.Size)
+	}
+}
+
+func TestLoadCertificate(t *testing.T) {
+	cert, err := LoadCertificate("", "", []string{"localhost", "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if parsed.VerifyHostname("localhost") != nil || parsed.VerifyHostname("127.0.0.1") != nil {
+		t.Errorf("Expected the certificate to cover the hosts, received %v %v", parsed.DNSNames, parsed.IPAddresses)
+	}
+	if _, err := LoadCertificate("missing.pem", "missing-key.pem", nil); !errors.Is(err, ErrorFailedToLoadCertificate) {
+		t.Errorf("Expected %v, received %v", ErrorFailedToLoadCertificate, err)
+	}
+}
@@ -0,0 +1,45 @@
+package inbound
+
+import "time"
+
+// Request is an HTTP request received by the listen command, as it is recorded in the store.
+type Request struct {
+	Method     string              `yaml:"method"`
+	Path       string              `yaml:"path"`
+	Query      map[string][]string `yaml:"query,omitempty"`
+	Host       string              `yaml:"host"`
+	Proto      string              `yaml:"proto"`
+	TLS        bool                `yaml:"tls"`
+	RemoteAddr string              `yaml:"remote_addr"`
+	Headers    map[string]string   `yaml:"headers"`
+	Body       string              `yaml:"body"`
+	Size       int64               `yaml:"size_bytes"`
+	// Truncated reports that the body exceeded the size limit and only its start was kept.
+	Truncated   bool      `yaml:"truncated,omitempty"`
+	ReceivedAt  time.Time `yaml:"received_at"`
+	Timing      Timing    `yaml:"timing"`
+	ReplyStatus int       `yaml:"reply_status"`
+}
+
+// Timing measures how long receiving and answering a request took.
+type Timing struct {
+	// ReadBody is the time spent reading the request body after the headers arrived.
+	ReadBody time.Duration `yaml:"read_body"`
+	// Total runs from the arrival of the headers until the reply was written, including the reply delay.
+	Total time.Duration `yaml:"total_duration"`
+}
+
+// Reply is the canned response sent for every received request.
+type Reply struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       string
+	Delay      time.Duration
+}
+
+// Receiver answers every request with the canned reply and hands it over for recording.
+type Receiver struct {
+	Reply Reply
+	// OnReceive is called with every request once the reply was written.
+	OnReceive func(req *Request)
+}
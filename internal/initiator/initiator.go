@@ -1,14 +1,19 @@
 package initiator
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptrace"
+	"net/textproto"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +23,11 @@ import (
 
 // Perform HTTP request based on the request configuration.
 func InitiateRequest(r *request.RequestObject) (*response.ResponseObject, error) {
+	return InitiateRequestWithContext(context.Background(), r)
+}
+
+// Perform HTTP request based on the request configuration. Cancelling the context aborts the request.
+func InitiateRequestWithContext(ctx context.Context, r *request.RequestObject) (*response.ResponseObject, error) {
 	slog.Debug("Initiating HTTP request", "request", r)
 	var client *http.Client
 	if (*r.SSLVerify) && r.CACertPath != "" {
@@ -28,14 +38,15 @@ func InitiateRequest(r *request.RequestObject) (*response.ResponseObject, error)
 			pool = x509.NewCertPool()
 		}
 
-		pem, err := os.ReadFile(r.CACertPath)
+		caCertPath := r.ResolvePath(r.CACertPath)
+		pem, err := os.ReadFile(caCertPath)
 		if err != nil {
-			slog.Error("Failed to read CA certificate file", "caCertPath", r.CACertPath, "error", err)
+			slog.Error("Failed to read CA certificate file", "caCertPath", caCertPath, "error", err)
 			return nil, ErrorFailedToReadCert
 		}
 		if ok := pool.AppendCertsFromPEM(pem); !ok {
-			slog.Error("Failed to append CA certificate to pool", "caCertPath", r.CACertPath)
-			return nil, fmt.Errorf("%w %q", ErrorFailedToReadCert, r.CACertPath)
+			slog.Error("Failed to append CA certificate to pool", "caCertPath", caCertPath)
+			return nil, fmt.Errorf("%w %q", ErrorFailedToReadCert, caCertPath)
 		}
 
 		tlsConfig := &tls.Config{
@@ -74,7 +85,7 @@ func InitiateRequest(r *request.RequestObject) (*response.ResponseObject, error)
 
 	var timing response.ResponseTimes
 	trace := createTrace(&timing)
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
 
 	slog.Info("Executing HTTP request", "method", r.Method, "url", r.URL, "timeout", r.Timeout)
 	start := time.Now().UTC()
@@ -116,12 +127,17 @@ func InitiateRequest(r *request.RequestObject) (*response.ResponseObject, error)
 
 // Construct request related items before initiating it.
 func constructHTTPRequest(r *request.RequestObject) (*http.Request, error) {
-	var bodyReader io.Reader
-	if r.Body != "" {
-		slog.Debug("Reading request body")
-		bodyReader = strings.NewReader(r.Body)
+	bodyReader, contentType, err := constructBody(r)
+	if err != nil {
+		slog.Error("Failed to build request body", "error", err)
+		return nil, err
+	}
+	requestURL, err := r.FullURL()
+	if err != nil {
+		slog.Error("Failed to build request URL", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrorFailedToBuildRequest, err)
 	}
-	req, err := http.NewRequest(r.Method, r.URL, bodyReader)
+	req, err := http.NewRequest(r.Method, requestURL, bodyReader)
 	if err != nil {
 		slog.Error("Failed to build HTTP request", "error", err)
 		return nil, fmt.Errorf("%w: %v", ErrorFailedToBuildRequest, err)
@@ -132,6 +148,11 @@ func constructHTTPRequest(r *request.RequestObject) (*http.Request, error) {
 		req.Header.Set(key, value)
 	}
 
+	if contentType != "" && (r.Multipart != nil || req.Header.Get("Content-Type") == "") {
+		slog.Debug("Setting body content type", "contentType", contentType)
+		req.Header.Set("Content-Type", contentType)
+	}
+
 	if r.Auth != "" {
 		headerName := r.AuthHeaderName
 		if headerName == "" {
@@ -149,6 +170,79 @@ func constructHTTPRequest(r *request.RequestObject) (*http.Request, error) {
 	return req, nil
 }
 
+// Build the request body reader and its content type from the body, body file, JSON, form, or multipart definition.
+func constructBody(r *request.RequestObject) (io.Reader, string, error) {
+	switch {
+	case r.JSON != nil:
+		slog.Debug("Encoding JSON body")
+		body, err := r.EncodeJSON()
+		if err != nil {
+			return nil, "", err
+		}
+		return strings.NewReader(body), "application/json", nil
+	case len(r.Form) > 0:
+		slog.Debug("Encoding form body", "fieldCount", len(r.Form))
+		return strings.NewReader(r.EncodeForm()), "application/x-www-form-urlencoded", nil
+	case r.Multipart != nil:
+		slog.Debug("Encoding multipart body", "fieldCount", len(r.Multipart.Fields), "fileCount", len(r.Multipart.Files))
+		return constructMultipartBody(r.Multipart)
+	case len(r.BinaryBody) > 0:
+		slog.Debug("Reading binary request body", "size", len(r.BinaryBody))
+		return bytes.NewReader(r.BinaryBody), "", nil
+	case r.Body != "":
+		slog.Debug("Reading request body")
+		return strings.NewReader(r.Body), "", nil
+	default:
+		return nil, "", nil
+	}
+}
+
+// Write multipart fields and files into a buffer and return it with the boundary content type.
+func constructMultipartBody(m *request.MultipartObject) (io.Reader, string, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	fieldNames := make([]string, 0, len(m.Fields))
+	for name := range m.Fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+	for _, name := range fieldNames {
+		for _, value := range m.Fields[name] {
+			if err := writer.WriteField(name, value); err != nil {
+				return nil, "", fmt.Errorf("%w: %v", ErrorFailedToBuildRequest, err)
+			}
+		}
+	}
+	for _, file := range m.Files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(file.Field), escapeQuotes(file.Filename)))
+		header.Set("Content-Type", file.ContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrorFailedToBuildRequest, err)
+		}
+		content, err := os.Open(file.Path)
+		if err != nil {
+			slog.Error("Failed to open multipart file", "path", file.Path, "error", err)
+			return nil, "", fmt.Errorf("%w %q: %v", ErrorFailedToBuildRequest, file.Path, err)
+		}
+		_, err = io.Copy(part, content)
+		content.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("%w %q: %v", ErrorFailedToBuildRequest, file.Path, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrorFailedToBuildRequest, err)
+	}
+	return &buffer, writer.FormDataContentType(), nil
+}
+
+// Escape quotes and backslashes for Content-Disposition parameters.
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
+
 // Capture timing stats for response.
 func createTrace(timing *response.ResponseTimes) *httptrace.ClientTrace {
 	return &httptrace.ClientTrace{
@@ -1,10 +1,13 @@
 package initiator
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -12,7 +15,9 @@ import (
 	"path/filepath"
 	"reqcorder/internal/request"
 	"reqcorder/internal/response"
+	"slices"
 	"testing"
+	"time"
 )
 
 var (
@@ -70,6 +75,123 @@ func TestSuccessfulConstructHTTPRequest(t *testing.T) {
 	}
 }
 
+func TestSuccessfulConstructHTTPRequest_Query(t *testing.T) {
+	r := &request.RequestObject{
+		Method: "GET",
+		URL:    "https://example.com/search?sort=asc",
+		Query: map[string]request.MultiValue{
+			"q":   {"hello world"},
+			"tag": {"go", "yaml"},
+		},
+	}
+	req, err := constructHTTPRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	expected := "q=hello+world&sort=asc&tag=go&tag=yaml"
+	if req.URL.RawQuery != expected {
+		t.Fatalf("Expected query %q, received %q\n", expected, req.URL.RawQuery)
+	}
+}
+
+func TestSuccessfulConstructHTTPRequest_Form(t *testing.T) {
+	r := &request.RequestObject{
+		Method: "POST",
+		URL:    "https://example.com/login",
+		Form:   map[string]request.MultiValue{"user": {"john doe"}, "remember": {"true"}},
+	}
+	req, err := constructHTTPRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Fatalf("Expected form content type, received %q\n", req.Header.Get("Content-Type"))
+	}
+	if err := req.ParseForm(); err != nil {
+		t.Fatalf("Expected no error parsing form, received %v\n", err)
+	}
+	if req.PostForm.Get("user") != "john doe" || req.PostForm.Get("remember") != "true" {
+		t.Fatalf("Unexpected form values %v\n", req.PostForm)
+	}
+}
+
+func TestSuccessfulConstructHTTPRequest_JSON(t *testing.T) {
+	r := &request.RequestObject{
+		Method: "POST",
+		URL:    "https://example.com/users",
+		JSON:   map[string]any{"id": int64(7), "name": `Jane "J" Doe`},
+	}
+	req, err := constructHTTPRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected JSON content type, received %q\n", req.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(req.Body)
+	expected := `{"id":7,"name":"Jane \"J\" Doe"}`
+	if string(body) != expected {
+		t.Fatalf("Expected body %s, received %s\n", expected, body)
+	}
+}
+
+func TestSuccessfulConstructHTTPRequest_Multipart(t *testing.T) {
+	root := t.TempDir()
+	filePath := filepath.Join(root, "report.csv")
+	if err := os.WriteFile(filePath, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v\n", err)
+	}
+	r := &request.RequestObject{
+		Method:  "POST",
+		URL:     "https://example.com/upload",
+		Headers: map[string]string{"Content-Type": "text/plain"},
+		Multipart: &request.MultipartObject{
+			Fields: map[string]request.MultiValue{"title": {"Q1"}},
+			Files: []request.MultipartFile{{
+				Field:       "report",
+				Path:        filePath,
+				Filename:    "q1.csv",
+				ContentType: "text/csv",
+			}},
+		},
+	}
+	req, err := constructHTTPRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatalf("Expected multipart body with boundary, received %v\n", err)
+	}
+	if req.MultipartForm.Value["title"][0] != "Q1" {
+		t.Fatalf("Unexpected multipart fields %v\n", req.MultipartForm.Value)
+	}
+	fileHeader := req.MultipartForm.File["report"][0]
+	if fileHeader.Filename != "q1.csv" || fileHeader.Header.Get("Content-Type") != "text/csv" || fileHeader.Size != 8 {
+		t.Fatalf("Unexpected file part %+v\n", fileHeader)
+	}
+}
+
+func TestSuccessfulConstructHTTPRequest_BinaryBody(t *testing.T) {
+	content := []byte{0x89, 0x50, 0x00, 0xff}
+	r := &request.RequestObject{
+		Method:     "PUT",
+		URL:        "https://example.com/upload",
+		BodyFile:   "/tmp/image.bin",
+		BinaryBody: content,
+	}
+	req, err := constructHTTPRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v\n", err)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("Expected no error reading body, received %v\n", err)
+	}
+	if !bytes.Equal(body, content) {
+		t.Fatalf("Expected binary body %v, received %v\n", content, body)
+	}
+}
+
 func TestConstructHTTPRequest_IllegalMethod(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -211,6 +333,83 @@ func TestSuccessfulInitiateRequest(t *testing.T) {
 	}
 }
 
+func TestSuccessfulInitiateRequest_InheritedRelativeCertPath(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	for _, sub := range []string{"shared", "services"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+	certPath := filepath.Join(dir, "shared", "ca-cert.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		t.Fatalf("failed to write cert file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shared", "base.yaml"), []byte("method: GET\nca_cert_path: ca-cert.pem\n"), 0644); err != nil {
+		t.Fatalf("failed to write base template: %v", err)
+	}
+	templatePath := filepath.Join(dir, "services", "child.yaml")
+	if err := os.WriteFile(templatePath, []byte("extends: ../shared/base.yaml\nurl: "+server.URL+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	r, _, err := request.LoadTemplate(templatePath)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if r.CACertPath != certPath {
+		t.Errorf("Expected CA certificate path %q, received %q", certPath, r.CACertPath)
+	}
+	t.Chdir(t.TempDir())
+	if dependencies := r.Dependencies(); !slices.Contains(dependencies, certPath) {
+		t.Errorf("Expected dependencies to include %q, received %v", certPath, dependencies)
+	}
+	res, err := InitiateRequest(r)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, received %d", http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestInitiateRequestWithContext_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	sslVerify := true
+	r := &request.RequestObject{
+		Method:    "GET",
+		URL:       server.URL,
+		SSLVerify: &sslVerify,
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := InitiateRequestWithContext(ctx, r)
+	if !errors.Is(err, ErrorRequestFailed) {
+		t.Fatalf("Expected %v, received %v", ErrorRequestFailed, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the request to stop when cancelled, took %s", elapsed)
+	}
+}
+
 func TestInitiateRequest_ConstructFailure(t *testing.T) {
 	sslVerify := true
 	r := &request.RequestObject{
@@ -0,0 +1,8 @@
+package mock
+
+import "errors"
+
+var (
+	ErrorNoRecordedResponses = errors.New("no recorded responses to serve")
+	ErrorInvalidPick         = errors.New("invalid response pick")
+)
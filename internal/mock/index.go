@@ -0,0 +1,168 @@
+package mock
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reqcorder/internal/record"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	bodyText = "text"
+	bodyJSON = "json"
+	bodyForm = "form"
+	// Multipart and binary bodies are not compared.
+	bodyOther = "other"
+)
+
+// Index the store into routes, one for every recorded request with a usable response. With PickLatest the most recent
+// response of each request is served. With PickBaseline the baseline of the template is served when it was recorded
+// for that request, and the most recent response otherwise. Routes are ordered most recent first.
+func LoadRoutes(recordStorePath string, pick string) ([]Route, error) {
+	slog.Debug("Indexing store for mock server", "recordStorePath", recordStorePath, "pick", pick)
+	if pick != PickLatest && pick != PickBaseline {
+		return nil, fmt.Errorf("%w %q, expected %q or %q", ErrorInvalidPick, pick, PickLatest, PickBaseline)
+	}
+	if _, err := os.Stat(filepath.Join(recordStorePath, "requests")); os.IsNotExist(err) {
+		slog.Error("No recorded requests in store", "recordStorePath", recordStorePath)
+		return nil, ErrorNoRecordedResponses
+	}
+	recordStore := record.RecordStore{RecordStorePath: recordStorePath}
+	requests, err := recordStore.GetSortedRequests()
+	if err != nil {
+		slog.Error("Failed to list recorded requests", "error", err)
+		return nil, err
+	}
+	baselines := make(map[string]string)
+	if pick == PickBaseline {
+		baselineStore := record.BaselineStore{RecordStorePath: recordStorePath}
+		baselines, err = baselineStore.GetBaselines()
+		if err != nil {
+			slog.Error("Failed to list baselines", "error", err)
+			return nil, err
+		}
+	}
+	var routes []Route
+	for _, file := range requests {
+		var req request.RequestObject
+		if err := utils.ReadYAMLFile(file.FilePath, &req); err != nil {
+			slog.Warn("Skipping unreadable request", "requestHash", file.RequestHash, "error", err)
+			continue
+		}
+		route, err := newRoute(&req)
+		if err != nil {
+			slog.Warn("Skipping request with invalid URL", "requestHash", file.RequestHash, "error", err)
+			continue
+		}
+		route.TemplateHash = file.TemplateHash
+		route.RequestHash = file.RequestHash
+		if !pickResponse(recordStorePath, &route, baselines[file.TemplateHash]) {
+			slog.Debug("Skipping request without a usable response", "requestHash", file.RequestHash)
+			continue
+		}
+		routes = append(routes, route)
+	}
+	if len(routes) == 0 {
+		slog.Error("No recorded responses to serve", "recordStorePath", recordStorePath)
+		return nil, ErrorNoRecordedResponses
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].RecordedAt.After(routes[j].RecordedAt) })
+	slog.Debug("Indexed store for mock server", "routeCount", len(routes))
+	return routes, nil
+}
+
+// Build the matching part of a route from a recorded request.
+func newRoute(req *request.RequestObject) (Route, error) {
+	fullURL, err := req.FullURL()
+	if err != nil {
+		return Route{}, err
+	}
+	parsedURL, err := url.Parse(fullURL)
+	if err != nil {
+		return Route{}, fmt.Errorf("%w %q: %v", request.ErrorInvalidURL, fullURL, err)
+	}
+	route := Route{
+		Method: strings.ToUpper(req.Method),
+		Path:   normalizePath(parsedURL.Path),
+		Query:  parsedURL.Query(),
+	}
+	switch {
+	case req.JSON != nil:
+		route.Body, err = req.EncodeJSON()
+		if err != nil {
+			return Route{}, err
+		}
+		route.BodyKind = bodyJSON
+	case len(req.Form) > 0:
+		route.Body = req.EncodeForm()
+		route.BodyKind = bodyForm
+	case req.Multipart != nil || req.BodyFileSize > 0:
+		route.BodyKind = bodyOther
+	default:
+		route.Body = req.Body
+		route.BodyKind = bodyText
+	}
+	return route, nil
+}
+
+// Set the response a route replies with. Responses of requests that failed before a reply was received are skipped.
+func pickResponse(recordStorePath string, route *Route, baselineID string) bool {
+	if baselineID != "" {
+		recordStore := record.RecordStore{RecordStorePath: recordStorePath, RequestHash: route.RequestHash, ResponseID: baselineID}
+		path := filepath.Join(recordStorePath, "responses", route.RequestHash, baselineID+".yaml")
+		if info, err := os.Stat(path); err == nil && recordStore.GetResponse() == nil && usable(recordStore.Response) {
+			route.ResponseID = baselineID
+			route.RecordedAt = info.ModTime()
+			route.Response = recordStore.Response
+			return true
+		}
+	}
+	recordStore := record.RecordStore{RecordStorePath: recordStorePath, RequestHash: route.RequestHash}
+	files, err := recordStore.GetSortedResponsesByRequestHash()
+	if err != nil {
+		return false
+	}
+	for _, file := range files {
+		recordStore.ResponseID = file.ResponseID
+		if recordStore.GetResponse() != nil || !usable(recordStore.Response) {
+			continue
+		}
+		route.ResponseID = file.ResponseID
+		route.RecordedAt = file.ModTime
+		route.Response = recordStore.Response
+		return true
+	}
+	return false
+}
+
+// A response is usable when it carries a real HTTP status code.
+func usable(res *response.ResponseObject) bool {
+	return res != nil && res.StatusCode >= 100 && res.StatusCode <= 599
+}
+
+// Paths are compared without a trailing slash.
+func normalizePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// Return the latency recorded with a route's response.
+func (r *Route) Latency() time.Duration {
+	if r.Response == nil {
+		return 0
+	}
+	return r.Response.Timing.Total
+}
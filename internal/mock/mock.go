@@ -0,0 +1,224 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
+	"reqcorder/pkg/utils"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxNearMisses is the number of near misses listed in the reply to an unmatched request.
+const maxNearMisses = 3
+
+// Headers that describe the recorded transfer rather than the response, so they are not replayed.
+var skippedHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Content-Encoding":  true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Set-Cookie":        true,
+}
+
+// Reply to a request with the recorded response of the first matching route, or with a miss.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Warn("Failed to read request body", "error", err)
+	}
+	req := &Request{
+		Method: strings.ToUpper(r.Method),
+		Path:   normalizePath(r.URL.Path),
+		Query:  r.URL.Query(),
+		Body:   string(body),
+	}
+	route := s.Match(req)
+	if route == nil {
+		status := s.replyMiss(w, req)
+		if s.OnServe != nil {
+			s.OnServe(req, nil, status)
+		}
+		return
+	}
+	slog.Debug("Matched recorded request", "method", req.Method, "path", req.Path, "requestHash", route.RequestHash, "responseId", route.ResponseID)
+	if s.Options.ReplayLatency {
+		select {
+		case <-time.After(route.Latency()):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	res := route.Response
+	for key, value := range res.Headers {
+		if skippedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		w.Header().Set(key, value)
+	}
+	for _, cookie := range res.Cookies {
+		http.SetCookie(w, cookie)
+	}
+	w.Header().Set("X-Reqcorder-Response-Id", route.ResponseID)
+	w.WriteHeader(res.StatusCode)
+	if _, err := io.WriteString(w, res.Body); err != nil {
+		slog.Warn("Failed to write response body", "error", err)
+	}
+	if s.OnServe != nil {
+		s.OnServe(req, route, res.StatusCode)
+	}
+}
+
+// Return the first route matching the request by method, path, query, and with MatchBody the body.
+func (s *Server) Match(req *Request) *Route {
+	for i := range s.Routes {
+		if len(s.differences(&s.Routes[i], req)) == 0 {
+			return &s.Routes[i]
+		}
+	}
+	return nil
+}
+
+// Write the miss reply and return its status code.
+func (s *Server) replyMiss(w http.ResponseWriter, req *Request) int {
+	status := s.Options.MissStatus
+	if status == 0 {
+		status = http.StatusNotFound
+	}
+	miss := Miss{Error: fmt.Sprintf("no recorded request matches %s %s", req.Method, requestURL(req.Path, req.Query))}
+	if s.Options.Hints {
+		miss.NearMisses = s.NearMisses(req)
+	}
+	slog.Debug("No recorded request matched", "method", req.Method, "path", req.Path, "nearMissCount", len(miss.NearMisses))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(miss); err != nil {
+		slog.Warn("Failed to write miss reply", "error", err)
+	}
+	return status
+}
+
+// Return the recorded requests closest to an unmatched request, fewest differences first. Routes are close when they
+// have the same path, or the same method and a path differing in a single segment.
+func (s *Server) NearMisses(req *Request) []NearMiss {
+	var nearMisses []NearMiss
+	for i := range s.Routes {
+		route := &s.Routes[i]
+		if route.Path != req.Path && (route.Method != req.Method || !oneSegmentApart(route.Path, req.Path)) {
+			continue
+		}
+		nearMisses = append(nearMisses, NearMiss{
+			Method:      route.Method,
+			URL:         requestURL(route.Path, route.Query),
+			RequestHash: route.RequestHash,
+			Differences: s.differences(route, req),
+		})
+	}
+	sort.SliceStable(nearMisses, func(i, j int) bool { return len(nearMisses[i].Differences) < len(nearMisses[j].Differences) })
+	if len(nearMisses) > maxNearMisses {
+		nearMisses = nearMisses[:maxNearMisses]
+	}
+	return nearMisses
+}
+
+// Describe how a request differs from a route. No differences means the route matches.
+func (s *Server) differences(route *Route, req *Request) []string {
+	var differences []string
+	if route.Method != req.Method {
+		differences = append(differences, fmt.Sprintf("method: recorded %s", route.Method))
+	}
+	if route.Path != req.Path {
+		differences = append(differences, fmt.Sprintf("path: recorded %s", route.Path))
+	}
+	differences = append(differences, queryDifferences(route.Query, req.Query)...)
+	if s.Options.MatchBody && !bodyMatches(route, req.Body) {
+		differences = append(differences, "body: differs from the recorded body")
+	}
+	return differences
+}
+
+// Compare query parameters, ignoring the order of parameters and of repeated values.
+func queryDifferences(recorded url.Values, received url.Values) []string {
+	var differences []string
+	for _, key := range utils.SortedKeys(recorded) {
+		values, ok := received[key]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("query %q: missing, recorded %q", key, strings.Join(recorded[key], ",")))
+			continue
+		}
+		if !sameValues(recorded[key], values) {
+			differences = append(differences, fmt.Sprintf("query %q: recorded %q", key, strings.Join(recorded[key], ",")))
+		}
+	}
+	for _, key := range utils.SortedKeys(received) {
+		if _, ok := recorded[key]; !ok {
+			differences = append(differences, fmt.Sprintf("query %q: not recorded", key))
+		}
+	}
+	return differences
+}
+
+func sameValues(a []string, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// Compare a request body with the recorded body. JSON and form bodies are compared by value, so key order and
+// formatting do not matter. Multipart and binary bodies always match.
+func bodyMatches(route *Route, body string) bool {
+	switch route.BodyKind {
+	case bodyJSON:
+		var recorded, received any
+		if json.Unmarshal([]byte(route.Body), &recorded) != nil || json.Unmarshal([]byte(body), &received) != nil {
+			return route.Body == body
+		}
+		return reflect.DeepEqual(recorded, received)
+	case bodyForm:
+		recorded, err := url.ParseQuery(route.Body)
+		if err != nil {
+			return route.Body == body
+		}
+		received, err := url.ParseQuery(body)
+		if err != nil {
+			return false
+		}
+		return len(queryDifferences(recorded, received)) == 0
+	case bodyOther:
+		return true
+	default:
+		return route.Body == body
+	}
+}
+
+// Report whether two paths have the same number of segments and differ in exactly one.
+func oneSegmentApart(a string, b string) bool {
+	aSegments, bSegments := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(aSegments) != len(bSegments) {
+		return false
+	}
+	different := 0
+	for i := range aSegments {
+		if aSegments[i] != bSegments[i] {
+			different++
+		}
+	}
+	return different == 1
+}
+
+func requestURL(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
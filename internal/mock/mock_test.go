@@ -0,0 +1,173 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reqcorder/internal/record"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"strings"
+	"testing"
+)
+
+func testRoutes() []Route {
+	return []Route{
+		{
+			Method:     "GET",
+			Path:       "/users/1",
+			Query:      url.Values{"fields": {"name", "email"}},
+			BodyKind:   bodyText,
+			ResponseID: "r1",
+			Response: &response.ResponseObject{
+				StatusCode: 200,
+				Headers:    map[string]string{"Content-Type": "application/json", "Content-Length": "99"},
+				Cookies:    []*http.Cookie{{Name: "session", Value: "abc"}},
+				Body:       `{"name":"john"}`,
+			},
+		},
+		{
+			Method:     "POST",
+			Path:       "/users",
+			Query:      url.Values{},
+			Body:       `{"name":"jane","age":30}`,
+			BodyKind:   bodyJSON,
+			ResponseID: "r2",
+			Response:   &response.ResponseObject{StatusCode: 201, Body: `{"id":2}`},
+		},
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		matchBody      bool
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Match with reordered query", method: "GET", target: "/users/1/?fields=email&fields=name", expectedStatus: 200, expectedBody: `{"name":"john"}`},
+		{name: "Missing query parameter", method: "GET", target: "/users/1", expectedStatus: 404},
+		{name: "Method is case insensitive", method: "post", target: "/users", body: "ignored", expectedStatus: 201},
+		{name: "Reordered JSON body", matchBody: true, method: "POST", target: "/users", body: `{"age":30, "name":"jane"}`, expectedStatus: 201},
+		{name: "Different JSON body", matchBody: true, method: "POST", target: "/users", body: `{"name":"john"}`, expectedStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Routes: testRoutes(), Options: Options{MatchBody: tt.matchBody}}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, received %d", tt.expectedStatus, recorder.Code)
+			}
+			if tt.expectedBody != "" && recorder.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, received %q", tt.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeHTTP_ReplaysHeadersAndCookies(t *testing.T) {
+	server := httptest.NewServer(&Server{Routes: testRoutes()})
+	defer server.Close()
+	res, err := http.Get(server.URL + "/users/1?fields=name&fields=email")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.Header.Get("Content-Type") != "application/json" || res.Header.Get("X-Reqcorder-Response-Id") != "r1" {
+		t.Errorf("Unexpected headers %v", res.Header)
+	}
+	if res.ContentLength != int64(len(body)) {
+		t.Errorf("Expected content length %d, received %d", len(body), res.ContentLength)
+	}
+	if cookies := res.Cookies(); len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "abc" {
+		t.Errorf("Unexpected cookies %v", cookies)
+	}
+}
+
+func TestServeHTTP_Miss(t *testing.T) {
+	server := &Server{Routes: testRoutes(), Options: Options{MissStatus: 501, Hints: true}}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/2?fields=name", nil))
+	if recorder.Code != 501 {
+		t.Fatalf("Expected status 501, received %d", recorder.Code)
+	}
+	var miss Miss
+	if err := json.Unmarshal(recorder.Body.Bytes(), &miss); err != nil {
+		t.Fatalf("Expected JSON miss reply, received %v", err)
+	}
+	if len(miss.NearMisses) != 1 || miss.NearMisses[0].Method != "GET" {
+		t.Fatalf("Expected the GET route as near miss, received %+v", miss.NearMisses)
+	}
+	expected := []string{"path: recorded /users/1", `query "fields": recorded "name,email"`}
+	if strings.Join(miss.NearMisses[0].Differences, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected differences %v, received %v", expected, miss.NearMisses[0].Differences)
+	}
+
+	server.Options.Hints = false
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/2", nil))
+	if strings.Contains(recorder.Body.String(), "near_misses") {
+		t.Errorf("Expected no near misses without hints, received %s", recorder.Body.String())
+	}
+}
+
+func TestLoadRoutes(t *testing.T) {
+	root := t.TempDir()
+	recordResponse := func(status int, body string) string {
+		recordStore := &record.RecordStore{
+			RecordStorePath: root,
+			TemplateYaml:    []byte("url: http://example.com/items/"),
+			Request:         &request.RequestObject{Method: "GET", URL: "http://example.com/items/", Query: map[string]request.MultiValue{"page": {"1"}}},
+			Response:        &response.ResponseObject{StatusCode: status, Body: body},
+		}
+		if err := recordStore.Record(); err != nil {
+			t.Fatalf("Expected no error, received %v", err)
+		}
+		return recordStore.ResponseID
+	}
+	baselineID := recordResponse(200, "first")
+	recordResponse(200, "second")
+	recordResponse(1000, "")
+
+	routes, err := LoadRoutes(root, PickLatest)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if len(routes) != 1 || routes[0].Method != "GET" || routes[0].Path != "/items" || routes[0].Query.Get("page") != "1" {
+		t.Fatalf("Unexpected routes %+v", routes)
+	}
+	if routes[0].Response.Body != "second" {
+		t.Errorf("Expected the latest usable response, received %q", routes[0].Response.Body)
+	}
+
+	baselineStore := &record.BaselineStore{RecordStorePath: root, TemplateHash: routes[0].TemplateHash, Baseline: &record.Baseline{
+		TemplateHash: routes[0].TemplateHash,
+		RequestHash:  routes[0].RequestHash,
+		ResponseID:   baselineID,
+	}}
+	if err := baselineStore.Record(); err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	routes, err = LoadRoutes(root, PickBaseline)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if routes[0].ResponseID != baselineID || routes[0].Response.Body != "first" {
+		t.Errorf("Expected the baseline response, received %q", routes[0].Response.Body)
+	}
+
+	if _, err := LoadRoutes(root, "oldest"); !errors.Is(err, ErrorInvalidPick) {
+		t.Errorf("Expected %v, received %v", ErrorInvalidPick, err)
+	}
+	if _, err := LoadRoutes(t.TempDir(), PickLatest); !errors.Is(err, ErrorNoRecordedResponses) {
+		t.Errorf("Expected %v, received %v", ErrorNoRecordedResponses, err)
+	}
+}
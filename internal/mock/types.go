@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"net/url"
+	"reqcorder/internal/response"
+	"time"
+)
+
+const (
+	PickLatest   = "latest"
+	PickBaseline = "baseline"
+)
+
+// Route is a recorded request answered by the mock server, with the recorded response it replies with.
+type Route struct {
+	Method       string
+	Path         string
+	Query        url.Values
+	Body         string
+	BodyKind     string
+	TemplateHash string
+	RequestHash  string
+	ResponseID   string
+	RecordedAt   time.Time
+	Response     *response.ResponseObject
+}
+
+// Options control how the mock server matches requests and replies.
+type Options struct {
+	// MatchBody also compares the request body. JSON and form bodies are compared by value.
+	MatchBody bool
+	// ReplayLatency delays every reply by the total duration recorded with the response.
+	ReplayLatency bool
+	// MissStatus is the status code returned when no recorded request matches.
+	MissStatus int
+	// Hints lists the closest recorded requests in the reply to unmatched requests.
+	Hints bool
+}
+
+// Server replies to HTTP requests with recorded responses.
+type Server struct {
+	Routes  []Route
+	Options Options
+	// OnServe is called after every reply, with the matched route or nil on a miss.
+	OnServe func(r *Request, route *Route, status int)
+}
+
+// Request is the part of an incoming request that is matched against routes.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   string
+}
+
+// NearMiss is a recorded request that almost matches an incoming request.
+type NearMiss struct {
+	Method      string   `json:"method"`
+	URL         string   `json:"url"`
+	RequestHash string   `json:"request_hash"`
+	Differences []string `json:"differences"`
+}
+
+// Miss is the reply body for requests that match no recorded request.
+type Miss struct {
+	Error      string     `json:"error"`
+	NearMisses []NearMiss `json:"near_misses,omitempty"`
+}
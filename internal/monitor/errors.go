@@ -0,0 +1,7 @@
+package monitor
+
+import "errors"
+
+var (
+	ErrorThresholdBreached = errors.New("monitor threshold breached")
+)
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+)
+
+// Window keeps the most recent samples, up to its size.
+type Window struct {
+	size    int
+	samples []Sample
+	next    int
+}
+
+// Create a window holding at most size samples.
+func NewWindow(size int) *Window {
+	if size < 1 {
+		size = 1
+	}
+	return &Window{size: size, samples: make([]Sample, 0, size)}
+}
+
+// Add a sample, dropping the oldest one when the window is full.
+func (w *Window) Add(sample Sample) {
+	if len(w.samples) < w.size {
+		w.samples = append(w.samples, sample)
+		return
+	}
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % w.size
+}
+
+// Return the samples in the window, oldest first.
+func (w *Window) Samples() []Sample {
+	samples := make([]Sample, 0, len(w.samples))
+	samples = append(samples, w.samples[w.next:]...)
+	return append(samples, w.samples[:w.next]...)
+}
+
+// Summarize the samples in the window.
+func (w *Window) Stats() Stats {
+	return Summarize(w.Samples())
+}
+
+// Summarize samples into counts, the error rate, and latency percentiles. Requests that got no response
+// count as failed but are left out of the latency percentiles.
+func Summarize(samples []Sample) Stats {
+	var stats Stats
+	for _, sample := range samples {
+		stats.Add(sample)
+		if sample.StatusCode != 0 {
+			stats.latencies = append(stats.latencies, sample.Latency)
+		}
+	}
+	sort.Slice(stats.latencies, func(i, j int) bool { return stats.latencies[i] < stats.latencies[j] })
+	stats.Latency = LatencyPercentiles{
+		P50: stats.Percentile(50),
+		P90: stats.Percentile(90),
+		P95: stats.Percentile(95),
+		P99: stats.Percentile(99),
+		Max: stats.Percentile(100),
+	}
+	return stats
+}
+
+// Count a sample towards the stats without keeping it. Latency percentiles need every sample and are only
+// computed by Summarize.
+func (s *Stats) Add(sample Sample) {
+	s.Requests++
+	if !sample.Passed {
+		s.Failed++
+	}
+	s.ErrorRate = float64(s.Failed) * 100 / float64(s.Requests)
+	s.AssertionCount += sample.AssertionCount
+	s.FailedAssertions += sample.FailedAssertions
+	if sample.StatusCode == 0 {
+		return
+	}
+	if s.StatusCodes == nil {
+		s.StatusCodes = make(map[int]int)
+	}
+	s.StatusCodes[sample.StatusCode]++
+}
+
+// Return the latency at a percentile between 0 and 100, using the nearest rank. Zero is returned without latencies.
+func (s Stats) Percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.latencies))))
+	rank = min(max(rank, 1), len(s.latencies))
+	return s.latencies[rank-1]
+}
+
+// Check window stats against the thresholds. The error describes the first breached threshold.
+func (t Thresholds) Check(s Stats) error {
+	if s.Requests == 0 {
+		return nil
+	}
+	if t.MaxErrorRate > 0 && s.ErrorRate > t.MaxErrorRate {
+		slog.Debug("Error rate threshold breached", "errorRate", s.ErrorRate, "maxErrorRate", t.MaxErrorRate)
+		return fmt.Errorf("%w: error rate %s above %s", ErrorThresholdBreached, FormatRate(s.ErrorRate), FormatRate(t.MaxErrorRate))
+	}
+	if t.MaxLatency > 0 {
+		latency := s.Percentile(t.Percentile)
+		if latency > t.MaxLatency {
+			slog.Debug("Latency threshold breached", "percentile", t.Percentile, "latency", latency, "maxLatency", t.MaxLatency)
+			return fmt.Errorf("%w: p%s latency %s above %s", ErrorThresholdBreached, formatNumber(t.Percentile), latency.Round(time.Microsecond), t.MaxLatency)
+		}
+	}
+	return nil
+}
+
+// Format a percentage with at most one decimal.
+func FormatRate(rate float64) string {
+	return formatNumber(math.Round(rate*10)/10) + "%"
+}
+
+func formatNumber(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	w := NewWindow(3)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		w.Add(Sample{Name: name})
+	}
+	var names []string
+	for _, sample := range w.Samples() {
+		names = append(names, sample.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"c", "d", "e"}) {
+		t.Errorf("Expected the three most recent samples oldest first, received %v", names)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var samples []Sample
+	for i := 1; i <= 10; i++ {
+		samples = append(samples, Sample{StatusCode: 200, Latency: time.Duration(i) * 10 * time.Millisecond, Passed: true, AssertionCount: 2})
+	}
+	samples = append(samples,
+		Sample{StatusCode: 503, Latency: 500 * time.Millisecond, AssertionCount: 2, FailedAssertions: 1},
+		Sample{Error: "connection refused"},
+	)
+	stats := Summarize(samples)
+	if stats.Requests != 12 || stats.Failed != 2 || stats.AssertionCount != 22 || stats.FailedAssertions != 1 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+	if !reflect.DeepEqual(stats.StatusCodes, map[int]int{200: 10, 503: 1}) {
+		t.Errorf("Unexpected status codes %v", stats.StatusCodes)
+	}
+	if FormatRate(stats.ErrorRate) != "16.7%" {
+		t.Errorf("Expected error rate 16.7%%, received %s", FormatRate(stats.ErrorRate))
+	}
+	expected := LatencyPercentiles{P50: 60 * time.Millisecond, P90: 100 * time.Millisecond, P95: 500 * time.Millisecond, P99: 500 * time.Millisecond, Max: 500 * time.Millisecond}
+	if stats.Latency != expected {
+		t.Errorf("Expected latency %+v, received %+v", expected, stats.Latency)
+	}
+	if empty := Summarize(nil); empty.ErrorRate != 0 || empty.Latency != (LatencyPercentiles{}) {
+		t.Errorf("Expected empty stats, received %+v", empty)
+	}
+}
+
+func TestStatsAdd(t *testing.T) {
+	samples := []Sample{
+		{StatusCode: 200, Latency: 10 * time.Millisecond, Passed: true, AssertionCount: 1},
+		{StatusCode: 500, Latency: 20 * time.Millisecond, AssertionCount: 1, FailedAssertions: 1},
+		{Error: "timeout"},
+	}
+	var totals Stats
+	for _, sample := range samples {
+		totals.Add(sample)
+	}
+	summarized := Summarize(samples)
+	if totals.Requests != summarized.Requests || totals.Failed != summarized.Failed || totals.ErrorRate != summarized.ErrorRate ||
+		totals.AssertionCount != summarized.AssertionCount || totals.FailedAssertions != summarized.FailedAssertions {
+		t.Errorf("Expected counts of %+v, received %+v", summarized, totals)
+	}
+	if !reflect.DeepEqual(totals.StatusCodes, summarized.StatusCodes) {
+		t.Errorf("Expected status codes %v, received %v", summarized.StatusCodes, totals.StatusCodes)
+	}
+	if totals.Latency != (LatencyPercentiles{}) || len(totals.latencies) != 0 {
+		t.Errorf("Expected no latencies to be kept, received %+v", totals)
+	}
+}
+
+func TestThresholdsCheck(t *testing.T) {
+	stats := Summarize([]Sample{
+		{StatusCode: 200, Latency: 100 * time.Millisecond, Passed: true},
+		{StatusCode: 200, Latency: 200 * time.Millisecond, Passed: true},
+		{StatusCode: 200, Latency: 300 * time.Millisecond, Passed: true},
+		{StatusCode: 500, Latency: 900 * time.Millisecond},
+	})
+	tests := []struct {
+		name        string
+		thresholds  Thresholds
+		expectedErr error
+	}{
+		{name: "No thresholds", thresholds: Thresholds{}},
+		{name: "Error rate within limit", thresholds: Thresholds{MaxErrorRate: 25}},
+		{name: "Error rate breached", thresholds: Thresholds{MaxErrorRate: 10}, expectedErr: ErrorThresholdBreached},
+		{name: "Latency within limit", thresholds: Thresholds{MaxLatency: 300 * time.Millisecond, Percentile: 75}},
+		{name: "Latency breached", thresholds: Thresholds{MaxLatency: 300 * time.Millisecond, Percentile: 95}, expectedErr: ErrorThresholdBreached},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.thresholds.Check(stats)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, received %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package monitor
+
+import "time"
+
+// Sample is the outcome of one request sent by the monitor.
+type Sample struct {
+	Name             string
+	StatusCode       int
+	Latency          time.Duration
+	Passed           bool
+	AssertionCount   int
+	FailedAssertions int
+	Error            string
+}
+
+// Thresholds end the monitor when the rolling window breaches them. Zero values are not checked.
+type Thresholds struct {
+	// MaxErrorRate is the highest share of failed requests allowed in the window, in percent.
+	MaxErrorRate float64 `yaml:"max_error_rate,omitempty"`
+	// MaxLatency is the highest latency allowed at Percentile.
+	MaxLatency time.Duration `yaml:"max_latency,omitempty"`
+	Percentile float64       `yaml:"percentile,omitempty"`
+}
+
+// Stats summarize a set of samples.
+type Stats struct {
+	Requests         int                `yaml:"requests"`
+	Failed           int                `yaml:"failed"`
+	ErrorRate        float64            `yaml:"error_rate"`
+	StatusCodes      map[int]int        `yaml:"status_codes,omitempty"`
+	AssertionCount   int                `yaml:"assertions,omitempty"`
+	FailedAssertions int                `yaml:"failed_assertions,omitempty"`
+	Latency          LatencyPercentiles `yaml:"latency,omitempty"`
+	latencies        []time.Duration
+}
+
+// LatencyPercentiles are computed from the total duration of the responses that were received.
+type LatencyPercentiles struct {
+	P50 time.Duration `yaml:"p50"`
+	P90 time.Duration `yaml:"p90"`
+	P95 time.Duration `yaml:"p95"`
+	P99 time.Duration `yaml:"p99"`
+	Max time.Duration `yaml:"max"`
+}
+
+// Summary is the recorded outcome of a monitor session.
+type Summary struct {
+	Target      string        `yaml:"target"`
+	TargetPath  string        `yaml:"target_path"`
+	Kind        string        `yaml:"kind"`
+	Environment string        `yaml:"environment,omitempty"`
+	Interval    time.Duration `yaml:"interval"`
+	Window      int           `yaml:"window"`
+	Thresholds  Thresholds    `yaml:"thresholds"`
+	StartedAt   time.Time     `yaml:"started_at"`
+	Duration    time.Duration `yaml:"duration"`
+	Checks      int           `yaml:"checks"`
+	// StopReason is completed, interrupted, or breached.
+	StopReason string `yaml:"stop_reason"`
+	Breach     string `yaml:"breach,omitempty"`
+	// Totals count every request of the session. Samples are only kept for the rolling window,
+	// so latency percentiles are only known for LastWindow.
+	Totals Stats `yaml:"totals"`
+	// LastWindow holds the stats of the rolling window when the monitor stopped.
+	LastWindow Stats `yaml:"last_window"`
+}
@@ -0,0 +1,148 @@
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"sync"
+	"time"
+)
+
+const (
+	caCertFile = "ca.pem"
+	caKeyFile  = "ca-key.pem"
+)
+
+// CA issues the certificates presented to clients when TLS traffic is intercepted.
+type CA struct {
+	// CertPath is the PEM file clients must trust for interception to work.
+	CertPath string
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	mu       sync.Mutex
+	leaves   map[string]*tls.Certificate
+}
+
+// Load the CA kept in dir, generating and saving a new one the first time. The key is only readable by the owner.
+func LoadOrCreateCA(dir string) (*CA, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+	if errors.Is(certErr, os.ErrNotExist) && errors.Is(keyErr, os.ErrNotExist) {
+		slog.Debug("Generating proxy CA", "dir", dir)
+		var err error
+		certPEM, keyPEM, err = generateCA()
+		if err != nil {
+			return nil, err
+		}
+		if err := utils.EnsureDir(dir); err != nil {
+			return nil, errors.Join(ErrorFailedToLoadCA, err)
+		}
+		if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, err)
+		}
+		if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, err)
+		}
+	} else if certErr != nil || keyErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, errors.Join(certErr, keyErr))
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToLoadCA, certPath, err)
+	}
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w %q: unsupported key type", ErrorFailedToLoadCA, keyPath)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToLoadCA, certPath, err)
+	}
+	slog.Debug("Loaded proxy CA", "certPath", certPath, "notAfter", cert.NotAfter)
+	return &CA{CertPath: certPath, cert: cert, key: key, leaves: make(map[string]*tls.Certificate)}, nil
+}
+
+// Generate a self-signed CA certificate and key, PEM encoded.
+func generateCA() ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "ReqCorder Proxy CA", Organization: []string{"ReqCorder"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrorFailedToLoadCA, err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// Return a certificate for host signed by the CA. Certificates are issued once per host and kept in memory.
+func (c *CA) certificateFor(host string) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if leaf, ok := c.leaves[host]; ok {
+		return leaf, nil
+	}
+	slog.Debug("Issuing certificate for intercepted host", "host", host)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	if err != nil {
+		return nil, err
+	}
+	leaf := &tls.Certificate{Certificate: [][]byte{der, c.cert.Raw}, PrivateKey: key}
+	c.leaves[host] = leaf
+	return leaf, nil
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
@@ -0,0 +1,7 @@
+package proxy
+
+import "errors"
+
+var (
+	ErrorFailedToLoadCA = errors.New("failed to load proxy CA")
+)
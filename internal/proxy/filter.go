@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"path"
+	"strings"
+)
+
+// Filter limits which exchanges are recorded. An empty list matches everything.
+type Filter struct {
+	// Hosts are host names without port, or globs such as *.example.com.
+	Hosts []string
+	// Paths are path prefixes such as /api, or globs such as /users/*/orders.
+	Paths []string
+}
+
+// Report whether an exchange with this host and path is recorded.
+func (f Filter) Match(host string, urlPath string) bool {
+	if !f.MatchHost(host) {
+		return false
+	}
+	if len(f.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range f.Paths {
+		if matchPath(pattern, urlPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Report whether some exchanges with this host may be recorded. TLS traffic to other hosts is tunneled untouched.
+func (f Filter) MatchHost(host string) bool {
+	if len(f.Hosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, pattern := range f.Hosts {
+		if matched, err := path.Match(strings.ToLower(pattern), host); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(pattern string, urlPath string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, urlPath)
+		return err == nil && matched
+	}
+	prefix := strings.TrimSuffix(pattern, "/")
+	return urlPath == prefix || strings.HasPrefix(urlPath, prefix+"/") || prefix == ""
+}
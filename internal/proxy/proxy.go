@@ -0,0 +1,401 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+	"reqcorder/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+// templateComment heads every synthetic template, so they are told apart from written ones.
+const templateComment = "# Recorded by reqcorder proxy\n"
+
+// Headers that only concern a single connection. They are neither forwarded nor recorded.
+var hopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Headers left out of recorded templates, because they are set again when the template is sent or live in other keys.
+var unrecordedHeaders = map[string]bool{
+	"Host":            true,
+	"Content-Length":  true,
+	"Accept-Encoding": true,
+	"Cookie":          true,
+	"User-Agent":      true,
+}
+
+// Create a proxy. With a CA, TLS traffic to hosts matching the filter is intercepted.
+func New(filter Filter, ca *CA, insecure bool) *Proxy {
+	return &Proxy{
+		Filter:   filter,
+		CA:       ca,
+		Insecure: insecure,
+		transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: insecure},
+			MaxIdleConnsPerHost: 16,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// Forward a proxy request, or open a tunnel for CONNECT.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		if p.CA == nil || !p.Filter.MatchHost(r.URL.Hostname()) {
+			p.tunnel(w, r)
+			return
+		}
+		p.intercept(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "reqcorder proxy only accepts proxy requests", http.StatusBadRequest)
+		return
+	}
+	p.forward(w, r)
+}
+
+// Send a request upstream and copy the response back. Matching exchanges are buffered for recording, others are
+// streamed as they are.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	exchange := &Exchange{
+		Matched: p.Filter.Match(r.URL.Hostname(), r.URL.Path),
+		Method:  r.Method,
+		URL:     r.URL.String(),
+	}
+	defer p.report(exchange)
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	removeHopHeaders(out.Header)
+	if exchange.Matched {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			slog.Error("Failed to read proxied request body", "url", exchange.URL, "error", err)
+			exchange.Err = err
+			exchange.StatusCode = http.StatusBadRequest
+			http.Error(w, "reqcorder proxy failed to read the request body", http.StatusBadRequest)
+			return
+		}
+		out.Body, out.ContentLength = http.NoBody, 0
+		if len(body) > 0 {
+			out.Body, out.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+		}
+		// Let the transport negotiate compression, so recorded bodies are decoded.
+		out.Header.Del("Accept-Encoding")
+		exchange.Request, exchange.TemplateYaml, err = p.newRequest(r, body)
+		if err != nil {
+			slog.Error("Failed to build request for recording", "url", exchange.URL, "error", err)
+			exchange.Matched = false
+		}
+	}
+	slog.Debug("Forwarding proxied request", "method", r.Method, "url", exchange.URL, "matched", exchange.Matched)
+	start := time.Now()
+	res, err := p.transport.RoundTrip(out)
+	if err != nil {
+		slog.Error("Proxied request failed", "url", exchange.URL, "error", err)
+		exchange.Err = err
+		exchange.StatusCode = http.StatusBadGateway
+		exchange.Response = &response.ResponseObject{
+			StatusCode: 1000,
+			Body:       "This request failed: " + err.Error() + "\n Refer to the logs for more details",
+			Timing:     response.ResponseTimes{Total: time.Since(start)},
+		}
+		http.Error(w, "reqcorder proxy failed to reach the upstream server: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+	exchange.StatusCode = res.StatusCode
+	removeHopHeaders(res.Header)
+	for key, values := range res.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	if !exchange.Matched {
+		w.WriteHeader(res.StatusCode)
+		if err := streamBody(w, res.Body); err != nil {
+			slog.Warn("Failed to stream proxied response", "url", exchange.URL, "error", err)
+		}
+		return
+	}
+	body, err := io.ReadAll(res.Body)
+	total := time.Since(start)
+	if err != nil {
+		slog.Error("Failed to read proxied response body", "url", exchange.URL, "error", err)
+		exchange.Err = err
+		exchange.Response = nil
+	}
+	w.Header().Del("Content-Length")
+	w.WriteHeader(res.StatusCode)
+	if _, err := w.Write(body); err != nil {
+		slog.Warn("Failed to write proxied response", "url", exchange.URL, "error", err)
+	}
+	if exchange.Err != nil {
+		return
+	}
+	exchange.Response = &response.ResponseObject{
+		StatusCode: res.StatusCode,
+		Headers:    flattenHeaders(res.Header),
+		Body:       string(body),
+		Size:       int64(len(body)),
+		Timing:     response.ResponseTimes{Total: total},
+		Cookies:    res.Cookies(),
+	}
+}
+
+func (p *Proxy) report(exchange *Exchange) {
+	if !exchange.Matched || exchange.Response == nil {
+		exchange.Request, exchange.TemplateYaml, exchange.Response = nil, nil, nil
+	}
+	if p.OnExchange != nil {
+		p.OnExchange(exchange)
+	}
+}
+
+// Build the request and the synthetic template recorded for a proxied request.
+func (p *Proxy) newRequest(r *http.Request, body []byte) (*request.RequestObject, []byte, error) {
+	u := url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host, Path: r.URL.Path, RawPath: r.URL.RawPath}
+	if (u.Scheme == "https" && u.Port() == "443") || (u.Scheme == "http" && u.Port() == "80") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+u.Port())
+	}
+	var query map[string]request.MultiValue
+	for key, values := range r.URL.Query() {
+		if query == nil {
+			query = make(map[string]request.MultiValue)
+		}
+		query[key] = request.MultiValue(values)
+	}
+	hop := make(map[string]bool)
+	for _, key := range hopHeaders {
+		hop[key] = true
+	}
+	var headers map[string]string
+	for key, values := range r.Header {
+		if hop[key] || unrecordedHeaders[key] {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[key] = strings.Join(values, ", ")
+	}
+	var cookies map[string]string
+	for _, cookie := range r.Cookies() {
+		if cookies == nil {
+			cookies = make(map[string]string)
+		}
+		cookies[cookie.Name] = cookie.Value
+	}
+	verify := !p.Insecure
+	t := template{
+		URL:       u.String(),
+		Query:     query,
+		Method:    r.Method,
+		Headers:   headers,
+		Cookies:   cookies,
+		UserAgent: r.UserAgent(),
+		Body:      string(body),
+	}
+	if !verify {
+		t.SSLVerify = &verify
+	}
+	templateYaml, err := utils.ConvertToYAML(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := &request.RequestObject{
+		URL:       t.URL,
+		Query:     query,
+		Method:    r.Method,
+		Headers:   headers,
+		Cookies:   cookies,
+		UserAgent: t.UserAgent,
+		Body:      t.Body,
+		SSLVerify: &verify,
+	}
+	return req, append([]byte(templateComment), templateYaml...), nil
+}
+
+// Relay bytes between the client and the requested host without looking at them.
+func (p *Proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("Tunneling connection", "host", r.Host)
+	upstream, err := net.DialTimeout("tcp", r.Host, dialTimeout)
+	if err != nil {
+		slog.Error("Failed to connect to tunneled host", "host", r.Host, "error", err)
+		http.Error(w, "reqcorder proxy failed to reach the upstream server: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	client, err := hijack(w)
+	if err != nil {
+		slog.Error("Failed to take over client connection", "error", err)
+		upstream.Close()
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		io.Copy(upstream, client)
+		upstream.Close()
+	})
+	wg.Go(func() {
+		io.Copy(client, upstream)
+		client.Close()
+	})
+	wg.Wait()
+}
+
+// Terminate TLS with a certificate issued by the CA and proxy the decrypted requests.
+func (p *Proxy) intercept(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("Intercepting TLS connection", "host", r.Host)
+	client, err := hijack(w)
+	if err != nil {
+		slog.Error("Failed to take over client connection", "error", err)
+		return
+	}
+	hostname := r.URL.Hostname()
+	tlsConn := tls.Server(client, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName != "" {
+				return p.CA.certificateFor(hello.ServerName)
+			}
+			return p.CA.certificateFor(hostname)
+		},
+	})
+	authority := r.Host
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.URL.Scheme = "https"
+			r.URL.Host = authority
+			p.forward(w, r)
+		}),
+		ReadHeaderTimeout: dialTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelDebug),
+	}
+	server.Serve(newConnListener(tlsConn))
+}
+
+// Take over the client connection and confirm the tunnel.
+func hijack(w http.ResponseWriter) (net.Conn, error) {
+	conn, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &bufferedConn{Conn: conn, reader: buffered.Reader}, nil
+}
+
+// bufferedConn reads what the server already buffered before reading from the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connListener hands a single connection to an http.Server and blocks further accepts until it is closed.
+type connListener struct {
+	conn     net.Conn
+	accepted bool
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	return &connListener{conn: conn, done: make(chan struct{})}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	if !l.accepted {
+		l.accepted = true
+		return &closeNotifyConn{Conn: l.conn, listener: l}, nil
+	}
+	<-l.done
+	return nil, net.ErrClosed
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+type closeNotifyConn struct {
+	net.Conn
+	listener *connListener
+}
+
+func (c *closeNotifyConn) Close() error {
+	err := c.Conn.Close()
+	c.listener.Close()
+	return err
+}
+
+// Copy a response body, flushing after every read so streamed responses reach the client as they arrive.
+func streamBody(w http.ResponseWriter, body io.Reader) error {
+	controller := http.NewResponseController(w)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+			controller.Flush()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, value := range header.Values("Connection") {
+		for _, key := range strings.Split(value, ",") {
+			header.Del(strings.TrimSpace(key))
+		}
+	}
+	for _, key := range hopHeaders {
+		header.Del(key)
+	}
+}
+
+// Convert headers to map.
+func flattenHeaders(headers http.Header) map[string]string {
+	flat := make(map[string]string)
+	for key, values := range headers {
+		flat[key] = strings.Join(values, ", ")
+	}
+	return flat
+}
@@ -0,0 +1,186 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		host     string
+		path     string
+		expected bool
+	}{
+		{name: "Empty filter", host: "example.com", path: "/", expected: true},
+		{name: "Host glob", filter: Filter{Hosts: []string{"*.example.com"}}, host: "API.example.com", path: "/", expected: true},
+		{name: "Host mismatch", filter: Filter{Hosts: []string{"*.example.com"}}, host: "example.org", path: "/", expected: false},
+		{name: "Path prefix", filter: Filter{Paths: []string{"/api/"}}, host: "example.com", path: "/api/users", expected: true},
+		{name: "Path prefix is segment aligned", filter: Filter{Paths: []string{"/api"}}, host: "example.com", path: "/apis", expected: false},
+		{name: "Path glob", filter: Filter{Paths: []string{"/users/*/orders"}}, host: "example.com", path: "/users/7/orders", expected: true},
+		{name: "Host and path", filter: Filter{Hosts: []string{"example.com"}, Paths: []string{"/api"}}, host: "example.com", path: "/health", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matched := tt.filter.Match(tt.host, tt.path); matched != tt.expected {
+				t.Errorf("Expected %v, received %v", tt.expected, matched)
+			}
+		})
+	}
+}
+
+// Start the proxy and return a client sending through it, with a function waiting for the exchanges it reported.
+// Exchanges are reported once the reply is written, so they may arrive after the client has read the response.
+func startProxy(t *testing.T, p *Proxy, roots *x509.CertPool) (*http.Client, func(count int) []*Exchange) {
+	var mu sync.Mutex
+	var exchanges []*Exchange
+	p.OnExchange = func(e *Exchange) {
+		mu.Lock()
+		defer mu.Unlock()
+		exchanges = append(exchanges, e)
+	}
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+	proxyURL, _ := url.Parse(server.URL)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}}
+	return client, func(count int) []*Exchange {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mu.Lock()
+			reported := append([]*Exchange(nil), exchanges...)
+			mu.Unlock()
+			if len(reported) >= count || time.Now().After(deadline) {
+				return reported
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+func upstream() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+	})
+}
+
+func TestForward(t *testing.T) {
+	target := httptest.NewServer(upstream())
+	defer target.Close()
+	client, exchanges := startProxy(t, New(Filter{Paths: []string{"/api"}}, nil, false), nil)
+
+	req, _ := http.NewRequest("POST", target.URL+"/api/items?b=2&a=1", strings.NewReader(`{"name":"book"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "token=xyz")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated || string(body) != `POST /api/items?b=2&a=1 {"name":"book"}` {
+		t.Fatalf("Unexpected response %d %q", res.StatusCode, body)
+	}
+	res, err = client.Get(target.URL + "/health")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	res.Body.Close()
+
+	recorded := exchanges(2)
+	if len(recorded) != 2 {
+		t.Fatalf("Expected two exchanges, received %d", len(recorded))
+	}
+	e := recorded[0]
+	if !e.Matched || e.Request == nil || e.Response == nil {
+		t.Fatalf("Expected the first exchange to be recorded, received %+v", e)
+	}
+	if e.Request.URL != target.URL+"/api/items" || e.Request.Query["a"][0] != "1" || e.Request.Cookies["token"] != "xyz" {
+		t.Errorf("Unexpected recorded request %+v", e.Request)
+	}
+	if e.Request.Headers["Content-Type"] != "application/json" || e.Request.Headers["Cookie"] != "" {
+		t.Errorf("Unexpected recorded headers %v", e.Request.Headers)
+	}
+	if e.Response.StatusCode != http.StatusCreated || e.Response.Body != string(body) || len(e.Response.Cookies) != 1 {
+		t.Errorf("Unexpected recorded response %+v", e.Response)
+	}
+	if !strings.HasPrefix(string(e.TemplateYaml), templateComment) || !strings.Contains(string(e.TemplateYaml), "url: "+target.URL+"/api/items") {
+		t.Errorf("Unexpected template %s", e.TemplateYaml)
+	}
+	if recorded[1].Matched || recorded[1].Request != nil || recorded[1].StatusCode != http.StatusCreated {
+		t.Errorf("Expected the second exchange to be proxied without recording, received %+v", recorded[1])
+	}
+}
+
+func TestIntercept(t *testing.T) {
+	target := httptest.NewTLSServer(upstream())
+	defer target.Close()
+	dir := t.TempDir()
+	ca, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	reloaded, err := LoadOrCreateCA(dir)
+	if err != nil || !reloaded.cert.Equal(ca.cert) {
+		t.Fatalf("Expected the saved CA to be loaded again, received %v", err)
+	}
+	info, err := os.Stat(dir + "/" + caKeyFile)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the CA key to be private, received %v %v", info.Mode(), err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client, exchanges := startProxy(t, New(Filter{}, ca, true), roots)
+
+	res, err := client.Get(target.URL + "/secure")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	res.Body.Close()
+	recorded := exchanges(1)
+	if len(recorded) != 1 || recorded[0].Request == nil {
+		t.Fatalf("Expected the intercepted exchange to be recorded, received %+v", recorded)
+	}
+	if recorded[0].Request.URL != target.URL+"/secure" {
+		t.Errorf("Unexpected recorded URL %q", recorded[0].Request.URL)
+	}
+	if !strings.Contains(string(recorded[0].TemplateYaml), "ssl_verify: false") {
+		t.Errorf("Expected insecure templates to disable verification, received %s", recorded[0].TemplateYaml)
+	}
+}
+
+func TestTunnel(t *testing.T) {
+	target := httptest.NewTLSServer(upstream())
+	defer target.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(target.Certificate())
+	client, exchanges := startProxy(t, New(Filter{}, nil, false), roots)
+
+	res, err := client.Get(target.URL + "/secure")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status 201, received %d", res.StatusCode)
+	}
+	if reported := exchanges(0); len(reported) != 0 {
+		t.Errorf("Expected tunneled traffic not to be reported, received %+v", reported)
+	}
+}
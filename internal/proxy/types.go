@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"net/http"
+	"reqcorder/internal/request"
+	"reqcorder/internal/response"
+)
+
+// Proxy is a forward HTTP proxy that hands every exchange matching its filter over for recording.
+type Proxy struct {
+	Filter Filter
+	// CA intercepts TLS traffic to matching hosts when set. Without it, HTTPS traffic is tunneled and not recorded.
+	CA *CA
+	// Insecure skips verification of upstream TLS certificates.
+	Insecure bool
+	// OnExchange is called after every proxied exchange, including those the filter leaves out.
+	OnExchange func(e *Exchange)
+	transport  http.RoundTripper
+}
+
+// Exchange is a request and its response that passed through the proxy.
+type Exchange struct {
+	// Matched reports whether the filter selected the exchange for recording.
+	Matched bool
+	Method  string
+	URL     string
+	// Request, TemplateYaml, and Response are only set for matched exchanges.
+	Request      *request.RequestObject
+	TemplateYaml []byte
+	Response     *response.ResponseObject
+	StatusCode   int
+	Err          error
+}
+
+// template is the synthetic template recorded for an exchange. It can be sent again with exec -tp.
+type template struct {
+	URL       string                        `yaml:"url"`
+	Query     map[string]request.MultiValue `yaml:"query,omitempty"`
+	Method    string                        `yaml:"method"`
+	Headers   map[string]string             `yaml:"headers,omitempty"`
+	Cookies   map[string]string             `yaml:"cookies,omitempty"`
+	UserAgent string                        `yaml:"user_agent,omitempty"`
+	Body      string                        `yaml:"body,omitempty"`
+	SSLVerify *bool                         `yaml:"ssl_verify,omitempty"`
+}
@@ -0,0 +1,97 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"strings"
+)
+
+// Pin a baseline for its template. Baselines are stored next to templates, one per template hash.
+func (b *BaselineStore) Record() error {
+	slog.Debug("Starting to record baseline", slog.Any("baselineStore", b))
+	b.TemplateHash = b.Baseline.TemplateHash
+	baselineDir := filepath.Join(b.RecordStorePath, "baselines")
+	if err := utils.EnsureDir(baselineDir); err != nil {
+		err = errors.Join(ErrorFailedToRecord, err)
+		slog.Error("Failed to ensure baselines directory", "error", err)
+		return err
+	}
+	baselineYaml, err := utils.ConvertToYAML(b.Baseline)
+	if err != nil {
+		slog.Error("Failed to convert baseline to YAML", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	baselinePath := filepath.Join(baselineDir, b.TemplateHash+".yaml")
+	slog.Debug("Writing baseline file", slog.String("baselinePath", baselinePath))
+	if err := os.WriteFile(baselinePath, baselineYaml, 0644); err != nil {
+		slog.Error("Failed to write baseline file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	slog.Debug("Successfully recorded baseline", slog.String("templateHash", b.TemplateHash))
+	return nil
+}
+
+// Retrieve the baseline of a template.
+func (b *BaselineStore) GetBaseline() error {
+	slog.Debug("Starting to retrieve baseline", slog.String("templateHash", b.TemplateHash))
+	baselinePath := filepath.Join(b.RecordStorePath, "baselines", b.TemplateHash+".yaml")
+	if _, err := os.Stat(baselinePath); err != nil {
+		slog.Debug("Baseline not found", slog.String("baselinePath", baselinePath))
+		return fmt.Errorf("%w for template %q", ErrorBaselineNotFound, b.TemplateHash)
+	}
+	var baseline Baseline
+	if err := utils.ReadYAMLFile(baselinePath, &baseline); err != nil {
+		slog.Error("Failed to read baseline file", "error", err)
+		return fmt.Errorf("failed to get baseline from path %q: %w", baselinePath, errors.Join(ErrorBaselineNotFound, err))
+	}
+	b.Baseline = &baseline
+	slog.Debug("Successfully retrieved baseline", slog.Any("baselineStore", b))
+	return nil
+}
+
+// Remove the baseline of a template.
+func (b *BaselineStore) Remove() error {
+	slog.Debug("Starting to remove baseline", slog.String("templateHash", b.TemplateHash))
+	baselinePath := filepath.Join(b.RecordStorePath, "baselines", b.TemplateHash+".yaml")
+	if err := os.Remove(baselinePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w for template %q", ErrorBaselineNotFound, b.TemplateHash)
+		}
+		slog.Error("Failed to remove baseline file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	slog.Debug("Successfully removed baseline", slog.String("templateHash", b.TemplateHash))
+	return nil
+}
+
+// Get the response ID pinned for every template that has a baseline, keyed by template hash.
+func (b *BaselineStore) GetBaselines() (map[string]string, error) {
+	slog.Debug("Starting to get all baselines")
+	baselineDir := filepath.Join(b.RecordStorePath, "baselines")
+	baselines := make(map[string]string)
+	files, err := os.ReadDir(baselineDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return baselines, nil
+		}
+		slog.Error("Failed to read baselines directory", "error", err)
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToReadDirectory, baselineDir, err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
+		}
+		var baseline Baseline
+		if err := utils.ReadYAMLFile(filepath.Join(baselineDir, file.Name()), &baseline); err != nil {
+			slog.Warn("Skipping unreadable baseline file", "file", file.Name(), "error", err)
+			continue
+		}
+		baselines[strings.TrimSuffix(file.Name(), ".yaml")] = baseline.ResponseID
+	}
+	slog.Debug("Collected all baselines", slog.Int("totalCount", len(baselines)))
+	return baselines, nil
+}
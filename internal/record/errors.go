@@ -12,4 +12,8 @@ var (
 	ErrorFailedToGetRequest      = errors.New("failed to get request")
 	ErrorFailedToGetResponse     = errors.New("failed to get response")
 	ErrorFailedToGetTemplate     = errors.New("failed to get template")
+	ErrorFailedToGetRun          = errors.New("failed to get run")
+	ErrorBaselineNotFound        = errors.New("baseline not found")
+	ErrorFailedToGetMonitor      = errors.New("failed to get monitor")
+	ErrorFailedToGetInbound      = errors.New("failed to get inbound request")
 )
@@ -0,0 +1,80 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"strings"
+)
+
+// Record an inbound request under a generated inbound ID.
+func (i *InboundStore) Record() error {
+	slog.Debug("Starting to record inbound request", slog.Any("inboundStore", i))
+	inboundDir := filepath.Join(i.RecordStorePath, "inbound")
+	if err := utils.EnsureDir(inboundDir); err != nil {
+		err = errors.Join(ErrorFailedToRecord, err)
+		slog.Error("Failed to ensure inbound directory", "error", err)
+		return err
+	}
+	if i.InboundID == "" {
+		i.InboundID = generateID()
+	}
+	inboundPath := filepath.Join(inboundDir, i.InboundID+".yaml")
+	slog.Debug("Writing inbound file", slog.String("inboundPath", inboundPath))
+	if err := os.WriteFile(inboundPath, i.InboundYaml, 0644); err != nil {
+		slog.Error("Failed to write inbound file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	slog.Debug("Successfully recorded inbound request", slog.String("inboundId", i.InboundID))
+	return nil
+}
+
+// Retrieve an inbound request by ID.
+func (i *InboundStore) GetInboundByID() error {
+	slog.Debug("Starting to retrieve inbound request by ID", slog.String("inboundId", i.InboundID))
+	inboundPath := filepath.Join(i.RecordStorePath, "inbound", i.InboundID+".yaml")
+	if _, err := os.Stat(inboundPath); err != nil {
+		slog.Debug("Inbound ID not found", slog.String("inboundId", i.InboundID))
+		return fmt.Errorf("%w: %q not found", ErrorFailedToGetInbound, i.InboundID)
+	}
+	inboundYaml, err := utils.ReadFile(inboundPath)
+	if err != nil {
+		err = errors.Join(ErrorFailedToGetInbound, err)
+		slog.Error("Failed to read inbound file", "error", err)
+		return fmt.Errorf("failed to get inbound request from path %q: %w", inboundPath, err)
+	}
+	i.InboundYaml = inboundYaml
+	return nil
+}
+
+// Get all inbound requests sorted in descending order of modification.
+func (i *InboundStore) GetSortedInbound() ([]FileInfo, error) {
+	slog.Debug("Starting to get all sorted inbound requests")
+	inboundDir := filepath.Join(i.RecordStorePath, "inbound")
+	files, err := os.ReadDir(inboundDir)
+	if err != nil {
+		slog.Error("Failed to read inbound directory", "error", err)
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToReadDirectory, inboundDir, err)
+	}
+	var allFiles []FileInfo
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
+		}
+		filePath := filepath.Join(inboundDir, file.Name())
+		fileInfo, err := os.Stat(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrorFailedToStatPath, filePath, err)
+		}
+		allFiles = append(allFiles, FileInfo{
+			InboundID: strings.TrimSuffix(file.Name(), ".yaml"),
+			FilePath:  filePath,
+			ModTime:   fileInfo.ModTime(),
+		})
+	}
+	sortFilesByTimeInPlace(allFiles)
+	return allFiles, nil
+}
@@ -15,6 +15,56 @@ func (r *RecordStore) LogValue() slog.Value {
 	)
 }
 
+// Helper function to log pointers to RunStore.
+func (r *RunStore) LogValue() slog.Value {
+	if r == nil {
+		return slog.StringValue("<nil>")
+	}
+	return slog.GroupValue(
+		slog.String("recordStorePath", r.RecordStorePath),
+		slog.String("collectionHash", r.CollectionHash),
+		slog.String("runId", r.RunID),
+	)
+}
+
+// Helper function to log pointers to MonitorStore.
+func (m *MonitorStore) LogValue() slog.Value {
+	if m == nil {
+		return slog.StringValue("<nil>")
+	}
+	return slog.GroupValue(
+		slog.String("recordStorePath", m.RecordStorePath),
+		slog.String("monitorId", m.MonitorID),
+	)
+}
+
+// Helper function to log pointers to InboundStore.
+func (i *InboundStore) LogValue() slog.Value {
+	if i == nil {
+		return slog.StringValue("<nil>")
+	}
+	return slog.GroupValue(
+		slog.String("recordStorePath", i.RecordStorePath),
+		slog.String("inboundId", i.InboundID),
+	)
+}
+
+// Helper function to log pointers to BaselineStore.
+func (b *BaselineStore) LogValue() slog.Value {
+	if b == nil {
+		return slog.StringValue("<nil>")
+	}
+	responseID := ""
+	if b.Baseline != nil {
+		responseID = b.Baseline.ResponseID
+	}
+	return slog.GroupValue(
+		slog.String("recordStorePath", b.RecordStorePath),
+		slog.String("templateHash", b.TemplateHash),
+		slog.String("responseId", responseID),
+	)
+}
+
 // Helper function to log FileInfo.
 func (f FileInfo) LogValue() slog.Value {
 	return slog.GroupValue(
@@ -22,6 +72,9 @@ func (f FileInfo) LogValue() slog.Value {
 		slog.String("templateHash", f.TemplateHash),
 		slog.String("requestHash", f.RequestHash),
 		slog.String("responseId", f.ResponseID),
+		slog.String("collectionHash", f.CollectionHash),
+		slog.String("runId", f.RunID),
+		slog.String("inboundId", f.InboundID),
 		slog.Time("modTime", f.ModTime),
 	)
 }
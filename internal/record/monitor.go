@@ -0,0 +1,50 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+)
+
+// Record the summary of a monitor session under a generated monitor ID.
+func (m *MonitorStore) Record() error {
+	slog.Debug("Starting to record monitor summary", slog.Any("monitorStore", m))
+	monitorDir := filepath.Join(m.RecordStorePath, "monitors")
+	if err := utils.EnsureDir(monitorDir); err != nil {
+		err = errors.Join(ErrorFailedToRecord, err)
+		slog.Error("Failed to ensure monitors directory", "error", err)
+		return err
+	}
+	if m.MonitorID == "" {
+		m.MonitorID = generateID()
+	}
+	monitorPath := filepath.Join(monitorDir, m.MonitorID+".yaml")
+	slog.Debug("Writing monitor file", slog.String("monitorPath", monitorPath))
+	if err := os.WriteFile(monitorPath, m.MonitorYaml, 0644); err != nil {
+		slog.Error("Failed to write monitor file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	slog.Debug("Successfully recorded monitor summary", slog.String("monitorId", m.MonitorID))
+	return nil
+}
+
+// Retrieve a monitor summary by ID.
+func (m *MonitorStore) GetMonitorByID() error {
+	slog.Debug("Starting to retrieve monitor summary by ID", slog.String("monitorId", m.MonitorID))
+	monitorPath := filepath.Join(m.RecordStorePath, "monitors", m.MonitorID+".yaml")
+	if _, err := os.Stat(monitorPath); err != nil {
+		slog.Debug("Monitor ID not found", slog.String("monitorId", m.MonitorID))
+		return fmt.Errorf("%w: %q not found", ErrorFailedToGetMonitor, m.MonitorID)
+	}
+	monitorYaml, err := utils.ReadFile(monitorPath)
+	if err != nil {
+		err = errors.Join(ErrorFailedToGetMonitor, err)
+		slog.Error("Failed to read monitor file", "error", err)
+		return fmt.Errorf("failed to get monitor from path %q: %w", monitorPath, err)
+	}
+	m.MonitorYaml = monitorYaml
+	return nil
+}
@@ -21,7 +21,7 @@ var globalExecutionCounter atomic.Uint64
 // Record request-response cycle.
 func (r *RecordStore) Record() error {
 	slog.Debug("Starting to record request-response cycle", slog.Any("recordStore", r))
-	r.TemplateHash = utils.CalculateMD5Hash(r.TemplateYaml)
+	r.TemplateHash = request.TemplateHash(r.TemplateYaml, r.Request)
 	r.Request.TemplateHash = r.TemplateHash
 	slog.Debug("Calculated template hash", slog.String("templateHash", r.TemplateHash))
 	requestYaml, err := utils.ConvertToYAML(r.Request)
@@ -70,6 +70,21 @@ func (r *RecordStore) Record() error {
 	return nil
 }
 
+// Record a new response for a request already in the store, under its existing template and request hashes.
+func (r *RecordStore) RecordResponse() error {
+	slog.Debug("Starting to record response for stored request", slog.Any("recordStore", r))
+	r.Response.TemplateHash = r.TemplateHash
+	r.Response.RequestHash = r.RequestHash
+	responseYaml, err := utils.ConvertToYAML(r.Response)
+	if err != nil {
+		err = errors.Join(ErrorFailedToConvertResponse, err)
+		slog.Error("Failed to convert response to YAML", "error", err)
+		return err
+	}
+	r.ResponseYaml = responseYaml
+	return r.recordResponse()
+}
+
 // Retrieve response from YAML file.
 func (r *RecordStore) GetResponse() error {
 	slog.Debug("Starting to retrieve response", slog.String("requestHash", r.RequestHash), slog.String("responseId", r.ResponseID))
@@ -455,6 +470,11 @@ func (r *RecordStore) GetSortedTemplates() ([]FileInfo, error) {
 
 // Generate unique response ID.
 func (r *RecordStore) generateResponseID() string {
+	return generateID()
+}
+
+// Generate a unique, time ordered ID used for responses and runs.
+func generateID() string {
 	now := time.Now().UTC()
 	counter := globalExecutionCounter.Add(1)
 	return fmt.Sprintf("%s_%04d", now.Format("20060102_150405_000"), counter%10000)
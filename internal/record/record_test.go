@@ -1521,6 +1521,9 @@ func TestFileInfo_LogValue(t *testing.T) {
 				slog.String("templateHash", "template123"),
 				slog.String("requestHash", "request456"),
 				slog.String("responseId", "response789"),
+				slog.String("collectionHash", ""),
+				slog.String("runId", ""),
+				slog.String("inboundId", ""),
 				slog.Time("modTime", now),
 			),
 		},
@@ -1538,6 +1541,9 @@ func TestFileInfo_LogValue(t *testing.T) {
 				slog.String("templateHash", ""),
 				slog.String("requestHash", ""),
 				slog.String("responseId", ""),
+				slog.String("collectionHash", ""),
+				slog.String("runId", ""),
+				slog.String("inboundId", ""),
 				slog.Time("modTime", time.Time{}),
 			),
 		},
@@ -1546,7 +1552,7 @@ func TestFileInfo_LogValue(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := tt.input.LogValue()
-			if !reflect.DeepEqual(result, tt.expected) {
+			if !result.Equal(tt.expected) {
 				t.Errorf("LogValue() = %v, want %v", result, tt.expected)
 			}
 		})
@@ -1697,3 +1703,239 @@ func TestFailedRecord_TemplateWriteFailure(t *testing.T) {
 // 		t.Fatalf("Expected error %v, received %v", expectedErr, err)
 // 	}
 // }
+
+func TestSuccessfulRecordRun(t *testing.T) {
+	root := t.TempDir()
+	runStore := &RunStore{
+		RecordStorePath: root,
+		CollectionYaml:  []byte("requests:\n  - get.yaml\n"),
+		RunYaml:         []byte("collection: smoke\n"),
+	}
+	err := runStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if runStore.RunID == "" {
+		t.Fatal("Expected a run ID to be generated")
+	}
+	collectionPath := filepath.Join(root, "collections", runStore.CollectionHash+".yaml")
+	content, err := os.ReadFile(collectionPath)
+	if err != nil || string(content) != string(runStore.CollectionYaml) {
+		t.Fatalf("Expected collection %q at %q, received %q (%v)", runStore.CollectionYaml, collectionPath, content, err)
+	}
+	getRun := &RunStore{
+		RecordStorePath: root,
+		RunID:           runStore.RunID,
+	}
+	err = getRun.GetRunByID()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if string(getRun.RunYaml) != string(runStore.RunYaml) || getRun.CollectionHash != runStore.CollectionHash {
+		t.Fatalf("Expected run %q under %q, received %q under %q", runStore.RunYaml, runStore.CollectionHash, getRun.RunYaml, getRun.CollectionHash)
+	}
+}
+
+func TestFailedGetRunByID_RunNotFound(t *testing.T) {
+	root := t.TempDir()
+	err := os.MkdirAll(filepath.Join(root, "runs", "hash"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to create runs directory: %v", err)
+	}
+	getRun := &RunStore{
+		RecordStorePath: root,
+		RunID:           "nonexistent",
+	}
+	err = getRun.GetRunByID()
+	expectedErr := ErrorFailedToGetRun
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Expected error %v, received %v", expectedErr, err)
+	}
+}
+
+func TestSuccessfulGetSortedRuns(t *testing.T) {
+	root := t.TempDir()
+	var runIDs []string
+	for _, collectionYaml := range []string{"name: first\n", "name: second\n"} {
+		runStore := &RunStore{
+			RecordStorePath: root,
+			CollectionYaml:  []byte(collectionYaml),
+			RunYaml:         []byte("collection: run\n"),
+		}
+		err := runStore.Record()
+		if err != nil {
+			t.Fatalf("Expected no error, received %v", err)
+		}
+		runIDs = append(runIDs, runStore.RunID)
+		time.Sleep(10 * time.Millisecond)
+	}
+	runStore := &RunStore{RecordStorePath: root}
+	files, err := runStore.GetSortedRuns()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if len(files) != 2 || files[0].RunID != runIDs[1] || files[1].RunID != runIDs[0] {
+		t.Fatalf("Expected runs %v newest first, received %+v", runIDs, files)
+	}
+}
+
+func TestSuccessfulRecordBaseline(t *testing.T) {
+	root := t.TempDir()
+	baselineStore := &BaselineStore{
+		RecordStorePath: root,
+		Baseline: &Baseline{
+			TemplateHash: "tmpl",
+			RequestHash:  "req",
+			ResponseID:   "20250102_150405_000_0001",
+			CreatedAt:    time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+	}
+	err := baselineStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	getBaseline := &BaselineStore{
+		RecordStorePath: root,
+		TemplateHash:    "tmpl",
+	}
+	err = getBaseline.GetBaseline()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if !reflect.DeepEqual(getBaseline.Baseline, baselineStore.Baseline) {
+		t.Fatalf("Expected baseline %+v, received %+v", baselineStore.Baseline, getBaseline.Baseline)
+	}
+	baselines, err := getBaseline.GetBaselines()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if !reflect.DeepEqual(baselines, map[string]string{"tmpl": "20250102_150405_000_0001"}) {
+		t.Fatalf("Expected one baseline, received %v", baselines)
+	}
+	err = getBaseline.Remove()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	err = getBaseline.GetBaseline()
+	if !errors.Is(err, ErrorBaselineNotFound) {
+		t.Fatalf("Expected %v after removal, received %v", ErrorBaselineNotFound, err)
+	}
+	err = getBaseline.Remove()
+	if !errors.Is(err, ErrorBaselineNotFound) {
+		t.Fatalf("Expected %v when removing twice, received %v", ErrorBaselineNotFound, err)
+	}
+}
+
+func TestSuccessfulGetBaselines_NoDirectory(t *testing.T) {
+	baselineStore := &BaselineStore{
+		RecordStorePath: t.TempDir(),
+	}
+	baselines, err := baselineStore.GetBaselines()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if len(baselines) != 0 {
+		t.Fatalf("Expected no baselines, received %v", baselines)
+	}
+}
+
+func TestSuccessfulRecordResponse_StoredRequest(t *testing.T) {
+	root := t.TempDir()
+	recordStore := &RecordStore{
+		RecordStorePath: root,
+		TemplateYaml:    []byte("url: https://example.com"),
+		Request:         &request.RequestObject{URL: "https://example.com"},
+		Response:        &response.ResponseObject{StatusCode: 200},
+	}
+	err := recordStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	getRequest := &RecordStore{
+		RecordStorePath: root,
+		RequestHash:     recordStore.RequestHash,
+	}
+	err = getRequest.GetRequestByHash()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	getRequest.Response = &response.ResponseObject{StatusCode: 201}
+	err = getRequest.RecordResponse()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	getResponse := &RecordStore{
+		RecordStorePath: root,
+		ResponseID:      getRequest.ResponseID,
+	}
+	err = getResponse.GetResponseByID()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if getResponse.RequestHash != recordStore.RequestHash || getResponse.TemplateHash != recordStore.TemplateHash || getResponse.Response.StatusCode != 201 {
+		t.Fatalf("Expected replayed response under %q and %q, received %+v", recordStore.RequestHash, recordStore.TemplateHash, getResponse.Response)
+	}
+}
+
+func TestSuccessfulRecordMonitor(t *testing.T) {
+	root := t.TempDir()
+	monitorStore := &MonitorStore{
+		RecordStorePath: root,
+		MonitorYaml:     []byte("target: users\nchecks: 3\n"),
+	}
+	err := monitorStore.Record()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if monitorStore.MonitorID == "" {
+		t.Fatal("Expected a monitor ID to be generated")
+	}
+	getMonitor := &MonitorStore{
+		RecordStorePath: root,
+		MonitorID:       monitorStore.MonitorID,
+	}
+	err = getMonitor.GetMonitorByID()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if string(getMonitor.MonitorYaml) != string(monitorStore.MonitorYaml) {
+		t.Errorf("Expected monitor %q, received %q", monitorStore.MonitorYaml, getMonitor.MonitorYaml)
+	}
+	missing := &MonitorStore{RecordStorePath: root, MonitorID: "missing"}
+	if err := missing.GetMonitorByID(); !errors.Is(err, ErrorFailedToGetMonitor) {
+		t.Errorf("Expected %v, received %v", ErrorFailedToGetMonitor, err)
+	}
+}
+
+func TestSuccessfulRecordInbound(t *testing.T) {
+	root := t.TempDir()
+	var ids []string
+	for _, body := range []string{"first", "second"} {
+		inboundStore := &InboundStore{
+			RecordStorePath: root,
+			InboundYaml:     []byte("method: POST\nbody: " + body + "\n"),
+		}
+		if err := inboundStore.Record(); err != nil {
+			t.Fatalf("Expected no error, received %v", err)
+		}
+		ids = append(ids, inboundStore.InboundID)
+	}
+	getInbound := &InboundStore{RecordStorePath: root, InboundID: ids[0]}
+	if err := getInbound.GetInboundByID(); err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if string(getInbound.InboundYaml) != "method: POST\nbody: first\n" {
+		t.Errorf("Unexpected inbound request %q", getInbound.InboundYaml)
+	}
+	files, err := getInbound.GetSortedInbound()
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected 2 inbound requests, received %d", len(files))
+	}
+	missing := &InboundStore{RecordStorePath: root, InboundID: "missing"}
+	if err := missing.GetInboundByID(); !errors.Is(err, ErrorFailedToGetInbound) {
+		t.Errorf("Expected %v, received %v", ErrorFailedToGetInbound, err)
+	}
+}
@@ -0,0 +1,119 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"strings"
+)
+
+// Record a collection run. The collection is stored by hash and the run under the collection hash.
+func (r *RunStore) Record() error {
+	slog.Debug("Starting to record collection run", slog.Any("runStore", r))
+	r.CollectionHash = utils.CalculateMD5Hash(r.CollectionYaml)
+	collectionDir := filepath.Join(r.RecordStorePath, "collections")
+	if err := utils.EnsureDir(collectionDir); err != nil {
+		err = errors.Join(ErrorFailedToRecord, err)
+		slog.Error("Failed to ensure collections directory", "error", err)
+		return err
+	}
+	collectionPath := filepath.Join(collectionDir, r.CollectionHash+".yaml")
+	slog.Debug("Writing collection file", slog.String("collectionPath", collectionPath))
+	if err := os.WriteFile(collectionPath, r.CollectionYaml, 0644); err != nil {
+		slog.Error("Failed to write collection file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	runDir := filepath.Join(r.RecordStorePath, "runs", r.CollectionHash)
+	if err := utils.EnsureDir(runDir); err != nil {
+		err = errors.Join(ErrorFailedToRecord, err)
+		slog.Error("Failed to ensure run directory", "error", err)
+		return err
+	}
+	if r.RunID == "" {
+		r.RunID = generateID()
+	}
+	runPath := filepath.Join(runDir, r.RunID+".yaml")
+	slog.Debug("Writing run file", slog.String("runPath", runPath))
+	if err := os.WriteFile(runPath, r.RunYaml, 0644); err != nil {
+		slog.Error("Failed to write run file", "error", err)
+		return fmt.Errorf("%w: %v", ErrorFailedToRecord, err)
+	}
+	slog.Debug("Successfully recorded collection run", slog.String("runId", r.RunID))
+	return nil
+}
+
+// Locate and retrieve a run by ID.
+func (r *RunStore) GetRunByID() error {
+	slog.Debug("Starting to locate and retrieve run by ID", slog.String("runId", r.RunID))
+	runsRootDir := filepath.Join(r.RecordStorePath, "runs")
+	collectionDirs, err := os.ReadDir(runsRootDir)
+	if err != nil {
+		slog.Error("Failed to read runs root directory", "error", err)
+		return fmt.Errorf("%w %q: %v", ErrorFailedToReadDirectory, runsRootDir, err)
+	}
+	for _, collectionDir := range collectionDirs {
+		if !collectionDir.IsDir() {
+			continue
+		}
+		runPath := filepath.Join(runsRootDir, collectionDir.Name(), r.RunID+".yaml")
+		if _, err := os.Stat(runPath); err != nil {
+			continue
+		}
+		slog.Debug("Found run file", slog.String("runPath", runPath))
+		runYaml, err := utils.ReadFile(runPath)
+		if err != nil {
+			err = errors.Join(ErrorFailedToGetRun, err)
+			slog.Error("Failed to read run file", "error", err)
+			return fmt.Errorf("failed to get run from path %q: %w", runPath, err)
+		}
+		r.RunYaml = runYaml
+		r.CollectionHash = collectionDir.Name()
+		return nil
+	}
+	slog.Debug("Run ID not found", slog.String("runId", r.RunID))
+	return fmt.Errorf("%w: %q not found", ErrorFailedToGetRun, r.RunID)
+}
+
+// Get all runs sorted in descending order of modification.
+func (r *RunStore) GetSortedRuns() ([]FileInfo, error) {
+	slog.Debug("Starting to get all sorted runs")
+	runsRootDir := filepath.Join(r.RecordStorePath, "runs")
+	collectionDirs, err := os.ReadDir(runsRootDir)
+	if err != nil {
+		slog.Error("Failed to read runs root directory", "error", err)
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToReadDirectory, runsRootDir, err)
+	}
+	var allFiles []FileInfo
+	for _, collectionDir := range collectionDirs {
+		if !collectionDir.IsDir() {
+			continue
+		}
+		runDirPath := filepath.Join(runsRootDir, collectionDir.Name())
+		runFiles, err := os.ReadDir(runDirPath)
+		if err != nil {
+			slog.Error("Failed to read run directory", "error", err)
+			return nil, fmt.Errorf("%w %q: %v", ErrorFailedToReadDirectory, runDirPath, err)
+		}
+		for _, runFile := range runFiles {
+			if runFile.IsDir() || !strings.HasSuffix(runFile.Name(), ".yaml") {
+				continue
+			}
+			filePath := filepath.Join(runDirPath, runFile.Name())
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %v", ErrorFailedToStatPath, filePath, err)
+			}
+			allFiles = append(allFiles, FileInfo{
+				RunID:          strings.TrimSuffix(runFile.Name(), ".yaml"),
+				CollectionHash: collectionDir.Name(),
+				FilePath:       filePath,
+				ModTime:        fileInfo.ModTime(),
+			})
+		}
+	}
+	sortFilesByTimeInPlace(allFiles)
+	return allFiles, nil
+}
@@ -19,11 +19,52 @@ type RecordStore struct {
 	ResponseID      string
 }
 
+// RunStore holds all data for a recorded collection run.
+type RunStore struct {
+	RecordStorePath string
+	CollectionYaml  []byte
+	RunYaml         []byte
+	CollectionHash  string
+	RunID           string
+}
+
+// MonitorStore holds the summary of a monitor session.
+type MonitorStore struct {
+	RecordStorePath string
+	MonitorYaml     []byte
+	MonitorID       string
+}
+
+// InboundStore holds a request received by the listen command.
+type InboundStore struct {
+	RecordStorePath string
+	InboundYaml     []byte
+	InboundID       string
+}
+
+// BaselineStore holds the baseline pinned for a template.
+type BaselineStore struct {
+	RecordStorePath string
+	TemplateHash    string
+	Baseline        *Baseline
+}
+
+// Baseline pins a recorded response as the expected response of its template.
+type Baseline struct {
+	TemplateHash string    `yaml:"template_hash"`
+	RequestHash  string    `yaml:"request_hash"`
+	ResponseID   string    `yaml:"response_id"`
+	CreatedAt    time.Time `yaml:"created_at"`
+}
+
 // FileInfo contains metadata about a recorded file.
 type FileInfo struct {
-	ResponseID   string
-	RequestHash  string
-	TemplateHash string
-	FilePath     string
-	ModTime      time.Time
+	ResponseID     string
+	RequestHash    string
+	TemplateHash   string
+	RunID          string
+	CollectionHash string
+	InboundID      string
+	FilePath       string
+	ModTime        time.Time
 }
@@ -0,0 +1,42 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"reqcorder/pkg/jsonpath"
+	"strings"
+)
+
+// Check an ignore path of the baseline comparison: status, body, header:<name>, or a JSONPath pattern.
+func ValidateIgnorePath(path string) error {
+	switch {
+	case path == "status" || path == "body":
+		return nil
+	case strings.HasPrefix(path, "header:"):
+		if strings.TrimSpace(strings.TrimPrefix(path, "header:")) == "" {
+			return fmt.Errorf("%w %q: header name is empty", ErrorInvalidIgnorePath, path)
+		}
+		return nil
+	case strings.HasPrefix(path, "$"):
+		if _, err := jsonpath.ParsePattern(path); err != nil {
+			return fmt.Errorf("%w: %w", ErrorInvalidIgnorePath, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w %q: use status, body, header:<name>, or a JSONPath such as $.id", ErrorInvalidIgnorePath, path)
+	}
+}
+
+// Check the ignore paths of the baseline block.
+func (r *RequestObject) processBaseline() error {
+	if r.Baseline == nil {
+		return nil
+	}
+	for _, path := range r.Baseline.Ignore {
+		if err := ValidateIgnorePath(path); err != nil {
+			return err
+		}
+	}
+	slog.Debug("Baseline options validated", "ignoreCount", len(r.Baseline.Ignore))
+	return nil
+}
@@ -0,0 +1,221 @@
+package request
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Check that at most one body kind is defined.
+func (r *RequestObject) checkBodyConflicts() error {
+	var defined []string
+	if r.Body != "" {
+		defined = append(defined, "body")
+	}
+	if r.BodyFile != "" {
+		defined = append(defined, "body_file")
+	}
+	if r.JSON != nil {
+		defined = append(defined, "json")
+	}
+	if len(r.Form) > 0 {
+		defined = append(defined, "form")
+	}
+	if r.Multipart != nil {
+		defined = append(defined, "multipart")
+	}
+	if len(defined) > 1 {
+		return fmt.Errorf("%w: %s", ErrorConflictingBody, strings.Join(defined, ", "))
+	}
+	return nil
+}
+
+// Read the body file and record its size and digest. The path is used as written, placeholders are not substituted in it.
+// Text content that takes part in substitution is placed in Body, binary content in BinaryBody.
+// Text content that must not be substituted is returned so it can be assigned once substitution is done.
+func (r *RequestObject) loadBodyFile() (string, error) {
+	if r.BodyFile == "" {
+		return "", nil
+	}
+	if strings.Contains(r.BodyFile, "{{") {
+		slog.Error("Body file path contains placeholders", "bodyFile", r.BodyFile)
+		return "", fmt.Errorf("%w %q: placeholders are not supported in body_file paths", ErrorFailedToReadBodyFile, r.BodyFile)
+	}
+	r.BodyFile = r.ResolvePath(r.BodyFile)
+	slog.Debug("Loading body file", "bodyFile", r.BodyFile)
+	content, err := os.ReadFile(r.BodyFile)
+	if err != nil {
+		slog.Error("Failed to read body file", "bodyFile", r.BodyFile, "error", err)
+		return "", fmt.Errorf("%w %q: %v", ErrorFailedToReadBodyFile, r.BodyFile, err)
+	}
+	sum := sha256.Sum256(content)
+	r.BodyFileSize = int64(len(content))
+	r.BodyFileSHA256 = hex.EncodeToString(sum[:])
+	if isBinary(content) {
+		slog.Debug("Body file is binary, sending as is", "size", r.BodyFileSize)
+		r.BinaryBody = content
+		return "", nil
+	}
+	if r.BodyFileSubst != nil && !*r.BodyFileSubst {
+		slog.Debug("Substitution disabled for body file", "size", r.BodyFileSize)
+		return string(content), nil
+	}
+	r.Body = string(content)
+	r.bodyFileSource = r.Body
+	return "", nil
+}
+
+// Prepare JSON, form and multipart bodies for execution.
+func (r *RequestObject) processBody() error {
+	slog.Debug("Processing request body")
+	if r.JSON != nil {
+		if _, err := r.EncodeJSON(); err != nil {
+			return err
+		}
+		r.setDefaultHeader("Content-Type", "application/json")
+	}
+	if len(r.Form) > 0 {
+		r.setDefaultHeader("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if r.Multipart != nil {
+		err := r.processMultipart()
+		if err != nil {
+			return err
+		}
+	}
+	slog.Debug("Request body processing completed")
+	return nil
+}
+
+// Resolve multipart file paths and record their size and digest.
+func (r *RequestObject) processMultipart() error {
+	slog.Debug("Processing multipart body", "fieldCount", len(r.Multipart.Fields), "fileCount", len(r.Multipart.Files))
+	for i := range r.Multipart.Files {
+		file := &r.Multipart.Files[i]
+		if file.Field == "" {
+			return fmt.Errorf("%w: file part %d has no field name", ErrorInvalidMultipart, i+1)
+		}
+		if file.Path == "" {
+			return fmt.Errorf("%w: file part %q has no path", ErrorInvalidMultipart, file.Field)
+		}
+		file.Path = r.ResolvePath(file.Path)
+		size, digest, err := digestFile(file.Path)
+		if err != nil {
+			return err
+		}
+		file.Size = size
+		file.SHA256 = digest
+		if file.Filename == "" {
+			file.Filename = filepath.Base(file.Path)
+		}
+		if file.ContentType == "" {
+			file.ContentType = detectContentType(file.Path)
+		}
+		slog.Debug("Processed multipart file", "field", file.Field, "path", file.Path, "size", file.Size, "contentType", file.ContentType)
+	}
+	return nil
+}
+
+// Resolve a path relative to the template directory. Absolute paths and requests without a template directory are returned cleaned.
+func (r *RequestObject) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if r.TemplateDir != "" {
+		path = filepath.Join(r.TemplateDir, path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return absPath
+}
+
+// Encode form values in the order produced by url.Values.
+func (r *RequestObject) EncodeForm() string {
+	values := url.Values{}
+	for key, fieldValues := range r.Form {
+		for _, value := range fieldValues {
+			values.Add(key, value)
+		}
+	}
+	return values.Encode()
+}
+
+// Describe the request body for previews.
+func (r *RequestObject) DescribeBody() string {
+	switch {
+	case r.JSON != nil:
+		body, err := r.EncodeJSON()
+		if err != nil {
+			return err.Error()
+		}
+		return body
+	case len(r.Form) > 0:
+		return r.EncodeForm()
+	case r.Multipart != nil:
+		return fmt.Sprintf("multipart (%d field(s), %d file(s))", len(r.Multipart.Fields), len(r.Multipart.Files))
+	case len(r.BinaryBody) > 0:
+		return fmt.Sprintf("binary file %s (%d bytes)", filepath.Base(r.BodyFile), len(r.BinaryBody))
+	default:
+		return r.Body
+	}
+}
+
+// Set a header unless it is already present, comparing names case-insensitively.
+func (r *RequestObject) setDefaultHeader(name string, value string) {
+	for key := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return
+		}
+	}
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	r.Headers[name] = value
+}
+
+// Return the size and hex SHA-256 digest of a file.
+func digestFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w %q: %v", ErrorFailedToReadBodyFile, path, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w %q: %v", ErrorFailedToReadBodyFile, path, err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Guess the content type of a file from its extension, falling back to content sniffing.
+func detectContentType(path string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer file.Close()
+	buffer := make([]byte, 512)
+	n, _ := file.Read(buffer)
+	return http.DetectContentType(buffer[:n])
+}
+
+// Report whether content should be treated as binary rather than text.
+func isBinary(content []byte) bool {
+	return !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1
+}
@@ -0,0 +1,110 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"reqcorder/pkg/jsonpath"
+	"sort"
+	"strings"
+)
+
+// Describe the source of a capture, e.g. "json $.token" or "header X-Session".
+func (c Capture) Source() string {
+	var sources []string
+	if c.JSON != "" {
+		sources = append(sources, "json "+c.JSON)
+	}
+	if c.Header != "" {
+		sources = append(sources, "header "+c.Header)
+	}
+	if c.Cookie != "" {
+		sources = append(sources, "cookie "+c.Cookie)
+	}
+	if c.Status {
+		sources = append(sources, "status")
+	}
+	if c.Regex != "" {
+		sources = append(sources, "regex "+c.Regex)
+	}
+	return strings.Join(sources, ", ")
+}
+
+// Check that every capture has exactly one source and that JSONPath and regex sources compile.
+func (r *RequestObject) processCaptures() error {
+	names := make([]string, 0, len(r.Capture))
+	for name := range r.Capture {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		capture := r.Capture[name]
+		sourceCount := 0
+		for _, set := range []bool{capture.JSON != "", capture.Header != "", capture.Cookie != "", capture.Status, capture.Regex != ""} {
+			if set {
+				sourceCount++
+			}
+		}
+		if sourceCount != 1 {
+			return fmt.Errorf("%w %q: exactly one of json, header, cookie, status, or regex must be set, found %d", ErrorInvalidCapture, name, sourceCount)
+		}
+		if capture.JSON != "" {
+			if _, err := jsonpath.Parse(capture.JSON); err != nil {
+				return fmt.Errorf("%w %q: %w", ErrorInvalidCapture, name, err)
+			}
+		}
+		if capture.Regex != "" {
+			if _, err := regexp.Compile(capture.Regex); err != nil {
+				return fmt.Errorf("%w %q: %v", ErrorInvalidCapture, name, err)
+			}
+		}
+	}
+	slog.Debug("Captures validated", "captureCount", len(names))
+	return nil
+}
+
+// Set the values captured by earlier requests of a run. They are applied during validation.
+func (r *RequestObject) ApplyCaptured(vars map[string]string) {
+	r.captured = vars
+}
+
+// Layer captured values referenced by the template or body file over BodyVars and record them.
+// Variable overrides from the command line take precedence over captured values.
+func (r *RequestObject) processCaptured() {
+	source := r.templateSource + r.bodyFileSource
+	for key, value := range r.captured {
+		if _, overridden := r.Overrides[key]; overridden || !strings.Contains(source, "{{"+key+"}}") {
+			continue
+		}
+		if r.BodyVars == nil {
+			r.BodyVars = make(map[string]string)
+		}
+		if r.Captured == nil {
+			r.Captured = make(map[string]string)
+		}
+		r.BodyVars[key] = value
+		r.Captured[key] = value
+	}
+	slog.Debug("Captured values applied", "capturedCount", len(r.Captured))
+}
+
+// Substitute captured values into placeholders that only appear once variables and environment variables are
+// substituted, such as a captured token referenced from a vars file value.
+func (r *RequestObject) processCapturedPlaceholders() {
+	for key, value := range r.captured {
+		if _, overridden := r.Overrides[key]; overridden {
+			continue
+		}
+		placeholder := "{{" + key + "}}"
+		r.substituteFields(func(field string) string {
+			if !strings.Contains(field, placeholder) {
+				return field
+			}
+			if r.Captured == nil {
+				r.Captured = make(map[string]string)
+			}
+			r.Captured[key] = value
+			return strings.ReplaceAll(field, placeholder, value)
+		})
+	}
+}
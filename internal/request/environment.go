@@ -0,0 +1,76 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"strings"
+)
+
+// Locate and read the environment file for the given name or path.
+// Names are looked up in an "environments" directory next to the template, then in the current directory.
+func LoadEnvironment(name string, templatePath string) (*EnvironmentObject, error) {
+	slog.Debug("Loading environment", "name", name, "templatePath", templatePath)
+	candidates := environmentCandidates(name, templatePath)
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		slog.Debug("Found environment file", "path", candidate)
+		env := &EnvironmentObject{}
+		err = utils.ReadYAMLFile(candidate, env)
+		if err != nil {
+			slog.Error("Failed to read environment file", "path", candidate, "error", err)
+			return nil, fmt.Errorf("%w %q: %w", ErrorInvalidEnvironment, candidate, err)
+		}
+		env.Name = strings.TrimSuffix(filepath.Base(candidate), filepath.Ext(candidate))
+		env.Path = candidate
+		slog.Debug("Environment loaded", slog.Any("environment", env))
+		return env, nil
+	}
+	slog.Error("Environment not found", "name", name, "searched", candidates)
+	return nil, fmt.Errorf("%w %q (searched %s)", ErrorEnvironmentNotFound, name, strings.Join(candidates, ", "))
+}
+
+// Merge environment variables over BodyVars and mark the request with the environment name.
+func (r *RequestObject) ApplyEnvironment(env *EnvironmentObject) {
+	if env == nil {
+		return
+	}
+	slog.Debug("Applying environment", "name", env.Name, "varCount", len(env.Vars))
+	if r.BodyVars == nil {
+		r.BodyVars = make(map[string]string, len(env.Vars))
+	}
+	for key, value := range env.Vars {
+		r.BodyVars[key] = value
+	}
+	r.Environment = env.Name
+	r.environmentPath = env.Path
+}
+
+// List the paths that may hold the environment file, in lookup order.
+func environmentCandidates(name string, templatePath string) []string {
+	ext := filepath.Ext(name)
+	if ext == ".yaml" || ext == ".yml" || strings.ContainsRune(name, filepath.Separator) {
+		return []string{name}
+	}
+	var dirs []string
+	if templatePath != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(templatePath), "environments"))
+	}
+	dirs = append(dirs, "environments")
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, dir := range dirs {
+		for _, candidate := range []string{filepath.Join(dir, name+".yaml"), filepath.Join(dir, name+".yml")} {
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
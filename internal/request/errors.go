@@ -3,9 +3,27 @@ package request
 import "errors"
 
 var (
-	ErrorInvalidURL              = errors.New("invalid URL passed")
-	ErrorInvalidMethod           = errors.New("invalid method passed")
-	ErrorUnsupportedType         = errors.New("unsupported type")
-	ErrorFailedToConvertBodyVar  = errors.New("failed to convert bodyVar to string")
-	ErrorFailedToCreateCookieJar = errors.New("failed to create cookie jar")
+	ErrorInvalidURL                = errors.New("invalid URL passed")
+	ErrorInvalidMethod             = errors.New("invalid method passed")
+	ErrorUnsupportedType           = errors.New("unsupported type")
+	ErrorFailedToConvertBodyVar    = errors.New("failed to convert bodyVar to string")
+	ErrorFailedToCreateCookieJar   = errors.New("failed to create cookie jar")
+	ErrorEnvironmentNotFound       = errors.New("environment not found")
+	ErrorInvalidEnvironment        = errors.New("invalid environment file")
+	ErrorInvalidFunctionArgs       = errors.New("invalid template function arguments")
+	ErrorInvalidTemplate           = errors.New("invalid template")
+	ErrorTemplateCycle             = errors.New("template extends cycle detected")
+	ErrorConflictingBody           = errors.New("multiple request bodies defined")
+	ErrorInvalidMultipart          = errors.New("invalid multipart body")
+	ErrorInvalidJSONBody           = errors.New("invalid json body")
+	ErrorFailedToReadBodyFile      = errors.New("failed to read body file")
+	ErrorInvalidVarOverride        = errors.New("invalid variable override")
+	ErrorInvalidVarsFile           = errors.New("invalid vars file")
+	ErrorUnresolvedPlaceholders    = errors.New("unresolved placeholders")
+	ErrorTemplateValidation        = errors.New("template validation failed")
+	ErrorInvalidCapture            = errors.New("invalid capture")
+	ErrorInvalidExpectation        = errors.New("invalid expectation")
+	ErrorInvalidIgnorePath         = errors.New("invalid baseline ignore path")
+	ErrorReplayFileChanged         = errors.New("file changed since the request was recorded")
+	ErrorStoredTemplateFileMissing = errors.New("file referenced by stored template not found")
 )
@@ -0,0 +1,146 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"reqcorder/pkg/jsonpath"
+	"reqcorder/pkg/jsonschema"
+	"reqcorder/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Matches an exact status code such as 201 or a class such as 4xx.
+var statusExpectationRegex = regexp.MustCompile(`^[1-5]([0-9]{2}|[xX]{2})$`)
+
+var matcherKeys = map[string]bool{"equals": true, "contains": true, "regex": true, "type": true, "exists": true}
+
+// JSONTypes are the values accepted by the type check of a JSONPath matcher.
+var JSONTypes = []string{"string", "number", "integer", "boolean", "array", "object", "null"}
+
+// Decode a matcher written either as a mapping of checks or as a plain value to compare for equality.
+func (m *Matcher) UnmarshalYAML(unmarshal func(any) error) error {
+	var raw any
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	if doc, ok := raw.(map[string]any); ok && len(doc) > 0 {
+		isMatcher := true
+		for key := range doc {
+			if !matcherKeys[key] {
+				isMatcher = false
+				break
+			}
+		}
+		if isMatcher {
+			type plain Matcher
+			if err := unmarshal((*plain)(m)); err != nil {
+				return err
+			}
+			// An unquoted "type: null" decodes as an empty string.
+			if value, exists := doc["type"]; exists && value == nil {
+				m.Type = "null"
+			}
+			return nil
+		}
+	}
+	m.Equals = raw
+	return nil
+}
+
+// Return the maximum latency. A plain number is read as milliseconds.
+func (e *Expectation) LatencyLimit() (time.Duration, error) {
+	if e.MaxLatency == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseFloat(e.MaxLatency, 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+	return time.ParseDuration(e.MaxLatency)
+}
+
+// Return the JSON Schema of the expect block, loading it when the template was not validated.
+func (e *Expectation) JSONSchema() (*jsonschema.Schema, error) {
+	if e.compiledSchema == nil {
+		schema, err := jsonschema.Load(e.Schema)
+		if err != nil {
+			return nil, err
+		}
+		e.compiledSchema = schema
+	}
+	return e.compiledSchema, nil
+}
+
+// Check that every assertion of the expect block can be evaluated.
+func (r *RequestObject) processExpectation() error {
+	e := r.Expect
+	if e == nil {
+		return nil
+	}
+	for _, status := range e.Status {
+		if !statusExpectationRegex.MatchString(status) {
+			return fmt.Errorf("%w: status %q must be a code such as 200 or a class such as 2xx", ErrorInvalidExpectation, status)
+		}
+	}
+	for _, name := range utils.SortedKeys(e.Headers) {
+		if err := e.Headers[name].validate(false); err != nil {
+			return fmt.Errorf("%w: header %q: %w", ErrorInvalidExpectation, name, err)
+		}
+	}
+	for _, path := range utils.SortedKeys(e.JSON) {
+		if _, err := jsonpath.Parse(path); err != nil {
+			return fmt.Errorf("%w: %w", ErrorInvalidExpectation, err)
+		}
+		if err := e.JSON[path].validate(true); err != nil {
+			return fmt.Errorf("%w: json %q: %w", ErrorInvalidExpectation, path, err)
+		}
+	}
+	if e.Body != nil {
+		if e.Body.Type != "" || e.Body.Exists != nil {
+			return fmt.Errorf("%w: body supports equals, contains, and regex", ErrorInvalidExpectation)
+		}
+		if err := e.Body.validate(false); err != nil {
+			return fmt.Errorf("%w: body: %w", ErrorInvalidExpectation, err)
+		}
+	}
+	latency, err := e.LatencyLimit()
+	if err != nil || latency < 0 {
+		return fmt.Errorf("%w: max_latency %q must be a duration such as 500ms or a number of milliseconds", ErrorInvalidExpectation, e.MaxLatency)
+	}
+	if e.MaxSize < 0 {
+		return fmt.Errorf("%w: max_size must not be negative, found %d", ErrorInvalidExpectation, e.MaxSize)
+	}
+	if e.Schema != "" {
+		e.Schema = r.ResolvePath(e.Schema)
+		schema, err := jsonschema.Load(e.Schema)
+		if err != nil {
+			return fmt.Errorf("%w: schema: %w", ErrorInvalidExpectation, err)
+		}
+		e.compiledSchema = schema
+	}
+	slog.Debug("Expectations validated", "schema", e.Schema, "headerCount", len(e.Headers), "jsonCount", len(e.JSON))
+	return nil
+}
+
+// Check a matcher. The type check is only allowed for JSONPath matchers.
+func (m Matcher) validate(allowType bool) error {
+	if m.Regex != "" {
+		if _, err := regexp.Compile(m.Regex); err != nil {
+			return err
+		}
+	}
+	if m.Type != "" {
+		if !allowType {
+			return fmt.Errorf("type is only supported for json checks")
+		}
+		if !containsFold(JSONTypes, m.Type) {
+			return fmt.Errorf("type must be one of %s, found %q", strings.Join(JSONTypes, ", "), m.Type)
+		}
+	}
+	if m.Equals == nil && m.Contains == nil && m.Regex == "" && m.Type == "" && m.Exists == nil {
+		return fmt.Errorf("no check defined")
+	}
+	return nil
+}
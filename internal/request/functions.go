@@ -0,0 +1,260 @@
+package request
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateFunction describes a built-in function usable as {{name}} or {{name:args}} in templates.
+type templateFunction struct {
+	// Maximum number of ":" separated arguments. The last argument receives the remainder of the text.
+	maxArgs int
+	// Whether the function produces a different value on every call and should be recorded.
+	dynamic bool
+	call    func(args []string) (string, error)
+}
+
+const randStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var functionRegex = regexp.MustCompile(`\{\{([A-Za-z][A-Za-z0-9]*)(?::([^{}]*))?\}\}`)
+
+var templateFunctions = map[string]templateFunction{
+	"uuid":       {maxArgs: 1, dynamic: true, call: uuidFunction},
+	"now":        {maxArgs: 1, dynamic: true, call: nowFunction},
+	"unix":       {maxArgs: 1, dynamic: true, call: unixFunction},
+	"unixMilli":  {maxArgs: 1, dynamic: true, call: unixMilliFunction},
+	"randInt":    {maxArgs: 2, dynamic: true, call: randIntFunction},
+	"randString": {maxArgs: 1, dynamic: true, call: randStringFunction},
+	"base64":     {maxArgs: 1, call: base64Function},
+	"base64url":  {maxArgs: 1, call: base64URLFunction},
+	"urlencode":  {maxArgs: 1, call: urlEncodeFunction},
+	"sha256":     {maxArgs: 1, call: sha256Function},
+	"hmac":       {maxArgs: 2, call: hmacFunction},
+}
+
+// Evaluate built-in functions in BodyVars values so that a generated value can be shared across fields.
+func (r *RequestObject) processBodyVarFunctions() error {
+	for key, value := range r.BodyVars {
+		resolved, err := r.evaluateFunctions(value)
+		if err != nil {
+			return err
+		}
+		r.BodyVars[key] = resolved
+	}
+	return nil
+}
+
+// Evaluate built-in function placeholders in all templated fields.
+func (r *RequestObject) processFunctions() error {
+	slog.Debug("Processing template functions")
+	var firstErr error
+	r.substituteFields(func(value string) string {
+		resolved, err := r.evaluateFunctions(value)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return resolved
+	})
+	if firstErr != nil {
+		slog.Error("Failed to evaluate template function", "error", firstErr)
+		return firstErr
+	}
+	slog.Debug("Template function processing completed", "generatedCount", len(r.Generated))
+	return nil
+}
+
+// Replace every known function placeholder in value with its result.
+func (r *RequestObject) evaluateFunctions(value string) (string, error) {
+	var firstErr error
+	resolved := functionRegex.ReplaceAllStringFunc(value, func(match string) string {
+		groups := functionRegex.FindStringSubmatch(match)
+		function, exists := templateFunctions[groups[1]]
+		if !exists {
+			return match
+		}
+		var args []string
+		if strings.HasPrefix(match, "{{"+groups[1]+":") {
+			args = strings.SplitN(groups[2], ":", function.maxArgs)
+		}
+		result, err := function.call(args)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%w %q: %v", ErrorInvalidFunctionArgs, match, err)
+			}
+			return match
+		}
+		if function.dynamic {
+			r.Generated = append(r.Generated, GeneratedValue{Placeholder: match, Value: result})
+		}
+		slog.Debug("Evaluated template function", "placeholder", match, "dynamic", function.dynamic)
+		return result
+	})
+	return resolved, firstErr
+}
+
+// Generate a random UUID. Supports version 4 (default) and version 7.
+func uuidFunction(args []string) (string, error) {
+	version := "v4"
+	if len(args) > 0 {
+		version = args[0]
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	switch version {
+	case "v4", "4":
+		b[6] = (b[6] & 0x0f) | 0x40
+	case "v7", "7":
+		var ts [8]byte
+		binary.BigEndian.PutUint64(ts[:], uint64(time.Now().UnixMilli()))
+		copy(b[0:6], ts[2:8])
+		b[6] = (b[6] & 0x0f) | 0x70
+	default:
+		return "", fmt.Errorf("unsupported uuid version %q", version)
+	}
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// Current UTC time in RFC3339 format, optionally shifted by a duration such as +1h or -30m.
+func nowFunction(args []string) (string, error) {
+	t, err := offsetTime(args)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+// Current unix timestamp in seconds, optionally shifted by a duration.
+func unixFunction(args []string) (string, error) {
+	t, err := offsetTime(args)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(t.Unix(), 10), nil
+}
+
+// Current unix timestamp in milliseconds, optionally shifted by a duration.
+func unixMilliFunction(args []string) (string, error) {
+	t, err := offsetTime(args)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10), nil
+}
+
+// Random integer between min and max (inclusive). Defaults to 0 and 1000000.
+func randIntFunction(args []string) (string, error) {
+	low, high := int64(0), int64(1000000)
+	var err error
+	if len(args) > 0 {
+		if low, err = strconv.ParseInt(strings.TrimSpace(args[0]), 10, 64); err != nil {
+			return "", fmt.Errorf("invalid minimum: %v", err)
+		}
+	}
+	if len(args) > 1 {
+		if high, err = strconv.ParseInt(strings.TrimSpace(args[1]), 10, 64); err != nil {
+			return "", fmt.Errorf("invalid maximum: %v", err)
+		}
+	}
+	if high < low {
+		return "", fmt.Errorf("maximum %d is less than minimum %d", high, low)
+	}
+	span := high - low
+	if span < 0 || span == math.MaxInt64 {
+		return "", fmt.Errorf("range from %d to %d is too large", low, high)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(span+1))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(n.Int64()+low, 10), nil
+}
+
+// Random alphanumeric string. Defaults to 16 characters.
+func randStringFunction(args []string) (string, error) {
+	length := 16
+	if len(args) > 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(args[0]))
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("invalid length %q", args[0])
+		}
+		length = n
+	}
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(randStringAlphabet)))
+	for range length {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(randStringAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// Standard base64 encoding of the argument.
+func base64Function(args []string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(firstArg(args))), nil
+}
+
+// URL-safe base64 encoding of the argument without padding.
+func base64URLFunction(args []string) (string, error) {
+	return base64.RawURLEncoding.EncodeToString([]byte(firstArg(args))), nil
+}
+
+// Query escape the argument.
+func urlEncodeFunction(args []string) (string, error) {
+	return url.QueryEscape(firstArg(args)), nil
+}
+
+// Hex encoded SHA-256 digest of the argument.
+func sha256Function(args []string) (string, error) {
+	sum := sha256.Sum256([]byte(firstArg(args)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Hex encoded HMAC-SHA256 of the message using the key, written as {{hmac:key:message}}.
+func hmacFunction(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("expected key and message")
+	}
+	mac := hmac.New(sha256.New, []byte(args[0]))
+	mac.Write([]byte(args[1]))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Current UTC time shifted by the optional duration argument.
+func offsetTime(args []string) (time.Time, error) {
+	now := time.Now().UTC()
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return now, nil
+	}
+	offset, err := time.ParseDuration(strings.TrimPrefix(strings.TrimSpace(args[0]), "+"))
+	if err != nil {
+		return now, fmt.Errorf("invalid offset: %v", err)
+	}
+	return now.Add(offset), nil
+}
+
+// Return the first argument or an empty string.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
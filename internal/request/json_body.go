@@ -0,0 +1,94 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Matches a value that consists of a single placeholder, such as "{{id}}" or "{{env:PORT}}".
+var singlePlaceholderRegex = regexp.MustCompile(`^\{\{[^{}]+\}\}$`)
+
+// Apply a substitution function to every key and string value of a structured JSON body.
+// A value made up of a single placeholder takes the type of its replacement when the replacement is valid JSON,
+// so "{{id}}" with id set to 42 becomes a number while "user-{{id}}" stays a string.
+func substituteJSON(value any, substitute func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		substituted := make(map[string]any, len(v))
+		for key, item := range v {
+			substituted[substitute(key)] = substituteJSON(item, substitute)
+		}
+		return substituted
+	case []any:
+		for i, item := range v {
+			v[i] = substituteJSON(item, substitute)
+		}
+		return v
+	case string:
+		resolved := substitute(v)
+		if resolved != v && singlePlaceholderRegex.MatchString(v) {
+			if typed, ok := decodeJSONValue(resolved); ok {
+				return typed
+			}
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+// Decode text as a single JSON value, keeping integers as int64 so large ids do not lose precision.
+func decodeJSONValue(text string) (any, bool) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, false
+	}
+	return normalizeJSONNumbers(value), true
+}
+
+// Replace json.Number values with int64 or float64 so they are recorded as YAML numbers.
+func normalizeJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeJSONNumbers(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeJSONNumbers(item)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return value
+	}
+}
+
+// Serialize the structured JSON body. HTML characters are left unescaped so the body matches what was written.
+func (r *RequestObject) EncodeJSON() (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(r.JSON)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrorInvalidJSONBody, err)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
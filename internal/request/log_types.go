@@ -17,6 +17,10 @@ func (r *RequestObject) LogValue() slog.Value {
 	for key, value := range r.Cookies {
 		cookieAttrs = append(cookieAttrs, slog.String(key, value))
 	}
+	queryAttrs := make([]slog.Attr, 0, len(r.Query))
+	for key, values := range r.Query {
+		queryAttrs = append(queryAttrs, slog.Any(key, []string(values)))
+	}
 	bodyVarAttrs := make([]slog.Attr, 0, len(r.BodyVars))
 	for key, value := range r.BodyVars {
 		bodyVarAttrs = append(bodyVarAttrs, slog.String(key, value))
@@ -34,6 +38,11 @@ func (r *RequestObject) LogValue() slog.Value {
 		slog.Float64("timeoutSeconds", r.TimeoutSeconds),
 		slog.Bool("sslVerify", *r.SSLVerify),
 		slog.String("caCertPath", r.CACertPath),
+		slog.String("environment", r.Environment),
+		slog.Attr{
+			Key:   "query",
+			Value: slog.GroupValue(queryAttrs...),
+		},
 		slog.Attr{
 			Key:   "headers",
 			Value: slog.GroupValue(headerAttrs...),
@@ -48,3 +57,22 @@ func (r *RequestObject) LogValue() slog.Value {
 		},
 	)
 }
+
+// Helper function to log pointers to EnvironmentObject.
+func (e *EnvironmentObject) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("<nil>")
+	}
+	varAttrs := make([]slog.Attr, 0, len(e.Vars))
+	for key, value := range e.Vars {
+		varAttrs = append(varAttrs, slog.String(key, value))
+	}
+	return slog.GroupValue(
+		slog.String("name", e.Name),
+		slog.String("path", e.Path),
+		slog.Attr{
+			Key:   "vars",
+			Value: slog.GroupValue(varAttrs...),
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"reqcorder/pkg/utils"
+	"sort"
+	"strings"
+)
+
+// Parse "key=value" overrides given on the command line. Later values for the same key win.
+func ParseVarOverrides(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w %q", ErrorInvalidVarOverride, pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// Read a YAML file of key-value pairs to be layered over BodyVars.
+func LoadVarsFile(path string) (map[string]string, error) {
+	slog.Debug("Loading vars file", "path", path)
+	vars := make(map[string]string)
+	err := utils.ReadYAMLFile(path, &vars)
+	if err != nil {
+		slog.Error("Failed to read vars file", "path", path, "error", err)
+		return nil, fmt.Errorf("%w %q: %w", ErrorInvalidVarsFile, path, err)
+	}
+	return vars, nil
+}
+
+// Layer command line variables over BodyVars and record them as overrides.
+func (r *RequestObject) ApplyOverrides(vars map[string]string) {
+	if len(vars) == 0 {
+		return
+	}
+	slog.Debug("Applying variable overrides", "varCount", len(vars))
+	if r.BodyVars == nil {
+		r.BodyVars = make(map[string]string, len(vars))
+	}
+	if r.Overrides == nil {
+		r.Overrides = make(map[string]string, len(vars))
+	}
+	for key, value := range vars {
+		r.BodyVars[key] = value
+		r.Overrides[key] = value
+	}
+}
+
+// Describe the overrides as sorted key=value pairs for previews.
+func (r *RequestObject) DescribeOverrides() string {
+	pairs := make([]string, 0, len(r.Overrides))
+	for key, value := range r.Overrides {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,122 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches any placeholder left in a field after substitution.
+var placeholderRegex = regexp.MustCompile(`\{\{[^{}]+\}\}`)
+
+// UnresolvedPlaceholder is a placeholder that was left in the request or substituted with an empty value.
+type UnresolvedPlaceholder struct {
+	Placeholder string
+	Empty       bool
+	Source      string
+	Lines       []int
+}
+
+// UnresolvedPlaceholdersError lists every unresolved placeholder found while validating a strict request.
+type UnresolvedPlaceholdersError struct {
+	Placeholders []UnresolvedPlaceholder
+}
+
+func (e *UnresolvedPlaceholdersError) Error() string {
+	placeholders := make([]string, len(e.Placeholders))
+	for i, p := range e.Placeholders {
+		placeholders[i] = p.Placeholder
+	}
+	return fmt.Sprintf("%v: %s", ErrorUnresolvedPlaceholders, strings.Join(placeholders, ", "))
+}
+
+func (e *UnresolvedPlaceholdersError) Unwrap() error {
+	return ErrorUnresolvedPlaceholders
+}
+
+// Describe each placeholder on its own line, with the lines it appears on when known.
+func (e *UnresolvedPlaceholdersError) Describe() string {
+	var sb strings.Builder
+	for _, p := range e.Placeholders {
+		sb.WriteString("  " + p.Placeholder)
+		if p.Empty {
+			sb.WriteString(" (empty value)")
+		}
+		if len(p.Lines) > 0 {
+			lines := make([]string, len(p.Lines))
+			for i, line := range p.Lines {
+				lines[i] = fmt.Sprint(line)
+			}
+			sb.WriteString(fmt.Sprintf(" at %s line %s", p.Source, strings.Join(lines, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Report whether unresolved placeholders should fail validation. Strict mode is on unless disabled.
+func (r *RequestObject) isStrict() bool {
+	return r.Strict == nil || *r.Strict
+}
+
+// Remember a placeholder that was substituted with an empty value.
+func (r *RequestObject) markEmptyPlaceholder(placeholder string) {
+	if r.emptyPlaceholders == nil {
+		r.emptyPlaceholders = make(map[string]bool)
+	}
+	r.emptyPlaceholders[placeholder] = true
+}
+
+// Collect placeholders that are still present in templated fields or were substituted with an empty value.
+func (r *RequestObject) checkUnresolvedPlaceholders() error {
+	found := make(map[string]bool)
+	for placeholder := range r.emptyPlaceholders {
+		found[placeholder] = true
+	}
+	r.substituteFields(func(value string) string {
+		for _, match := range placeholderRegex.FindAllString(value, -1) {
+			if _, empty := found[match]; !empty {
+				found[match] = false
+			}
+		}
+		return value
+	})
+	if len(found) == 0 {
+		return nil
+	}
+	placeholders := make([]UnresolvedPlaceholder, 0, len(found))
+	for placeholder, empty := range found {
+		p := UnresolvedPlaceholder{Placeholder: placeholder, Empty: empty}
+		if lines := findLines(r.templateSource, placeholder); len(lines) > 0 {
+			p.Source, p.Lines = "template", lines
+		} else if lines := findLines(r.bodyFileSource, placeholder); len(lines) > 0 {
+			p.Source, p.Lines = "body_file", lines
+		}
+		placeholders = append(placeholders, p)
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		a, b := placeholders[i], placeholders[j]
+		if len(a.Lines) > 0 && len(b.Lines) > 0 && a.Source == b.Source && a.Lines[0] != b.Lines[0] {
+			return a.Lines[0] < b.Lines[0]
+		}
+		if a.Source != b.Source {
+			return a.Source > b.Source
+		}
+		return a.Placeholder < b.Placeholder
+	})
+	slog.Error("Unresolved placeholders in request", "count", len(placeholders))
+	return &UnresolvedPlaceholdersError{Placeholders: placeholders}
+}
+
+// Return the 1-based line numbers of content that contain text.
+func findLines(content string, text string) []int {
+	var lines []int
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, text) {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
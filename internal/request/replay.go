@@ -0,0 +1,51 @@
+package request
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Prepare a request loaded from the store to be sent again. Stored requests are already resolved, so only the
+// state that is not recorded is rebuilt: the timeout, binary body files, and the cookie jar.
+// Files sent as the body or as multipart parts must still match the digest recorded with the request.
+func (r *RequestObject) PrepareReplay() error {
+	slog.Debug("Preparing stored request for replay", "templateHash", r.TemplateHash)
+	err := r.processBasics()
+	if err != nil {
+		slog.Error("Error processing request object", "error", err)
+		return err
+	}
+	if r.BodyFile != "" && r.Body == "" && r.BodyFileSize > 0 {
+		content, err := os.ReadFile(r.BodyFile)
+		if err != nil {
+			slog.Error("Failed to read body file", "bodyFile", r.BodyFile, "error", err)
+			return fmt.Errorf("%w %q: %v", ErrorFailedToReadBodyFile, r.BodyFile, err)
+		}
+		sum := sha256.Sum256(content)
+		if digest := hex.EncodeToString(sum[:]); digest != r.BodyFileSHA256 {
+			return fmt.Errorf("%w: %q", ErrorReplayFileChanged, r.BodyFile)
+		}
+		r.BinaryBody = content
+	}
+	if r.Multipart != nil {
+		for _, file := range r.Multipart.Files {
+			_, digest, err := digestFile(file.Path)
+			if err != nil {
+				return err
+			}
+			if file.SHA256 != "" && digest != file.SHA256 {
+				return fmt.Errorf("%w: %q", ErrorReplayFileChanged, file.Path)
+			}
+		}
+	}
+	err = r.processCookies()
+	if err != nil {
+		slog.Error("Error processing cookies", "error", err)
+		return err
+	}
+	slog.Debug("Stored request prepared for replay", slog.Any("requestObject", r))
+	return nil
+}
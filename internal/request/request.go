@@ -15,15 +15,67 @@ import (
 // Run all processing steps to prepare request for execution.
 func (r *RequestObject) Validate() error {
 	slog.Debug("Raw request object", slog.Any("requestObject", r))
-	err := r.processBasics()
+	err := r.checkBodyConflicts()
 	if err != nil {
-		slog.Error("Error processing request object", "error", err)
+		slog.Error("Error processing request body", "error", err)
+		return err
+	}
+	err = r.processCaptures()
+	if err != nil {
+		slog.Error("Error processing captures", "error", err)
+		return err
+	}
+	err = r.processExpectation()
+	if err != nil {
+		slog.Error("Error processing expectations", "error", err)
+		return err
+	}
+	err = r.processBaseline()
+	if err != nil {
+		slog.Error("Error processing baseline options", "error", err)
+		return err
+	}
+	unsubstitutedBody, err := r.loadBodyFile()
+	if err != nil {
+		slog.Error("Error loading body file", "error", err)
+		return err
+	}
+	r.processCaptured()
+	err = r.processBodyVarFunctions()
+	if err != nil {
+		slog.Error("Error processing body variable functions", "error", err)
 		return err
 	}
 	slog.Debug("Processing body variables")
 	r.processBodyVars()
 	slog.Debug("Processing environment variables")
 	r.processEnvVars()
+	r.processCapturedPlaceholders()
+	err = r.processFunctions()
+	if err != nil {
+		slog.Error("Error processing template functions", "error", err)
+		return err
+	}
+	if r.isStrict() {
+		err = r.checkUnresolvedPlaceholders()
+		if err != nil {
+			slog.Error("Error checking placeholders", "error", err)
+			return err
+		}
+	}
+	if unsubstitutedBody != "" {
+		r.Body = unsubstitutedBody
+	}
+	err = r.processBasics()
+	if err != nil {
+		slog.Error("Error processing request object", "error", err)
+		return err
+	}
+	err = r.processBody()
+	if err != nil {
+		slog.Error("Error processing request body", "error", err)
+		return err
+	}
 	slog.Debug("Processing authentication")
 	r.processAuth()
 	slog.Debug("Processing cookies")
@@ -67,49 +119,95 @@ func (r *RequestObject) processBasics() error {
 	if r.SSLVerify == nil {
 		r.SSLVerify = &t
 	}
+	r.CACertPath = r.ResolvePath(r.CACertPath)
 	slog.Debug("Basic validation completed", "method", r.Method, "timeout", r.Timeout, "userAgent", r.UserAgent)
 	return nil
 }
 
-// Replace placeholders in request body with BodyVars values.
+// Replace {{key}} placeholders in all templated fields with BodyVars values.
 func (r *RequestObject) processBodyVars() {
-	if r.Body == "" {
-		slog.Debug("No body content, skipping body variable processing")
+	if len(r.BodyVars) == 0 {
+		slog.Debug("No body variables defined, skipping body variable processing")
 		return
 	}
 	slog.Debug("Processing body variables", "bodyVarsCount", len(r.BodyVars), "bodyLength", len(r.Body))
-	body := r.Body
-	for key, value := range r.BodyVars {
-		placeholder := "{{" + key + "}}"
-		body = strings.ReplaceAll(body, placeholder, value)
-		slog.Debug("Replaced body variable", "key", key, "placeholder", placeholder)
-	}
-	r.Body = body
-	slog.Debug("Body variable processing completed", "newBodyLength", len(body))
+	r.substituteFields(func(value string) string {
+		for key, replacement := range r.BodyVars {
+			placeholder := "{{" + key + "}}"
+			if replacement == "" && strings.Contains(value, placeholder) {
+				r.markEmptyPlaceholder(placeholder)
+			}
+			value = strings.ReplaceAll(value, placeholder, replacement)
+		}
+		return value
+	})
+	slog.Debug("Body variable processing completed", "newBodyLength", len(r.Body))
 }
 
-// Substitute {{env:VAR}} placeholders with environment variable values.
+// Substitute {{env:VAR}} placeholders in all templated fields with environment variable values.
+// {{env:VAR:-default}} falls back to default when the variable is unset or empty.
 func (r *RequestObject) processEnvVars() {
-	slog.Debug("Processing environment variables in request body")
-	envRegex := regexp.MustCompile(`\{\{env:([A-Z_][A-Z0-9_]+)\}\}`)
-	matches := envRegex.FindAllStringSubmatch(r.Body, -1)
-	if len(matches) == 0 {
-		slog.Debug("No environment variable placeholders found in request body")
-		return
+	slog.Debug("Processing environment variables in request")
+	envRegex := regexp.MustCompile(`\{\{env:([A-Z_][A-Z0-9_]+)(:-[^{}]*)?\}\}`)
+	r.substituteFields(func(value string) string {
+		return envRegex.ReplaceAllStringFunc(value, func(match string) string {
+			groups := envRegex.FindStringSubmatch(match)
+			varName := groups[1]
+			replacement := os.Getenv(varName)
+			slog.Debug("Replacing environment variable", "varName", varName, "found", replacement != "")
+			if replacement == "" && groups[2] != "" {
+				return strings.TrimPrefix(groups[2], ":-")
+			}
+			if replacement == "" {
+				r.markEmptyPlaceholder(match)
+			}
+			return replacement
+		})
+	})
+	slog.Debug("Environment variable processing completed", "bodyLength", len(r.Body))
+}
+
+// Apply a substitution function to every field that supports placeholders.
+func (r *RequestObject) substituteFields(substitute func(string) string) {
+	r.URL = substitute(r.URL)
+	for key, values := range r.Query {
+		for i, value := range values {
+			values[i] = substitute(value)
+		}
+		r.Query[key] = values
+	}
+	for key, value := range r.Headers {
+		r.Headers[key] = substitute(value)
+	}
+	for key, value := range r.Cookies {
+		r.Cookies[key] = substitute(value)
 	}
-	for _, match := range matches {
-		if len(match) > 1 {
-			varName := match[1]
-			slog.Debug("Found environment variable placeholder", "varName", varName)
+	r.Auth = substitute(r.Auth)
+	r.AuthHeaderName = substitute(r.AuthHeaderName)
+	r.UserAgent = substitute(r.UserAgent)
+	r.CACertPath = substitute(r.CACertPath)
+	r.Body = substitute(r.Body)
+	if r.JSON != nil {
+		r.JSON = substituteJSON(r.JSON, substitute)
+	}
+	for key, values := range r.Form {
+		for i, value := range values {
+			values[i] = substitute(value)
+		}
+		r.Form[key] = values
+	}
+	if r.Multipart != nil {
+		for key, values := range r.Multipart.Fields {
+			for i, value := range values {
+				values[i] = substitute(value)
+			}
+			r.Multipart.Fields[key] = values
+		}
+		for i := range r.Multipart.Files {
+			r.Multipart.Files[i].Path = substitute(r.Multipart.Files[i].Path)
+			r.Multipart.Files[i].Filename = substitute(r.Multipart.Files[i].Filename)
 		}
 	}
-	r.Body = envRegex.ReplaceAllStringFunc(r.Body, func(match string) string {
-		varName := envRegex.FindStringSubmatch(match)[1]
-		value := os.Getenv(varName)
-		slog.Debug("Replacing environment variable", "varName", varName, "found", value != "")
-		return value
-	})
-	slog.Debug("Environment variable processing completed", "bodyLength", len(r.Body))
 }
 
 // Ensure Authorization header is correctly prefixed.
@@ -161,3 +259,56 @@ func (r *RequestObject) processCookies() error {
 	slog.Debug("Cookie processing completed", "url", r.URL)
 	return nil
 }
+
+// Build the request URL with the query parameters merged into any query already present in the URL.
+func (r *RequestObject) FullURL() (string, error) {
+	if len(r.Query) == 0 {
+		return r.URL, nil
+	}
+	parsedURL, err := url.Parse(r.URL)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrorInvalidURL, r.URL, err)
+	}
+	query := parsedURL.Query()
+	for key, values := range r.Query {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	parsedURL.RawQuery = query.Encode()
+	return parsedURL.String(), nil
+}
+
+// Decode a scalar or a list of scalars into a MultiValue.
+func (m *MultiValue) UnmarshalYAML(unmarshal func(any) error) error {
+	var raw any
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	switch value := raw.(type) {
+	case nil:
+		*m = MultiValue{""}
+	case []any:
+		values := make(MultiValue, 0, len(value))
+		for _, item := range value {
+			if _, isMap := item.(map[string]any); isMap {
+				return fmt.Errorf("%w: expected scalar values, received a map", ErrorUnsupportedType)
+			}
+			values = append(values, fmt.Sprint(item))
+		}
+		*m = values
+	case map[string]any:
+		return fmt.Errorf("%w: expected a scalar or a list, received a map", ErrorUnsupportedType)
+	default:
+		*m = MultiValue{fmt.Sprint(value)}
+	}
+	return nil
+}
+
+// Encode a single value as a scalar and several values as a list.
+func (m MultiValue) MarshalYAML() (any, error) {
+	if len(m) == 1 {
+		return m[0], nil
+	}
+	return []string(m), nil
+}
@@ -1,15 +1,24 @@
 package request
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"reqcorder/pkg/utils"
+	"strings"
 	"testing"
 	"time"
 
 	"log/slog"
+
+	"github.com/goccy/go-yaml"
 )
 
 func compareStructFields(t *testing.T, got, want any, fieldsToCheck []string) {
@@ -344,11 +353,41 @@ func TestProcessBodyVars(t *testing.T) {
 				Body: "{\"name\": \"{{name}}\", \"firstName\": \"{{firstName}}\"}",
 			},
 		},
+		{
+			name: "Body vars in URL, headers, cookies and auth",
+			input: &RequestObject{
+				URL:            "https://{{host}}/users/{{user_id}}",
+				Headers:        map[string]string{"X-Tenant": "{{tenant}}"},
+				Cookies:        map[string]string{"session": "{{session}}"},
+				Auth:           "{{token}}",
+				AuthHeaderName: "X-{{tenant}}-Auth",
+				UserAgent:      "ReqCorder/{{tenant}}",
+				CACertPath:     "/certs/{{tenant}}.pem",
+				BodyVars: map[string]string{
+					"host":    "example.com",
+					"user_id": "42",
+					"tenant":  "acme",
+					"session": "abc123",
+					"token":   "secret",
+				},
+			},
+			expectedOutput: &RequestObject{
+				URL:            "https://example.com/users/42",
+				Headers:        map[string]string{"X-Tenant": "acme"},
+				Cookies:        map[string]string{"session": "abc123"},
+				Auth:           "secret",
+				AuthHeaderName: "X-acme-Auth",
+				UserAgent:      "ReqCorder/acme",
+				CACertPath:     "/certs/acme.pem",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.input.processBodyVars()
-			compareStructFields(t, tt.input, tt.expectedOutput, []string{"Body"})
+			compareStructFields(t, tt.input, tt.expectedOutput, []string{
+				"URL", "Headers", "Cookies", "Auth", "AuthHeaderName", "UserAgent", "CACertPath", "Body",
+			})
 		})
 	}
 }
@@ -380,6 +419,15 @@ func TestProcessEnvVars(t *testing.T) {
 				Body: "{\"name\": \"\"}",
 			},
 		},
+		{
+			name: "Default for non existent environment variable",
+			input: &RequestObject{
+				Body: "{\"name\": \"{{env:DAMN:-fallback}}\", \"path\": \"{{env:PATH:-unused}}\"}",
+			},
+			expectedOutput: &RequestObject{
+				Body: fmt.Sprintf("{\"name\": \"fallback\", \"path\": \"%s\"}", path),
+			},
+		},
 		{
 			name: "Multiple valid environment variable",
 			input: &RequestObject{
@@ -389,11 +437,24 @@ func TestProcessEnvVars(t *testing.T) {
 				Body: fmt.Sprintf("{\"name\": \"%s\", \"firstName\": \"%s\"}", path, home),
 			},
 		},
+		{
+			name: "Environment variables outside the body",
+			input: &RequestObject{
+				URL:     "https://example.com{{env:HOME}}",
+				Headers: map[string]string{"X-Path": "{{env:PATH}}"},
+				Auth:    "{{env:HOME}}",
+			},
+			expectedOutput: &RequestObject{
+				URL:     "https://example.com" + home,
+				Headers: map[string]string{"X-Path": path},
+				Auth:    home,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.input.processEnvVars()
-			compareStructFields(t, tt.input, tt.expectedOutput, []string{"Body"})
+			compareStructFields(t, tt.input, tt.expectedOutput, []string{"URL", "Headers", "Auth", "Body"})
 		})
 	}
 }
@@ -627,6 +688,25 @@ func TestValidate(t *testing.T) {
 				UserAgent:      "ReqCorder",
 			},
 		},
+		{
+			name: "Valid request with templated URL",
+			input: &RequestObject{
+				URL:      "https://{{host}}/users",
+				Method:   "GET",
+				Auth:     "{{token}}",
+				AuthType: "Bearer",
+				BodyVars: map[string]string{"host": "example.com", "token": "abc"},
+			},
+			expectedOutput: &RequestObject{
+				URL:            "https://example.com/users",
+				Method:         "GET",
+				Auth:           "Bearer abc",
+				AuthType:       "Bearer",
+				TimeoutSeconds: 30,
+				Timeout:        30 * time.Second,
+				UserAgent:      "ReqCorder",
+			},
+		},
 		{
 			name: "Valid request with basic auth",
 			input: &RequestObject{
@@ -783,6 +863,11 @@ func TestLogValue(t *testing.T) {
 				slog.Float64("timeoutSeconds", 30),
 				slog.Bool("sslVerify", false),
 				slog.String("caCertPath", "/path/to/cert"),
+				slog.String("environment", ""),
+				slog.Attr{
+					Key:   "query",
+					Value: slog.GroupValue(),
+				},
 				slog.Attr{
 					Key:   "headers",
 					Value: slog.GroupValue(slog.String("Content-Type", "application/json"), slog.String("Accept", "application/json")),
@@ -829,6 +914,11 @@ func TestLogValue(t *testing.T) {
 				slog.Float64("timeoutSeconds", 30),
 				slog.Bool("sslVerify", false),
 				slog.String("caCertPath", ""),
+				slog.String("environment", ""),
+				slog.Attr{
+					Key:   "query",
+					Value: slog.GroupValue(),
+				},
 				slog.Attr{
 					Key:   "headers",
 					Value: slog.GroupValue(),
@@ -854,3 +944,1442 @@ func TestLogValue(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadEnvironment(t *testing.T) {
+	root := t.TempDir()
+	templatePath := filepath.Join(root, "template.yaml")
+	envDir := filepath.Join(root, "environments")
+	if err := os.MkdirAll(envDir, 0755); err != nil {
+		t.Fatalf("Failed to create environments directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(envDir, "staging.yaml"), []byte("vars:\n  host: staging.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to write environment file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(envDir, "broken.yaml"), []byte("vars: [1, 2"), 0644); err != nil {
+		t.Fatalf("Failed to write environment file: %v", err)
+	}
+	tests := []struct {
+		name          string
+		env           string
+		expectedName  string
+		expectedHost  string
+		expectedError error
+	}{
+		{
+			name:         "Environment by name next to template",
+			env:          "staging",
+			expectedName: "staging",
+			expectedHost: "staging.example.com",
+		},
+		{
+			name:         "Environment by path",
+			env:          filepath.Join(envDir, "staging.yaml"),
+			expectedName: "staging",
+			expectedHost: "staging.example.com",
+		},
+		{
+			name:          "Missing environment",
+			env:           "prod",
+			expectedError: ErrorEnvironmentNotFound,
+		},
+		{
+			name:          "Malformed environment",
+			env:           "broken",
+			expectedError: ErrorInvalidEnvironment,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := LoadEnvironment(tt.env, templatePath)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if env.Name != tt.expectedName {
+				t.Errorf("Expected environment name %q, received %q", tt.expectedName, env.Name)
+			}
+			if env.Vars["host"] != tt.expectedHost {
+				t.Errorf("Expected host %q, received %q", tt.expectedHost, env.Vars["host"])
+			}
+		})
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	r := &RequestObject{
+		URL:      "https://{{host}}/users/{{user_id}}",
+		Method:   "GET",
+		BodyVars: map[string]string{"host": "localhost", "user_id": "1"},
+	}
+	r.ApplyEnvironment(&EnvironmentObject{
+		Name: "staging",
+		Vars: map[string]string{"host": "staging.example.com"},
+	})
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.URL != "https://staging.example.com/users/1" {
+		t.Errorf("Expected environment to override body vars, received URL %q", r.URL)
+	}
+	if r.Environment != "staging" {
+		t.Errorf("Expected environment %q, received %q", "staging", r.Environment)
+	}
+}
+
+func TestProcessFunctions(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		pattern       string
+		generated     int
+		expectedError error
+	}{
+		{
+			name:      "UUID v4",
+			input:     "{{uuid}}",
+			pattern:   `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+			generated: 1,
+		},
+		{
+			name:      "UUID v7",
+			input:     "{{uuid:v7}}",
+			pattern:   `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+			generated: 1,
+		},
+		{
+			name:      "RFC3339 timestamp with offset",
+			input:     "{{now:+1h}}",
+			pattern:   `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`,
+			generated: 1,
+		},
+		{
+			name:      "Unix timestamp",
+			input:     "ts={{unix:-30m}}",
+			pattern:   `^ts=\d{10}$`,
+			generated: 1,
+		},
+		{
+			name:      "Random int in range",
+			input:     "{{randInt:5:5}}",
+			expected:  "5",
+			generated: 1,
+		},
+		{
+			name:      "Random string",
+			input:     "{{randString:8}}",
+			pattern:   `^[A-Za-z0-9]{8}$`,
+			generated: 1,
+		},
+		{
+			name:     "Base64 keeps colons in argument",
+			input:    "Basic {{base64:user:pass}}",
+			expected: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:     "URL encode",
+			input:    "{{urlencode:a b&c}}",
+			expected: "a+b%26c",
+		},
+		{
+			name:     "SHA-256",
+			input:    "{{sha256:abc}}",
+			expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:     "HMAC",
+			input:    "{{hmac:key:The quick brown fox jumps over the lazy dog}}",
+			expected: "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		},
+		{
+			name:     "Unknown placeholder untouched",
+			input:    "{{user_name}} {{env:HOME}}",
+			expected: "{{user_name}} {{env:HOME}}",
+		},
+		{
+			name:          "Invalid random int range",
+			input:         "{{randInt:10:1}}",
+			expectedError: ErrorInvalidFunctionArgs,
+		},
+		{
+			name:          "Random int range overflows",
+			input:         "{{randInt:-9223372036854775808:9223372036854775807}}",
+			expectedError: ErrorInvalidFunctionArgs,
+		},
+		{
+			name:          "Random int range one past the largest span",
+			input:         "{{randInt:-1:9223372036854775807}}",
+			expectedError: ErrorInvalidFunctionArgs,
+		},
+		{
+			name:      "Largest random int range",
+			input:     "{{randInt:0:9223372036854775806}}",
+			pattern:   `^\d+$`,
+			generated: 1,
+		},
+		{
+			name:          "Invalid offset",
+			input:         "{{now:soon}}",
+			expectedError: ErrorInvalidFunctionArgs,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RequestObject{Body: tt.input}
+			err := r.processFunctions()
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expected != "" && r.Body != tt.expected {
+				t.Errorf("Expected %q, received %q", tt.expected, r.Body)
+			}
+			if tt.pattern != "" && !regexp.MustCompile(tt.pattern).MatchString(r.Body) {
+				t.Errorf("Expected %q to match %q", r.Body, tt.pattern)
+			}
+			if len(r.Generated) != tt.generated {
+				t.Errorf("Expected %d generated values, received %d", tt.generated, len(r.Generated))
+			}
+		})
+	}
+}
+
+func TestValidate_SharedGeneratedBodyVar(t *testing.T) {
+	r := &RequestObject{
+		URL:      "https://example.com",
+		Method:   "POST",
+		Headers:  map[string]string{"Idempotency-Key": "{{key}}"},
+		Body:     `{"key": "{{key}}", "user": "{{base64:{{user}}:{{pass}}}}"}`,
+		BodyVars: map[string]string{"key": "{{uuid}}", "user": "john", "pass": "secret"},
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedBody := fmt.Sprintf(`{"key": "%s", "user": "am9objpzZWNyZXQ="}`, r.Headers["Idempotency-Key"])
+	if r.Body != expectedBody {
+		t.Errorf("Expected body %q, received %q", expectedBody, r.Body)
+	}
+	if len(r.Generated) != 1 || r.Generated[0].Value != r.BodyVars["key"] {
+		t.Errorf("Expected generated uuid to be recorded once, received %v", r.Generated)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(name string, content string) string {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	writeFile("base.yaml", "url: https://example.com\nmethod: GET\ntimeout: 10\nheaders:\n  Accept: application/json\n  X-Team: core\n")
+	writeFile("team/middle.yaml", "extends: ../base.yaml\nauth_type: bearer\nauth: team-token\nheaders:\n  X-Team: payments\n")
+	leaf := writeFile("team/leaf.yaml", "extends: middle.yaml\nmethod: POST\nheaders:\n  X-Request: leaf\n")
+	plainContent := "url: https://example.com\nmethod: GET\n"
+	plain := writeFile("plain.yaml", plainContent)
+	writeFile("cycle_a.yaml", "extends: cycle_b.yaml\nmethod: GET\n")
+	cycle := writeFile("cycle_b.yaml", "extends: cycle_a.yaml\nurl: https://example.com\n")
+	missing := writeFile("missing.yaml", "extends: nowhere.yaml\n")
+
+	t.Run("Template without extends is returned as is", func(t *testing.T) {
+		req, templateYaml, err := LoadTemplate(plain)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(templateYaml) != plainContent {
+			t.Errorf("Expected template content %q, received %q", plainContent, string(templateYaml))
+		}
+		if req.URL != "https://example.com" || req.Method != "GET" {
+			t.Errorf("Unexpected request %+v", req)
+		}
+	})
+
+	t.Run("Multi level chain is deep merged", func(t *testing.T) {
+		req, templateYaml, err := LoadTemplate(leaf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		compareStructFields(t, req, &RequestObject{
+			URL:            "https://example.com",
+			Method:         "POST",
+			TimeoutSeconds: 10,
+			AuthType:       "bearer",
+			Auth:           "team-token",
+			Headers: map[string]string{
+				"Accept":    "application/json",
+				"X-Team":    "payments",
+				"X-Request": "leaf",
+			},
+		}, nil)
+		if strings.Contains(string(templateYaml), "extends") {
+			t.Errorf("Expected resolved template without extends key, received %q", string(templateYaml))
+		}
+	})
+
+	t.Run("Changing a base changes the resolved template", func(t *testing.T) {
+		_, before, err := LoadTemplate(leaf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		writeFile("base.yaml", "url: https://example.org\nmethod: GET\n")
+		_, after, err := LoadTemplate(leaf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(before) == string(after) {
+			t.Error("Expected resolved template to change when the base changes")
+		}
+	})
+
+	t.Run("Cycle is detected", func(t *testing.T) {
+		_, _, err := LoadTemplate(cycle)
+		if !errors.Is(err, ErrorTemplateCycle) {
+			t.Fatalf("Expected error %v, received %v", ErrorTemplateCycle, err)
+		}
+	})
+
+	t.Run("Missing base", func(t *testing.T) {
+		_, _, err := LoadTemplate(missing)
+		if err == nil {
+			t.Fatal("Expected error, received nil")
+		}
+	})
+
+	t.Run("Relative paths of a base resolve against the base directory", func(t *testing.T) {
+		bodyPath := writeFile("shared/body.json", `{"a":1}`)
+		certPath := writeFile("shared/certs/ca.pem", "cert")
+		schemaPath := writeFile("shared/schema.json", `{"type":"object"}`)
+		writeFile("shared/files.yaml", "url: https://example.com\nmethod: POST\nbody_file: body.json\nca_cert_path: certs/ca.pem\nexpect:\n  schema: schema.json\nmultipart:\n  files:\n    - field: report\n      path: report.csv\n")
+		child := writeFile("services/child.yaml", "extends: ../shared/files.yaml\nheaders:\n  X-Child: \"true\"\n")
+		req, _, err := LoadTemplate(child)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.ResolvePath(req.BodyFile) != bodyPath {
+			t.Errorf("Expected body file %q, received %q", bodyPath, req.ResolvePath(req.BodyFile))
+		}
+		if req.ResolvePath(req.CACertPath) != certPath {
+			t.Errorf("Expected CA certificate %q, received %q", certPath, req.ResolvePath(req.CACertPath))
+		}
+		if req.ResolvePath(req.Expect.Schema) != schemaPath {
+			t.Errorf("Expected schema %q, received %q", schemaPath, req.ResolvePath(req.Expect.Schema))
+		}
+		expectedFile := filepath.Join(root, "shared", "report.csv")
+		if len(req.Multipart.Files) != 1 || req.ResolvePath(req.Multipart.Files[0].Path) != expectedFile {
+			t.Errorf("Expected multipart file %q, received %+v", expectedFile, req.Multipart.Files)
+		}
+	})
+}
+
+func TestQuery(t *testing.T) {
+	root := t.TempDir()
+	templatePath := filepath.Join(root, "query.yaml")
+	content := "url: https://example.com/search?sort=asc\nmethod: GET\nquery:\n  q: \"{{term}}\"\n  page: 2\n  tag:\n    - go\n    - yaml\nbody_vars:\n  term: hello world\n"
+	if err := os.WriteFile(templatePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	req, _, err := LoadTemplate(templatePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(req.Query["tag"], MultiValue{"go", "yaml"}) {
+		t.Errorf("Expected list query value, received %v", req.Query["tag"])
+	}
+	if !reflect.DeepEqual(req.Query["page"], MultiValue{"2"}) {
+		t.Errorf("Expected scalar query value, received %v", req.Query["page"])
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fullURL, err := req.FullURL()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedURL := "https://example.com/search?page=2&q=hello+world&sort=asc&tag=go&tag=yaml"
+	if fullURL != expectedURL {
+		t.Errorf("Expected URL %q, received %q", expectedURL, fullURL)
+	}
+	if req.URL != "https://example.com/search?sort=asc" {
+		t.Errorf("Expected URL field to remain unchanged, received %q", req.URL)
+	}
+	requestYaml, err := yaml.Marshal(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(requestYaml), "query:\n  page: \"2\"\n  q: hello world\n  tag:\n  - go\n  - yaml\n") {
+		t.Errorf("Expected query parameters to be recorded separately, received:\n%s", requestYaml)
+	}
+}
+
+func TestQuery_InvalidValue(t *testing.T) {
+	var req RequestObject
+	err := yaml.Unmarshal([]byte("query:\n  filter:\n    a: b\n"), &req)
+	if err == nil {
+		t.Fatal("Expected error for map query value, received nil")
+	}
+}
+
+func TestProcessBody(t *testing.T) {
+	root := t.TempDir()
+	avatar := filepath.Join(root, "avatar.png")
+	avatarContent := []byte("\x89PNG\r\n\x1a\nfake")
+	if err := os.WriteFile(avatar, avatarContent, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	tests := []struct {
+		name          string
+		input         *RequestObject
+		expectedError error
+		check         func(t *testing.T, r *RequestObject)
+	}{
+		{
+			name: "Body and form conflict",
+			input: &RequestObject{
+				Body: "raw",
+				Form: map[string]MultiValue{"a": {"b"}},
+			},
+			expectedError: ErrorConflictingBody,
+		},
+		{
+			name: "Form sets content type",
+			input: &RequestObject{
+				Form: map[string]MultiValue{"name": {"John Doe"}, "tag": {"a", "b"}},
+			},
+			check: func(t *testing.T, r *RequestObject) {
+				if r.Headers["Content-Type"] != "application/x-www-form-urlencoded" {
+					t.Errorf("Expected form content type, received %q", r.Headers["Content-Type"])
+				}
+				if r.EncodeForm() != "name=John+Doe&tag=a&tag=b" {
+					t.Errorf("Unexpected form encoding %q", r.EncodeForm())
+				}
+			},
+		},
+		{
+			name: "Form keeps explicit content type",
+			input: &RequestObject{
+				Headers: map[string]string{"content-type": "application/x-www-form-urlencoded; charset=UTF-8"},
+				Form:    map[string]MultiValue{"name": {"John"}},
+			},
+			check: func(t *testing.T, r *RequestObject) {
+				if len(r.Headers) != 1 {
+					t.Errorf("Expected explicit content type to be kept, received %v", r.Headers)
+				}
+			},
+		},
+		{
+			name: "Multipart manifest",
+			input: &RequestObject{
+				TemplateDir: root,
+				Multipart: &MultipartObject{
+					Fields: map[string]MultiValue{"name": {"John"}},
+					Files:  []MultipartFile{{Field: "avatar", Path: "avatar.png"}},
+				},
+			},
+			check: func(t *testing.T, r *RequestObject) {
+				file := r.Multipart.Files[0]
+				sum := sha256.Sum256(avatarContent)
+				if file.Path != avatar {
+					t.Errorf("Expected resolved path %q, received %q", avatar, file.Path)
+				}
+				if file.Size != int64(len(avatarContent)) {
+					t.Errorf("Expected size %d, received %d", len(avatarContent), file.Size)
+				}
+				if file.SHA256 != hex.EncodeToString(sum[:]) {
+					t.Errorf("Unexpected digest %q", file.SHA256)
+				}
+				if file.Filename != "avatar.png" || file.ContentType != "image/png" {
+					t.Errorf("Unexpected filename %q or content type %q", file.Filename, file.ContentType)
+				}
+			},
+		},
+		{
+			name: "Multipart missing file",
+			input: &RequestObject{
+				TemplateDir: root,
+				Multipart: &MultipartObject{
+					Files: []MultipartFile{{Field: "avatar", Path: "missing.png"}},
+				},
+			},
+			expectedError: ErrorFailedToReadBodyFile,
+		},
+		{
+			name: "Multipart file without field",
+			input: &RequestObject{
+				Multipart: &MultipartObject{
+					Files: []MultipartFile{{Path: avatar}},
+				},
+			},
+			expectedError: ErrorInvalidMultipart,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.checkBodyConflicts()
+			if err == nil {
+				err = tt.input.processBody()
+			}
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, tt.input)
+		})
+	}
+}
+
+func TestBodyFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	writeFile("payload.json", []byte(`{"name": "{{name}}"}`))
+	writeFile("image.bin", []byte{0x89, 0x50, 0x00, 0xff, 0xfe})
+	disabled := false
+	tests := []struct {
+		name          string
+		input         *RequestObject
+		expectedBody  string
+		expectedBytes []byte
+		expectedError error
+	}{
+		{
+			name: "Text body file with substitution",
+			input: &RequestObject{
+				BodyFile: "payload.json",
+				BodyVars: map[string]string{"name": "John"},
+			},
+			expectedBody: `{"name": "John"}`,
+		},
+		{
+			name: "Text body file without substitution",
+			input: &RequestObject{
+				BodyFile:      "payload.json",
+				BodyFileSubst: &disabled,
+				BodyVars:      map[string]string{"name": "John"},
+			},
+			expectedBody: `{"name": "{{name}}"}`,
+		},
+		{
+			name: "Binary body file is sent as is",
+			input: &RequestObject{
+				BodyFile: "image.bin",
+			},
+			expectedBytes: []byte{0x89, 0x50, 0x00, 0xff, 0xfe},
+		},
+		{
+			name: "Body file and body conflict",
+			input: &RequestObject{
+				BodyFile: "payload.json",
+				Body:     "inline",
+			},
+			expectedError: ErrorConflictingBody,
+		},
+		{
+			name: "Missing body file",
+			input: &RequestObject{
+				BodyFile: "missing.json",
+			},
+			expectedError: ErrorFailedToReadBodyFile,
+		},
+		{
+			name: "Placeholder in body file path",
+			input: &RequestObject{
+				BodyFile: "{{name}}.json",
+				BodyVars: map[string]string{"name": "payload"},
+			},
+			expectedError: ErrorFailedToReadBodyFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.URL = "https://example.com"
+			tt.input.Method = "POST"
+			tt.input.TemplateDir = root
+			err := tt.input.Validate()
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.input.Body != tt.expectedBody {
+				t.Errorf("Expected body %q, received %q", tt.expectedBody, tt.input.Body)
+			}
+			if !reflect.DeepEqual(tt.input.BinaryBody, tt.expectedBytes) {
+				t.Errorf("Expected binary body %v, received %v", tt.expectedBytes, tt.input.BinaryBody)
+			}
+			if tt.input.BodyFileSHA256 == "" || tt.input.BodyFileSize == 0 {
+				t.Errorf("Expected body file digest and size to be recorded")
+			}
+			if !filepath.IsAbs(tt.input.BodyFile) {
+				t.Errorf("Expected body file path to be resolved, received %q", tt.input.BodyFile)
+			}
+		})
+	}
+}
+
+func TestTemplateHash_BodyFile(t *testing.T) {
+	root := t.TempDir()
+	payload := filepath.Join(root, "payload.json")
+	templatePath := filepath.Join(root, "template.yaml")
+	if err := os.WriteFile(payload, []byte(`{"v": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to write payload: %v", err)
+	}
+	if err := os.WriteFile(templatePath, []byte("url: https://example.com\nmethod: POST\nbody_file: payload.json\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	load := func() (*RequestObject, []byte) {
+		req, templateYaml, err := LoadTemplate(templatePath)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return req, templateYaml
+	}
+	req, first := load()
+	if string(first) != "url: https://example.com\nmethod: POST\nbody_file: payload.json\n" {
+		t.Errorf("Expected the template to be recorded as written, received %q", string(first))
+	}
+	firstHash := TemplateHash(first, req)
+	if firstHash == utils.CalculateMD5Hash(first) {
+		t.Error("Expected the body file digest to be part of the template hash")
+	}
+	req, reloaded := load()
+	if hash := TemplateHash(reloaded, req); hash != firstHash {
+		t.Errorf("Expected template hash %q to be stable, received %q", firstHash, hash)
+	}
+	if err := os.WriteFile(payload, []byte(`{"v": 2}`), 0644); err != nil {
+		t.Fatalf("Failed to update payload: %v", err)
+	}
+	req, edited := load()
+	if string(edited) != string(first) {
+		t.Errorf("Expected template content to stay the same, received %q", string(edited))
+	}
+	if TemplateHash(edited, req) == firstHash {
+		t.Error("Expected template hash to change when the body file changes")
+	}
+}
+
+func TestLoadStoredTemplate(t *testing.T) {
+	root := t.TempDir()
+	payload := filepath.Join(root, "payload.json")
+	if err := os.WriteFile(payload, []byte(`{"v": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to write payload: %v", err)
+	}
+	templatePath := filepath.Join(root, "template.yaml")
+	if err := os.WriteFile(templatePath, []byte("url: https://example.com\nmethod: POST\nbody_file: payload.json"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	_, recorded, err := LoadTemplate(templatePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Chdir(root)
+	req, stored, err := LoadStoredTemplate("hash", recorded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(stored) != string(recorded) {
+		t.Errorf("Expected the stored template to keep its content, received %q", string(stored))
+	}
+	if req.ResolvePath(req.BodyFile) != payload {
+		t.Errorf("Expected body file to resolve to %q, received %q", payload, req.ResolvePath(req.BodyFile))
+	}
+
+	t.Chdir(t.TempDir())
+	missingSchema := filepath.Join(root, "missing.json")
+	_, _, err = LoadStoredTemplate("hash", []byte("url: https://example.com\nmethod: POST\nbody_file: payload.json\nca_cert_path: '{{cert}}'\nexpect:\n  schema: "+missingSchema+"\n"))
+	var missingErr *MissingFilesError
+	if !errors.As(err, &missingErr) || !errors.Is(err, ErrorStoredTemplateFileMissing) {
+		t.Fatalf("Expected missing files error, received %v", err)
+	}
+	expected := []TemplateFile{{Key: "body_file", Path: "payload.json"}, {Key: "expect.schema", Path: missingSchema}}
+	if !reflect.DeepEqual(missingErr.Files, expected) {
+		t.Errorf("Expected missing files %+v, received %+v", expected, missingErr.Files)
+	}
+	if !strings.Contains(missingErr.Describe(), "payload.json (relative to the original template directory") {
+		t.Errorf("Expected relative path to be explained, received %q", missingErr.Describe())
+	}
+
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	_, _, err = LoadStoredTemplate("hash", []byte("url: https://example.com\nmethod: GET\nca_cert_path: certs/ca.pem\n"))
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected missing files error, received %v", err)
+	}
+	expected = []TemplateFile{{Key: "ca_cert_path", Path: "certs/ca.pem"}}
+	if !reflect.DeepEqual(missingErr.Files, expected) {
+		t.Errorf("Expected missing files %+v, received %+v", expected, missingErr.Files)
+	}
+	expectedDescription := "  ca_cert_path: certs/ca.pem (relative to the original template directory, looked up at " + filepath.Join(workDir, "certs/ca.pem") + ")\n"
+	if !strings.HasPrefix(missingErr.Describe(), expectedDescription) {
+		t.Errorf("Expected description to start with %q, received %q", expectedDescription, missingErr.Describe())
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"base.yaml":                "url: https://example.com\nmethod: POST\nbody_file: payload.json\n",
+		"child.yaml":               "extends: base.yaml\nheaders:\n  X-Id: '{{id}}'\nexpect:\n  schema: schemas/user.json\n",
+		"payload.json":             `{"id": "{{id}}"}`,
+		"schemas/user.json":        `{"type": "object"}`,
+		"environments/staging.yml": "vars:\n  id: '42'\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	childPath := filepath.Join(root, "child.yaml")
+	if chain := TemplateChain(childPath); !reflect.DeepEqual(chain, []string{childPath, filepath.Join(root, "base.yaml")}) {
+		t.Errorf("Unexpected template chain %v", chain)
+	}
+	req, _, err := LoadTemplate(childPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	env, err := LoadEnvironment("staging", childPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req.ApplyEnvironment(env)
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{filepath.Join(root, "environments/staging.yml"), filepath.Join(root, "payload.json"), filepath.Join(root, "schemas/user.json")}
+	if dependencies := req.Dependencies(); !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("Expected dependencies %v, received %v", expected, dependencies)
+	}
+}
+
+func TestTemplateChain_Broken(t *testing.T) {
+	root := t.TempDir()
+	templatePath := filepath.Join(root, "child.yaml")
+	if err := os.WriteFile(templatePath, []byte("extends: missing.yaml\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	expected := []string{templatePath, filepath.Join(root, "missing.yaml")}
+	if chain := TemplateChain(templatePath); !reflect.DeepEqual(chain, expected) {
+		t.Errorf("Expected chain %v, received %v", expected, chain)
+	}
+}
+
+func TestJSONBody(t *testing.T) {
+	tests := []struct {
+		name                string
+		template            string
+		expectedBody        string
+		expectedContentType string
+		expectedError       error
+	}{
+		{
+			name: "Typed values and escaped strings",
+			template: `
+json:
+  id: "{{id}}"
+  label: "user-{{id}}"
+  name: "{{name}}"
+  active: true
+  score: 1.5
+  tags: [a, "{{tag}}"]
+  profile:
+    admin: "{{admin}}"
+body_vars:
+  id: "42"
+  name: 'John "JD" <Doe>'
+  tag: b
+  admin: "false"`,
+			expectedBody:        `{"active":true,"id":42,"label":"user-42","name":"John \"JD\" <Doe>","profile":{"admin":false},"score":1.5,"tags":["a","b"]}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name: "Existing content type is kept",
+			template: `
+json: [1, 2]
+headers:
+  content-type: application/vnd.api+json`,
+			expectedBody: `[1,2]`,
+		},
+		{
+			name: "Unresolved placeholder stays a string when strict mode is off",
+			template: `
+json:
+  id: "{{missing}}"
+strict: false`,
+			expectedBody:        `{"id":"{{missing}}"}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name: "JSON and body conflict",
+			template: `
+json:
+  id: 1
+body: raw`,
+			expectedError: ErrorConflictingBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req RequestObject
+			if err := yaml.Unmarshal([]byte("url: https://example.com\nmethod: POST"+tt.template), &req); err != nil {
+				t.Fatalf("Failed to decode template: %v", err)
+			}
+			err := req.Validate()
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			body, err := req.EncodeJSON()
+			if err != nil {
+				t.Fatalf("Unexpected error encoding body: %v", err)
+			}
+			if body != tt.expectedBody {
+				t.Errorf("Expected body %s, received %s", tt.expectedBody, body)
+			}
+			if req.DescribeBody() != tt.expectedBody {
+				t.Errorf("Expected body preview %s, received %s", tt.expectedBody, req.DescribeBody())
+			}
+			if tt.expectedContentType != "" && req.Headers["Content-Type"] != tt.expectedContentType {
+				t.Errorf("Expected content type %q, received %q", tt.expectedContentType, req.Headers["Content-Type"])
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	req := &RequestObject{
+		URL:      "https://example.com/{{user}}",
+		Method:   "GET",
+		BodyVars: map[string]string{"user": "template", "region": "us"},
+	}
+	req.ApplyEnvironment(&EnvironmentObject{Name: "dev", Vars: map[string]string{"user": "env", "region": "eu"}})
+	req.ApplyOverrides(map[string]string{"user": "cli"})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.URL != "https://example.com/cli" {
+		t.Errorf("Expected override to win over environment and template, received %q", req.URL)
+	}
+	if req.BodyVars["region"] != "eu" {
+		t.Errorf("Expected environment value to remain for keys without override, received %q", req.BodyVars["region"])
+	}
+	if !reflect.DeepEqual(req.Overrides, map[string]string{"user": "cli"}) {
+		t.Errorf("Expected overrides to be recorded, received %v", req.Overrides)
+	}
+	if req.DescribeOverrides() != "user=cli" {
+		t.Errorf("Unexpected overrides description %q", req.DescribeOverrides())
+	}
+}
+
+func TestParseVarOverrides(t *testing.T) {
+	vars, err := ParseVarOverrides([]string{"a=1", "b=", "a=2", " c =x=y"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{"a": "2", "b": "", "c": "x=y"}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Errorf("Expected %v, received %v", expected, vars)
+	}
+	for _, invalid := range []string{"novalue", "=value"} {
+		if _, err := ParseVarOverrides([]string{invalid}); !errors.Is(err, ErrorInvalidVarOverride) {
+			t.Errorf("Expected invalid override error for %q, received %v", invalid, err)
+		}
+	}
+}
+
+func TestStrictPlaceholders(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("STRICT_TEST_SET", "value")
+	tests := []struct {
+		name                 string
+		template             string
+		expectedPlaceholders []UnresolvedPlaceholder
+		expectedURL          string
+	}{
+		{
+			name: "Misspelled body var and missing env var",
+			template: `url: https://example.com/{{path}}
+method: POST
+headers:
+  X-Token: "{{env:STRICT_TEST_MISSING}}"
+body: '{"user": "{{user_nme}}"}'
+body_vars:
+  path: items
+  user_name: john`,
+			expectedPlaceholders: []UnresolvedPlaceholder{
+				{Placeholder: "{{env:STRICT_TEST_MISSING}}", Empty: true, Source: "template", Lines: []int{4}},
+				{Placeholder: "{{user_nme}}", Source: "template", Lines: []int{5}},
+			},
+		},
+		{
+			name: "Body var with empty value",
+			template: `url: https://example.com/{{path}}
+method: GET
+body_vars:
+  path: ""`,
+			expectedPlaceholders: []UnresolvedPlaceholder{
+				{Placeholder: "{{path}}", Empty: true, Source: "template", Lines: []int{1}},
+			},
+		},
+		{
+			name: "Env defaults resolve placeholders",
+			template: `url: https://example.com/{{env:STRICT_TEST_MISSING:-fallback}}/{{env:STRICT_TEST_SET:-unused}}
+method: GET`,
+			expectedURL: "https://example.com/fallback/value",
+		},
+		{
+			name: "Strict mode disabled in template",
+			template: `url: https://example.com/{{unknown}}
+method: GET
+strict: false`,
+			expectedURL: "https://example.com/{{unknown}}",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(root, fmt.Sprintf("template%d.yaml", i))
+			if err := os.WriteFile(path, []byte(tt.template), 0644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+			req, _, err := LoadTemplate(path)
+			if err != nil {
+				t.Fatalf("Unexpected error loading template: %v", err)
+			}
+			err = req.Validate()
+			if tt.expectedPlaceholders != nil {
+				var placeholdersErr *UnresolvedPlaceholdersError
+				if !errors.As(err, &placeholdersErr) || !errors.Is(err, ErrorUnresolvedPlaceholders) {
+					t.Fatalf("Expected unresolved placeholders error, received %v", err)
+				}
+				if !reflect.DeepEqual(placeholdersErr.Placeholders, tt.expectedPlaceholders) {
+					t.Errorf("Expected placeholders %+v, received %+v", tt.expectedPlaceholders, placeholdersErr.Placeholders)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if req.URL != tt.expectedURL {
+				t.Errorf("Expected URL %q, received %q", tt.expectedURL, req.URL)
+			}
+		})
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	root := t.TempDir()
+	certPath := filepath.Join(root, "ca.pem")
+	if err := os.WriteFile(certPath, []byte("cert"), 0644); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	tests := []struct {
+		name           string
+		files          map[string]string
+		template       string
+		expectedIssues []string
+	}{
+		{
+			name: "Valid template",
+			files: map[string]string{"template.yaml": `url: https://example.com
+method: post
+auth_type: Bearer
+timeout: 2.5
+ca_cert_path: ` + certPath + `
+query:
+  tags: [a, 1]
+multipart:
+  files:
+    - field: report
+      path: report.csv`},
+			template: "template.yaml",
+		},
+		{
+			name: "Unknown keys, types and ranges",
+			files: map[string]string{"template.yaml": `url: https://example.com
+method: FETCH
+header:
+  a: b
+timeout: 0
+ssl_verify: "yes"
+auth_type: digest
+ca_cert_path: ` + filepath.Join(root, "missing.pem") + `
+body_vars:
+  nested: {a: b}`},
+			template: "template.yaml",
+			expectedIssues: []string{
+				`template.yaml:2:9: method must be one of GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS, found "FETCH"`,
+				`template.yaml:3:1: unknown key "header", did you mean "headers"?`,
+				`template.yaml:5:10: timeout must be greater than 0, found 0`,
+				`template.yaml:6:13: ssl_verify must be a boolean`,
+				`template.yaml:7:12: auth_type must be one of basic, bearer, found "digest"`,
+				`template.yaml:8:15: ca_cert_path "` + filepath.Join(root, "missing.pem") + `" does not exist`,
+				`template.yaml:10:11: body_vars.nested must be a string`,
+			},
+		},
+		{
+			name:     "Placeholders skip value checks",
+			files:    map[string]string{"template.yaml": "url: https://example.com\nmethod: \"{{method}}\"\nca_cert_path: \"{{env:CA_CERT}}\""},
+			template: "template.yaml",
+		},
+		{
+			name:     "Missing required keys",
+			files:    map[string]string{"template.yaml": "headers:\n  a: b"},
+			template: "template.yaml",
+			expectedIssues: []string{
+				`template.yaml:1:1: missing required key "url"`,
+				`template.yaml:1:1: missing required key "method"`,
+			},
+		},
+		{
+			name: "Base templates are checked and provide required keys",
+			files: map[string]string{
+				"base.yaml":  "url: https://example.com\nmethod: GET\nssl_verfy: false",
+				"child.yaml": "extends: base.yaml\nheaders:\n  a: b",
+			},
+			template: "child.yaml",
+			expectedIssues: []string{
+				`base.yaml:3:1: unknown key "ssl_verfy", did you mean "ssl_verify"?`,
+			},
+		},
+		{
+			name:           "Syntax error",
+			files:          map[string]string{"template.yaml": "url: https://example.com\nmethod: [GET"},
+			template:       "template.yaml",
+			expectedIssues: []string{"template.yaml:2:9:"},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(root, fmt.Sprint(i))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+			issues, err := CheckTemplate(filepath.Join(dir, tt.template))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(issues) != len(tt.expectedIssues) {
+				t.Fatalf("Expected %d issues, received %v", len(tt.expectedIssues), issues)
+			}
+			for j, issue := range issues {
+				expected := filepath.Join(dir, tt.expectedIssues[j])
+				if !strings.HasPrefix(issue.String(), expected) {
+					t.Errorf("Expected issue %q, received %q", expected, issue.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTemplate_RelativeCertPath(t *testing.T) {
+	root := t.TempDir()
+	templateDir := filepath.Join(root, "templates")
+	workDir := filepath.Join(root, "work")
+	for _, dir := range []string{templateDir, workDir} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	for _, path := range []string{filepath.Join(templateDir, "ca.pem"), filepath.Join(workDir, "work.pem")} {
+		if err := os.WriteFile(path, []byte("cert"), 0644); err != nil {
+			t.Fatalf("Failed to write certificate: %v", err)
+		}
+	}
+	t.Chdir(workDir)
+	tests := []struct {
+		name          string
+		caCertPath    string
+		expectedIssue bool
+	}{
+		{name: "Relative to the template", caCertPath: "ca.pem"},
+		{name: "Only in the working directory", caCertPath: "work.pem", expectedIssue: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templatePath := filepath.Join(templateDir, "template.yaml")
+			content := "url: https://example.com\nmethod: GET\nca_cert_path: " + tt.caCertPath + "\n"
+			if err := os.WriteFile(templatePath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+			issues, err := CheckTemplate(templatePath)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectedIssue != (len(issues) == 1) || len(issues) > 1 {
+				t.Errorf("Expected issue %v, received %v", tt.expectedIssue, issues)
+			}
+		})
+	}
+}
+
+func TestCheckTemplate_MissingFile(t *testing.T) {
+	_, err := CheckTemplate(filepath.Join(t.TempDir(), "missing.yaml"))
+	if !errors.Is(err, utils.ErrorFailedToReadFile) {
+		t.Errorf("Expected read error, received %v", err)
+	}
+}
+
+func TestTemplateJSONSchema(t *testing.T) {
+	content, err := TemplateJSONSchema()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, received %v", err)
+	}
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected properties in schema, received %v", schema)
+	}
+	for key := range templateSchema.properties {
+		if _, exists := properties[key]; !exists {
+			t.Errorf("Expected property %q in schema", key)
+		}
+	}
+	if schema["additionalProperties"] != false {
+		t.Error("Expected unknown keys to be disallowed")
+	}
+}
+
+func TestProcessCaptures(t *testing.T) {
+	tests := []struct {
+		name        string
+		capture     map[string]Capture
+		expectedErr error
+	}{
+		{
+			name: "One source per capture",
+			capture: map[string]Capture{
+				"token":   {JSON: "$.data['token']"},
+				"session": {Header: "X-Session"},
+				"sid":     {Cookie: "sid"},
+				"status":  {Status: true},
+				"order":   {Regex: `order-(\d+)`},
+			},
+		},
+		{
+			name:        "No source",
+			capture:     map[string]Capture{"token": {}},
+			expectedErr: ErrorInvalidCapture,
+		},
+		{
+			name:        "Multiple sources",
+			capture:     map[string]Capture{"token": {JSON: "$.token", Header: "X-Token"}},
+			expectedErr: ErrorInvalidCapture,
+		},
+		{
+			name:        "Invalid JSONPath",
+			capture:     map[string]Capture{"token": {JSON: "data.token"}},
+			expectedErr: ErrorInvalidCapture,
+		},
+		{
+			name:        "Invalid regex",
+			capture:     map[string]Capture{"order": {Regex: "order-("}},
+			expectedErr: ErrorInvalidCapture,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RequestObject{URL: "https://example.com", Method: "GET", Capture: tt.capture}
+			err := req.Validate()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyCaptured(t *testing.T) {
+	root := t.TempDir()
+	templatePath := filepath.Join(root, "template.yaml")
+	err := os.WriteFile(templatePath, []byte("url: https://example.com/users/{{user_id}}\nmethod: GET\nauth_type: bearer\nauth: \"{{token}}\"\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	req, _, err := LoadTemplate(templatePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req.ApplyOverrides(map[string]string{"user_id": "7"})
+	req.ApplyCaptured(map[string]string{"token": "tok-123", "user_id": "42", "unused": "value"})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.URL != "https://example.com/users/7" {
+		t.Errorf("Expected override to win over captured value, received %q", req.URL)
+	}
+	if req.Auth != "Bearer tok-123" {
+		t.Errorf("Expected captured token in auth, received %q", req.Auth)
+	}
+	if !reflect.DeepEqual(req.Captured, map[string]string{"token": "tok-123"}) {
+		t.Errorf("Expected only referenced captures to be recorded, received %v", req.Captured)
+	}
+}
+
+func TestApplyCaptured_ThroughVariables(t *testing.T) {
+	t.Setenv("CAPTURE_TEST_TRACE", "trace-{{trace_id}}")
+	req := &RequestObject{
+		URL:      "https://example.com/orders/{{order}}",
+		Method:   "GET",
+		Headers:  map[string]string{"X-Trace": "{{env:CAPTURE_TEST_TRACE}}"},
+		AuthType: "bearer",
+		Auth:     "{{auth}}",
+		BodyVars: map[string]string{"order": "{{order_id}}"},
+	}
+	req.ApplyOverrides(map[string]string{"auth": "{{token}}"})
+	req.ApplyCaptured(map[string]string{"token": "tok-123", "order_id": "42", "trace_id": "abc"})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.URL != "https://example.com/orders/42" {
+		t.Errorf("Expected captured value referenced from body_vars, received %q", req.URL)
+	}
+	if req.Auth != "Bearer tok-123" {
+		t.Errorf("Expected captured value referenced from an override, received %q", req.Auth)
+	}
+	if req.Headers["X-Trace"] != "trace-abc" {
+		t.Errorf("Expected captured value referenced from an environment variable, received %q", req.Headers["X-Trace"])
+	}
+	if !reflect.DeepEqual(req.Captured, map[string]string{"token": "tok-123", "order_id": "42", "trace_id": "abc"}) {
+		t.Errorf("Expected the used captures to be recorded, received %v", req.Captured)
+	}
+}
+
+func TestProcessExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		expect      string
+		expectedErr error
+	}{
+		{
+			name:   "Valid expectations",
+			expect: "status: [200, 4xx]\nheaders:\n  Content-Type: application/json\njson:\n  $.id: 1\n  $.items[0].name:\n    type: string\n    regex: '^a'\nbody:\n  contains: ok\nmax_latency: 500ms\nmax_size: 1024",
+		},
+		{
+			name:   "Latency in milliseconds",
+			expect: "max_latency: 250",
+		},
+		{
+			name:        "Invalid status",
+			expect:      "status: 2x0",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Invalid JSONPath",
+			expect:      "json:\n  id: 1",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Unknown type",
+			expect:      "json:\n  $.id:\n    type: float",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Type on header",
+			expect:      "headers:\n  X-Id:\n    type: string",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Invalid regex",
+			expect:      "body:\n  regex: '('",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Invalid latency",
+			expect:      "max_latency: soon",
+			expectedErr: ErrorInvalidExpectation,
+		},
+		{
+			name:        "Negative size",
+			expect:      "max_size: -1",
+			expectedErr: ErrorInvalidExpectation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Expectation
+			if err := yaml.Unmarshal([]byte(tt.expect), &e); err != nil {
+				t.Fatalf("Failed to decode expect block: %v", err)
+			}
+			req := &RequestObject{URL: "https://example.com", Method: "GET", Expect: &e}
+			err := req.Validate()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestProcessExpectation_Schema(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0755); err != nil {
+		t.Fatalf("Failed to create schema directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schemas", "user.json"), []byte(`{"type": "object"}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schemas", "broken.json"), []byte(`{"$ref": "#/$defs/missing"}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	tests := []struct {
+		name        string
+		schema      string
+		expectedErr error
+	}{
+		{name: "Schema relative to template", schema: "./schemas/user.json"},
+		{name: "Missing schema", schema: "./schemas/missing.json", expectedErr: ErrorInvalidExpectation},
+		{name: "Unresolved reference", schema: "./schemas/broken.json", expectedErr: ErrorInvalidExpectation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RequestObject{URL: "https://example.com", Method: "GET", TemplateDir: dir, Expect: &Expectation{Schema: tt.schema}}
+			err := req.Validate()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+			if err == nil && req.Expect.Schema != filepath.Join(dir, "schemas", "user.json") {
+				t.Errorf("Expected schema path to be resolved, received %q", req.Expect.Schema)
+			}
+		})
+	}
+}
+
+func TestValidateIgnorePath(t *testing.T) {
+	tests := []struct {
+		path        string
+		expectedErr error
+	}{
+		{path: "status"},
+		{path: "body"},
+		{path: "header:X-Request-Id"},
+		{path: "$.items[*].id"},
+		{path: "header:", expectedErr: ErrorInvalidIgnorePath},
+		{path: "timing", expectedErr: ErrorInvalidIgnorePath},
+		{path: "$.items[", expectedErr: ErrorInvalidIgnorePath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := ValidateIgnorePath(tt.path)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPrepareReplay(t *testing.T) {
+	dir := t.TempDir()
+	binaryPath := filepath.Join(dir, "payload.bin")
+	content := []byte{0x00, 0x01, 0x02, 0xff}
+	if err := os.WriteFile(binaryPath, content, 0644); err != nil {
+		t.Fatalf("Failed to write body file: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	tests := []struct {
+		name        string
+		stored      string
+		expectedErr error
+	}{
+		{
+			name:   "Resolved request",
+			stored: "url: https://example.com/users\nmethod: POST\nbody: '{\"id\": 1}'\ncookies:\n  sid: abc\ntimeout: 5.0\n",
+		},
+		{
+			name:   "Binary body file",
+			stored: fmt.Sprintf("url: https://example.com/upload\nmethod: PUT\nbody_file: %s\nbody_file_size: 4\nbody_file_sha256: %s\n", binaryPath, digest),
+		},
+		{
+			name:        "Changed body file",
+			stored:      fmt.Sprintf("url: https://example.com/upload\nmethod: PUT\nbody_file: %s\nbody_file_size: 4\nbody_file_sha256: %s\n", binaryPath, strings.Repeat("0", 64)),
+			expectedErr: ErrorReplayFileChanged,
+		},
+		{
+			name:        "Missing multipart file",
+			stored:      "url: https://example.com/upload\nmethod: POST\nmultipart:\n  files:\n    - field: report\n      path: " + filepath.Join(dir, "missing.csv") + "\n",
+			expectedErr: ErrorFailedToReadBodyFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req RequestObject
+			if err := yaml.Unmarshal([]byte(tt.stored), &req); err != nil {
+				t.Fatalf("Failed to decode stored request: %v", err)
+			}
+			err := req.PrepareReplay()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, received %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if req.CookieJar == nil || req.Timeout == 0 {
+				t.Errorf("Expected cookie jar and timeout to be rebuilt, received %v and %v", req.CookieJar, req.Timeout)
+			}
+			if req.BodyFile != "" && !reflect.DeepEqual(req.BinaryBody, content) {
+				t.Errorf("Expected binary body %v, received %v", content, req.BinaryBody)
+			}
+		})
+	}
+}
+
+func TestMatcherUnmarshal(t *testing.T) {
+	var e Expectation
+	err := yaml.Unmarshal([]byte("json:\n  $.user: {id: 1}\n  $.name:\n    contains: jane\n  $.deleted:\n    type: null\n"), &e)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(e.JSON["$.user"].Equals, map[string]any{"id": uint64(1)}) {
+		t.Errorf("Expected a mapping without matcher keys to be an equality check, received %+v", e.JSON["$.user"])
+	}
+	if e.JSON["$.name"].Contains != "jane" || e.JSON["$.name"].Equals != nil {
+		t.Errorf("Expected a contains matcher, received %+v", e.JSON["$.name"])
+	}
+	if e.JSON["$.deleted"].Type != "null" {
+		t.Errorf("Expected unquoted null type, received %+v", e.JSON["$.deleted"])
+	}
+}
@@ -0,0 +1,222 @@
+package request
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type schemaKind int
+
+const (
+	kindAny schemaKind = iota
+	kindString
+	kindNumber
+	kindBool
+	kindObject
+	kindMap
+	kindArray
+	kindMultiValue
+)
+
+// schemaNode describes the expected shape of a template value.
+// It drives both template checking and the JSON Schema emitted for editors.
+type schemaNode struct {
+	kind        schemaKind
+	description string
+	// Allowed string values, compared case-insensitively.
+	enum []string
+	// Exclusive lower bound for numbers.
+	exclusiveMin *float64
+	// The value is a path that must exist, unless it contains placeholders.
+	fileExists bool
+	properties map[string]*schemaNode
+	required   []string
+	// Schema of map values and array items.
+	items *schemaNode
+}
+
+var zeroSeconds = 0.0
+
+var templateSchema = &schemaNode{
+	kind:        kindObject,
+	description: "ReqCorder request template",
+	properties: map[string]*schemaNode{
+		"extends":              {kind: kindString, description: "Path to a template whose keys are inherited, relative to this file"},
+		"url":                  {kind: kindString, description: "Request URL"},
+		"method":               {kind: kindString, description: "HTTP method", enum: []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}},
+		"query":                {kind: kindMap, description: "Query parameters merged into the URL", items: &schemaNode{kind: kindMultiValue}},
+		"headers":              {kind: kindMap, description: "Request headers", items: &schemaNode{kind: kindString}},
+		"cookies":              {kind: kindMap, description: "Request cookies", items: &schemaNode{kind: kindString}},
+		"auth":                 {kind: kindString, description: "Auth header value"},
+		"auth_type":            {kind: kindString, description: "Prefix added to the auth value", enum: []string{"basic", "bearer"}},
+		"auth_header_name":     {kind: kindString, description: "Auth header name, defaults to Authorization"},
+		"user_agent":           {kind: kindString, description: "User agent, defaults to ReqCorder"},
+		"body":                 {kind: kindString, description: "Raw request body"},
+		"body_file":            {kind: kindString, description: "File to read the request body from, relative to the template"},
+		"body_file_substitute": {kind: kindBool, description: "Whether placeholders in a text body_file are substituted"},
+		"json":                 {kind: kindAny, description: "Structured body sent as JSON"},
+		"form":                 {kind: kindMap, description: "application/x-www-form-urlencoded body", items: &schemaNode{kind: kindMultiValue}},
+		"multipart": {
+			kind:        kindObject,
+			description: "multipart/form-data body",
+			properties: map[string]*schemaNode{
+				"fields": {kind: kindMap, description: "Plain form fields", items: &schemaNode{kind: kindMultiValue}},
+				"files": {kind: kindArray, description: "File parts", items: &schemaNode{
+					kind: kindObject,
+					properties: map[string]*schemaNode{
+						"field":        {kind: kindString, description: "Form field name"},
+						"path":         {kind: kindString, description: "File path, relative to the template"},
+						"filename":     {kind: kindString, description: "File name sent to the server"},
+						"content_type": {kind: kindString, description: "Content type of the part"},
+					},
+					required: []string{"field", "path"},
+				}},
+			},
+		},
+		"timeout":      {kind: kindNumber, description: "Timeout in seconds, defaults to 30", exclusiveMin: &zeroSeconds},
+		"body_vars":    {kind: kindMap, description: "Variables substituted for {{name}} placeholders", items: &schemaNode{kind: kindString}},
+		"ssl_verify":   {kind: kindBool, description: "Whether TLS certificates are verified, defaults to true"},
+		"ca_cert_path": {kind: kindString, description: "Path to a CA certificate", fileExists: true},
+		"strict":       {kind: kindBool, description: "Whether unresolved or empty placeholders fail the request, defaults to true"},
+		"capture": {kind: kindMap, description: "Values extracted from the response for later requests of a collection run", items: &schemaNode{
+			kind: kindObject,
+			properties: map[string]*schemaNode{
+				"json":   {kind: kindString, description: "JSONPath into the response body, e.g. $.data.token"},
+				"header": {kind: kindString, description: "Response header name"},
+				"cookie": {kind: kindString, description: "Response cookie name"},
+				"status": {kind: kindBool, description: "Capture the status code"},
+				"regex":  {kind: kindString, description: "Regular expression over the response body, the first group is captured if present"},
+			},
+		}},
+		"baseline": {
+			kind:        kindObject,
+			description: "Options for comparing responses with the baseline of the template",
+			properties: map[string]*schemaNode{
+				"ignore": {kind: kindArray, description: "Locations left out of the comparison: status, body, header:<name>, or a JSONPath pattern such as $.items[*].id", items: &schemaNode{kind: kindString}},
+			},
+		},
+		"expect": {
+			kind:        kindObject,
+			description: "Assertions checked against the response",
+			properties: map[string]*schemaNode{
+				"status":      {kind: kindMultiValue, description: "Expected status codes such as 200 or classes such as 2xx"},
+				"headers":     {kind: kindMap, description: "Expected header values, or matchers with equals, contains, regex, or exists", items: &schemaNode{kind: kindAny}},
+				"json":        {kind: kindMap, description: "Expected values by JSONPath, or matchers with equals, contains, regex, type, or exists", items: &schemaNode{kind: kindAny}},
+				"body":        {kind: kindAny, description: "Expected body, or a matcher with equals, contains, or regex"},
+				"max_latency": {kind: kindString, description: "Maximum total time, a duration such as 500ms or a number of milliseconds"},
+				"max_size":    {kind: kindNumber, description: "Maximum response size in bytes"},
+				"schema":      {kind: kindString, description: "JSON Schema (draft 2020-12) file the body must match, relative to the template"},
+			},
+		},
+	},
+	required: []string{"url", "method"},
+}
+
+// Build a JSON Schema (draft 2020-12) describing the template format.
+func TemplateJSONSchema() ([]byte, error) {
+	schema := templateSchema.jsonSchema()
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "ReqCorder template"
+	// Templates that extend another template may inherit the required keys.
+	delete(schema, "required")
+	schema["if"] = map[string]any{"not": map[string]any{"required": []string{"extends"}}}
+	schema["then"] = map[string]any{"required": templateSchema.required}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// Convert a schema node into its JSON Schema representation.
+func (s *schemaNode) jsonSchema() map[string]any {
+	schema := make(map[string]any)
+	if s.description != "" {
+		schema["description"] = s.description
+	}
+	scalar := []string{"string", "number", "boolean"}
+	switch s.kind {
+	case kindString:
+		schema["type"] = "string"
+		if len(s.enum) > 0 {
+			var values []string
+			for _, value := range s.enum {
+				lower := strings.ToLower(value)
+				values = append(values, strings.ToUpper(value), lower, strings.ToUpper(lower[:1])+lower[1:])
+			}
+			sort.Strings(values)
+			schema["anyOf"] = []any{
+				map[string]any{"enum": dedupe(values)},
+				map[string]any{"pattern": `\{\{.+\}\}`},
+			}
+		}
+	case kindNumber:
+		schema["type"] = "number"
+		if s.exclusiveMin != nil {
+			schema["exclusiveMinimum"] = *s.exclusiveMin
+		}
+	case kindBool:
+		schema["type"] = "boolean"
+	case kindObject:
+		schema["type"] = "object"
+		properties := make(map[string]any, len(s.properties))
+		for key, property := range s.properties {
+			properties[key] = property.jsonSchema()
+		}
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+		if len(s.required) > 0 {
+			schema["required"] = s.required
+		}
+	case kindMap:
+		schema["type"] = "object"
+		schema["additionalProperties"] = s.items.jsonSchema()
+	case kindArray:
+		schema["type"] = "array"
+		schema["items"] = s.items.jsonSchema()
+	case kindMultiValue:
+		schema["oneOf"] = []any{
+			map[string]any{"type": scalar},
+			map[string]any{"type": "array", "items": map[string]any{"type": scalar}},
+		}
+	}
+	return schema
+}
+
+// Return the names of the schema's properties in sorted order.
+func (s *schemaNode) propertyNames() []string {
+	names := make([]string, 0, len(s.properties))
+	for name := range s.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe the kind for error messages.
+func (k schemaKind) String() string {
+	switch k {
+	case kindString:
+		return "a string"
+	case kindNumber:
+		return "a number"
+	case kindBool:
+		return "a boolean"
+	case kindObject, kindMap:
+		return "a mapping"
+	case kindArray:
+		return "a list"
+	case kindMultiValue:
+		return "a scalar or a list of scalars"
+	default:
+		return "any value"
+	}
+}
+
+// Remove consecutive duplicates from a sorted slice.
+func dedupe(values []string) []string {
+	var unique []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,361 @@
+package request
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Read a template file, resolve its extends chain and decode it into a RequestObject.
+// The returned YAML is the fully resolved template, which is what gets hashed and recorded.
+func LoadTemplate(path string) (*RequestObject, []byte, error) {
+	return LoadTemplateWithDefaults(path, nil)
+}
+
+// Read a template file and merge defaults under it, as done for the requests of a collection.
+func LoadTemplateWithDefaults(path string, defaults map[string]any) (*RequestObject, []byte, error) {
+	slog.Debug("Loading template", "templatePath", path, "defaultCount", len(defaults))
+	content, err := utils.ReadFile(path)
+	if err != nil {
+		slog.Error("Failed to read template file", "templatePath", path, "error", err)
+		return nil, nil, err
+	}
+	doc, err := parseTemplateDocument(path, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, exists := doc["extends"]; !exists && len(defaults) == 0 {
+		return decodeTemplate(path, content)
+	}
+	return BuildTemplate(path, doc, defaults)
+}
+
+// Build a request from a parsed template document. Relative paths in the document are resolved against the directory of path.
+// The extends chain is resolved first, then defaults are merged under the result.
+func BuildTemplate(path string, doc map[string]any, defaults map[string]any) (*RequestObject, []byte, error) {
+	if _, exists := doc["extends"]; exists {
+		slog.Debug("Template extends another template, resolving chain", "templatePath", path)
+		resolved, err := resolveExtends(path, doc, nil)
+		if err != nil {
+			slog.Error("Failed to resolve template chain", "templatePath", path, "error", err)
+			return nil, nil, err
+		}
+		doc = resolved
+	}
+	if len(defaults) > 0 {
+		doc = mergeTemplateMaps(defaults, doc)
+	}
+	templateYaml, err := yaml.MarshalWithOptions(doc, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		slog.Error("Failed to convert resolved template to YAML", "templatePath", path, "error", err)
+		return nil, nil, fmt.Errorf("%w: %v", utils.ErrorFailedToMarshalYAML, err)
+	}
+	return decodeTemplate(path, templateYaml)
+}
+
+// Decode resolved template YAML into a RequestObject whose relative paths are resolved against the directory of path.
+func decodeTemplate(path string, templateYaml []byte) (*RequestObject, []byte, error) {
+	var req RequestObject
+	err := yaml.Unmarshal(templateYaml, &req)
+	if err != nil {
+		slog.Error("Failed to decode template", "templatePath", path, "error", err)
+		return nil, nil, fmt.Errorf("%w: %v", utils.ErrorFailedToUnmarshalYAML, err)
+	}
+	req.templateSource = string(templateYaml)
+	req.TemplateDir = filepath.Dir(path)
+	if absPath, err := filepath.Abs(path); err == nil {
+		req.TemplateDir = filepath.Dir(absPath)
+	}
+	slog.Debug("Template loaded", "templatePath", path, "templateLength", len(templateYaml))
+	return &req, templateYaml, nil
+}
+
+// Hash a template for recording. The digest of the body file read by the request is part of the hash,
+// so editing the body file gives a new template hash.
+func TemplateHash(templateYaml []byte, r *RequestObject) string {
+	if r == nil || r.BodyFileSHA256 == "" {
+		return utils.CalculateMD5Hash(templateYaml)
+	}
+	return utils.CalculateMD5Hash(append(slices.Clip(templateYaml), r.BodyFileSHA256...))
+}
+
+// Decode a template recorded in the store. The store does not record the directory of the original file, so relative
+// paths are resolved against the working directory and referenced files that cannot be found are reported up front.
+func LoadStoredTemplate(templateHash string, templateYaml []byte) (*RequestObject, []byte, error) {
+	slog.Debug("Loading stored template", "templateHash", templateHash, "templateLength", len(templateYaml))
+	var req RequestObject
+	err := yaml.Unmarshal(templateYaml, &req)
+	if err != nil {
+		slog.Error("Failed to decode stored template", "templateHash", templateHash, "error", err)
+		return nil, nil, fmt.Errorf("%w: %v", utils.ErrorFailedToUnmarshalYAML, err)
+	}
+	req.templateSource = string(templateYaml)
+	var missingFiles []TemplateFile
+	for _, ref := range req.referencedFiles() {
+		if strings.Contains(ref.Path, "{{") {
+			continue
+		}
+		if _, err := os.Stat(req.ResolvePath(ref.Path)); err != nil {
+			missingFiles = append(missingFiles, ref)
+		}
+	}
+	if len(missingFiles) > 0 {
+		slog.Error("Stored template references missing files", "templateHash", templateHash, "missingCount", len(missingFiles))
+		workDir, _ := os.Getwd()
+		return nil, nil, &MissingFilesError{TemplateHash: templateHash, WorkDir: workDir, Files: missingFiles}
+	}
+	slog.Debug("Stored template loaded", "templateHash", templateHash)
+	return &req, templateYaml, nil
+}
+
+// TemplateFile is a file read by a template, named by the template key that references it.
+type TemplateFile struct {
+	Key  string
+	Path string
+}
+
+// MissingFilesError lists the files of a stored template that cannot be found from the working directory.
+type MissingFilesError struct {
+	TemplateHash string
+	WorkDir      string
+	Files        []TemplateFile
+}
+
+func (e *MissingFilesError) Error() string {
+	paths := make([]string, len(e.Files))
+	for i, file := range e.Files {
+		paths[i] = file.Path
+	}
+	return fmt.Sprintf("%v: %s", ErrorStoredTemplateFileMissing, strings.Join(paths, ", "))
+}
+
+func (e *MissingFilesError) Unwrap() error {
+	return ErrorStoredTemplateFileMissing
+}
+
+// Describe each missing file on its own line, along with where it was looked up. Every relative path of a stored
+// template, including those inherited through extends, is relative to the original template directory, which the
+// store does not record, so they were resolved against the working directory instead.
+func (e *MissingFilesError) Describe() string {
+	var sb strings.Builder
+	relative := false
+	for _, file := range e.Files {
+		sb.WriteString(fmt.Sprintf("  %s: %s", file.Key, file.Path))
+		if !filepath.IsAbs(file.Path) {
+			relative = true
+			sb.WriteString(fmt.Sprintf(" (relative to the original template directory, looked up at %s)", filepath.Join(e.WorkDir, file.Path)))
+		}
+		sb.WriteString("\n")
+	}
+	if relative {
+		sb.WriteString("The store does not record the original template directory, so relative paths are resolved against the working directory; run from that directory or use absolute paths.\n")
+	}
+	return sb.String()
+}
+
+// List the files a template reads when it is executed.
+func (r *RequestObject) referencedFiles() []TemplateFile {
+	var refs []TemplateFile
+	if r.BodyFile != "" {
+		refs = append(refs, TemplateFile{Key: "body_file", Path: r.BodyFile})
+	}
+	if r.CACertPath != "" {
+		refs = append(refs, TemplateFile{Key: "ca_cert_path", Path: r.CACertPath})
+	}
+	if r.Multipart != nil {
+		for _, file := range r.Multipart.Files {
+			if file.Path != "" {
+				refs = append(refs, TemplateFile{Key: "multipart file", Path: file.Path})
+			}
+		}
+	}
+	if r.Expect != nil && r.Expect.Schema != "" {
+		refs = append(refs, TemplateFile{Key: "expect.schema", Path: r.Expect.Schema})
+	}
+	return refs
+}
+
+// Return the files a request reads when it is sent, resolved against the template directory, along with its environment file.
+// Paths that still hold placeholders are left out.
+func (r *RequestObject) Dependencies() []string {
+	var paths []string
+	if r.environmentPath != "" {
+		paths = append(paths, r.environmentPath)
+	}
+	for _, file := range r.referencedFiles() {
+		if !strings.Contains(file.Path, "{{") {
+			paths = append(paths, r.ResolvePath(file.Path))
+		}
+	}
+	return paths
+}
+
+// List a template file and the templates it extends, nearest first. The chain is followed as far as it can be read,
+// so a broken template still lists the files that need fixing.
+func TemplateChain(path string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	for path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			absPath = filepath.Clean(path)
+		}
+		if seen[absPath] {
+			break
+		}
+		seen[absPath] = true
+		chain = append(chain, absPath)
+		content, err := utils.ReadFile(absPath)
+		if err != nil {
+			break
+		}
+		var doc struct {
+			Extends string `yaml:"extends"`
+		}
+		if err := yaml.Unmarshal(content, &doc); err != nil || strings.TrimSpace(doc.Extends) == "" {
+			break
+		}
+		path = doc.Extends
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(absPath), path)
+		}
+	}
+	return chain
+}
+
+// Recursively merge a template with the templates it extends. Paths are relative to the extending file, and relative
+// file paths inherited from a base template are rewritten to stay relative to the extending file.
+func resolveExtends(path string, doc map[string]any, chain []string) (map[string]any, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = filepath.Clean(path)
+	}
+	for _, visited := range chain {
+		if visited == absPath {
+			cycle := append(chain, absPath)
+			return nil, fmt.Errorf("%w: %s", ErrorTemplateCycle, strings.Join(cycle, " -> "))
+		}
+	}
+	chain = append(chain, absPath)
+	extends, exists := doc["extends"]
+	if !exists {
+		return doc, nil
+	}
+	basePath, ok := extends.(string)
+	if !ok || strings.TrimSpace(basePath) == "" {
+		return nil, fmt.Errorf("%w %q: extends must be a file path", ErrorInvalidTemplate, path)
+	}
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(path), basePath)
+	}
+	slog.Debug("Resolving base template", "templatePath", path, "basePath", basePath)
+	content, err := utils.ReadFile(basePath)
+	if err != nil {
+		return nil, err
+	}
+	baseDoc, err := parseTemplateDocument(basePath, content)
+	if err != nil {
+		return nil, err
+	}
+	base, err := resolveExtends(basePath, baseDoc, chain)
+	if err != nil {
+		return nil, err
+	}
+	base = rebaseTemplatePaths(base, filepath.Dir(basePath), filepath.Dir(path))
+	// The document may belong to the caller, such as an inline collection item that is built on every run, so extends is
+	// left out of a copy instead of being deleted from it.
+	child := make(map[string]any, len(doc))
+	for key, value := range doc {
+		if key != "extends" {
+			child[key] = value
+		}
+	}
+	return mergeTemplateMaps(base, child), nil
+}
+
+// Rewrite the relative file paths of a template document from one directory to another, returning a copy of the document.
+// Absolute paths and paths holding placeholders are kept as they are.
+func rebaseTemplatePaths(doc map[string]any, from string, to string) map[string]any {
+	rebase := func(value any) any {
+		path, ok := value.(string)
+		if !ok || path == "" || filepath.IsAbs(path) || strings.Contains(path, "{{") {
+			return value
+		}
+		rebased := filepath.Join(from, path)
+		if relPath, err := filepath.Rel(to, rebased); err == nil {
+			return relPath
+		}
+		if absPath, err := filepath.Abs(rebased); err == nil {
+			return absPath
+		}
+		return rebased
+	}
+	rebased := make(map[string]any, len(doc))
+	for key, value := range doc {
+		rebased[key] = value
+	}
+	for _, key := range []string{"body_file", "ca_cert_path"} {
+		if value, exists := rebased[key]; exists {
+			rebased[key] = rebase(value)
+		}
+	}
+	if expect, ok := rebased["expect"].(map[string]any); ok {
+		if schema, exists := expect["schema"]; exists {
+			expect = mergeTemplateMaps(expect, map[string]any{"schema": rebase(schema)})
+			rebased["expect"] = expect
+		}
+	}
+	if multipart, ok := rebased["multipart"].(map[string]any); ok {
+		if files, ok := multipart["files"].([]any); ok {
+			rebasedFiles := make([]any, len(files))
+			for i, file := range files {
+				rebasedFiles[i] = file
+				if fileMap, ok := file.(map[string]any); ok {
+					if path, exists := fileMap["path"]; exists {
+						rebasedFiles[i] = mergeTemplateMaps(fileMap, map[string]any{"path": rebase(path)})
+					}
+				}
+			}
+			rebased["multipart"] = mergeTemplateMaps(multipart, map[string]any{"files": rebasedFiles})
+		}
+	}
+	return rebased
+}
+
+// Deep merge two template maps. Nested maps are merged, every other value in override replaces the base value.
+func mergeTemplateMaps(base map[string]any, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		baseMap, baseIsMap := merged[key].(map[string]any)
+		overrideMap, overrideIsMap := value.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergeTemplateMaps(baseMap, overrideMap)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// Parse template content into a generic map.
+func parseTemplateDocument(path string, content []byte) (map[string]any, error) {
+	var doc map[string]any
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		slog.Error("Failed to parse template", "templatePath", path, "error", err)
+		return nil, fmt.Errorf("%w %q: %w", ErrorInvalidTemplate, path, err)
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+	return doc, nil
+}
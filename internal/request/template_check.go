@@ -0,0 +1,418 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reqcorder/pkg/utils"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// TemplateIssue is a problem found while checking a template, located by file, line and column.
+type TemplateIssue struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (i TemplateIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.Path, i.Line, i.Column, i.Message)
+}
+
+// TemplateIssuesError lists every problem found in one or more templates.
+type TemplateIssuesError struct {
+	Issues []TemplateIssue
+}
+
+func (e *TemplateIssuesError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("%v: %s", ErrorTemplateValidation, strings.Join(issues, "; "))
+}
+
+func (e *TemplateIssuesError) Unwrap() error {
+	return ErrorTemplateValidation
+}
+
+// Describe each issue on its own line.
+func (e *TemplateIssuesError) Describe() string {
+	var sb strings.Builder
+	for _, issue := range e.Issues {
+		sb.WriteString("  " + issue.String() + "\n")
+	}
+	return sb.String()
+}
+
+// templateChecker collects issues for a template and the templates it extends.
+type templateChecker struct {
+	issues  []TemplateIssue
+	visited map[string]bool
+	keys    map[string]bool
+	// Set when a file in the chain could not be parsed, so required keys cannot be checked.
+	incomplete bool
+}
+
+// Check a template and the templates it extends against the template schema.
+// Every problem is reported with its position. The returned error is only set when the template cannot be read.
+func CheckTemplate(path string) ([]TemplateIssue, error) {
+	return CheckTemplateWithDefaults(path, nil)
+}
+
+// Check a template that has defaults merged under it, as done for the requests of a collection.
+// Keys set in the defaults count towards the required keys.
+func CheckTemplateWithDefaults(path string, defaults map[string]any) ([]TemplateIssue, error) {
+	slog.Debug("Checking template", "templatePath", path, "defaultCount", len(defaults))
+	checker := newTemplateChecker(defaults)
+	if _, err := os.Stat(path); err != nil {
+		slog.Error("Failed to read template file", "templatePath", path, "error", err)
+		return nil, fmt.Errorf("%w %q: %v", utils.ErrorFailedToReadFile, path, err)
+	}
+	checker.checkFile(path, nil)
+	checker.checkRequired(path, nil)
+	slog.Debug("Template check completed", "templatePath", path, "issueCount", len(checker.issues))
+	return checker.issues, nil
+}
+
+// Check a template written inline in another file, such as a request of a collection, against the template schema.
+// Keys in skip belong to the enclosing file and are left out, keys set in the defaults count towards the required keys.
+// Relative paths are resolved against the directory of path.
+func CheckInlineTemplate(path string, node ast.Node, skip []string, defaults map[string]any) []TemplateIssue {
+	slog.Debug("Checking inline template", "path", path)
+	checker := newTemplateChecker(defaults)
+	pairs, ok := mappingPairs(node)
+	if !ok || len(pairs) == 0 {
+		checker.add(path, node, "expected a mapping for the inline template")
+		return checker.issues
+	}
+	var templatePairs []*ast.MappingValueNode
+	for _, pair := range pairs {
+		if !slices.Contains(skip, pair.Key.String()) {
+			templatePairs = append(templatePairs, pair)
+		}
+	}
+	checker.checkDocument(path, node, templatePairs)
+	// A mapping is positioned at its first value, so missing keys are reported at its first key instead.
+	checker.checkRequired(path, pairs[0].Key)
+	slog.Debug("Inline template check completed", "path", path, "issueCount", len(checker.issues))
+	return checker.issues
+}
+
+// Check template keys written inline in another file that are merged under templates, such as the defaults of a
+// collection. Keys and values are checked as in a template, but required keys may be left to the templates.
+func CheckTemplateDefaults(path string, node ast.Node) []TemplateIssue {
+	slog.Debug("Checking template defaults", "path", path)
+	checker := newTemplateChecker(nil)
+	pairs, ok := mappingPairs(node)
+	if !ok {
+		checker.add(path, node, "expected a mapping for the defaults")
+		return checker.issues
+	}
+	checker.checkDocument(path, node, pairs)
+	return checker.issues
+}
+
+func newTemplateChecker(defaults map[string]any) *templateChecker {
+	checker := &templateChecker{visited: make(map[string]bool), keys: make(map[string]bool)}
+	for key := range defaults {
+		checker.keys[key] = true
+	}
+	return checker
+}
+
+// Report the required keys that were set neither in the template chain nor in the defaults.
+func (c *templateChecker) checkRequired(path string, node ast.Node) {
+	for _, key := range templateSchema.required {
+		if !c.incomplete && !c.keys[key] {
+			c.add(path, node, fmt.Sprintf("missing required key %q", key))
+		}
+	}
+}
+
+// Check a single file and follow its extends key.
+func (c *templateChecker) checkFile(path string, extendsNode ast.Node) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = filepath.Clean(path)
+	}
+	if c.visited[absPath] {
+		c.issues = append(c.issues, c.issueAt(path, extendsNode, ErrorTemplateCycle.Error()))
+		c.incomplete = true
+		return
+	}
+	c.visited[absPath] = true
+	content, err := os.ReadFile(path)
+	if err != nil {
+		c.issues = append(c.issues, c.issueAt(path, extendsNode, fmt.Sprintf("failed to read template: %v", err)))
+		c.incomplete = true
+		return
+	}
+	file, err := parser.ParseBytes(content, 0)
+	if err != nil {
+		var yamlErr yaml.Error
+		if errors.As(err, &yamlErr) && yamlErr.GetToken() != nil {
+			position := yamlErr.GetToken().Position
+			c.issues = append(c.issues, TemplateIssue{Path: path, Line: position.Line, Column: position.Column, Message: yamlErr.GetMessage()})
+		} else {
+			c.add(path, nil, err.Error())
+		}
+		c.incomplete = true
+		return
+	}
+	if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		c.add(path, nil, "template is empty")
+		return
+	}
+	root := file.Docs[0].Body
+	pairs, ok := mappingPairs(root)
+	if !ok {
+		c.add(path, root, "expected a mapping at the top level of the template")
+		return
+	}
+	c.checkDocument(path, root, pairs)
+}
+
+// Check the top-level keys of a template and follow its extends key.
+func (c *templateChecker) checkDocument(path string, root ast.Node, pairs []*ast.MappingValueNode) {
+	c.checkObject(path, root, pairs, templateSchema, "")
+	for _, pair := range pairs {
+		key := pair.Key.String()
+		c.keys[key] = true
+		if key != "extends" {
+			continue
+		}
+		basePath, ok := scalarValue(pair.Value)
+		if !ok || strings.TrimSpace(basePath) == "" {
+			continue
+		}
+		if !filepath.IsAbs(basePath) {
+			basePath = filepath.Join(filepath.Dir(path), basePath)
+		}
+		c.checkFile(basePath, pair.Value)
+	}
+}
+
+// Check a value against a schema node.
+func (c *templateChecker) checkNode(path string, node ast.Node, schema *schemaNode, name string) {
+	node = unwrapNode(node)
+	if node == nil || node.Type() == ast.NullType || node.Type() == ast.AliasType || schema.kind == kindAny {
+		return
+	}
+	switch schema.kind {
+	case kindString:
+		value, ok := scalarValue(node)
+		if !ok {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+			return
+		}
+		if strings.Contains(value, "{{") {
+			return
+		}
+		if len(schema.enum) > 0 && !containsFold(schema.enum, value) {
+			c.add(path, node, fmt.Sprintf("%s must be one of %s, found %q", name, strings.Join(schema.enum, ", "), value))
+		}
+		if schema.fileExists && value != "" {
+			filePath := value
+			if !filepath.IsAbs(filePath) {
+				filePath = filepath.Join(filepath.Dir(path), filePath)
+			}
+			if _, err := os.Stat(filePath); err != nil {
+				c.add(path, node, fmt.Sprintf("%s %q does not exist", name, value))
+			}
+		}
+	case kindNumber:
+		if node.Type() != ast.IntegerType && node.Type() != ast.FloatType {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+			return
+		}
+		value, err := strconv.ParseFloat(node.String(), 64)
+		if err == nil && schema.exclusiveMin != nil && value <= *schema.exclusiveMin {
+			c.add(path, node, fmt.Sprintf("%s must be greater than %v, found %v", name, *schema.exclusiveMin, value))
+		}
+	case kindBool:
+		if node.Type() != ast.BoolType {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+		}
+	case kindMultiValue:
+		if sequence, ok := node.(*ast.SequenceNode); ok {
+			for _, item := range sequence.Values {
+				if _, ok := scalarValue(unwrapNode(item)); !ok {
+					c.add(path, item, fmt.Sprintf("%s must be %s", name, schema.kind))
+				}
+			}
+			return
+		}
+		if _, ok := scalarValue(node); !ok {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+		}
+	case kindArray:
+		sequence, ok := node.(*ast.SequenceNode)
+		if !ok {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+			return
+		}
+		for i, item := range sequence.Values {
+			c.checkNode(path, item, schema.items, fmt.Sprintf("%s[%d]", name, i))
+		}
+	case kindMap:
+		pairs, ok := mappingPairs(node)
+		if !ok {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+			return
+		}
+		for _, pair := range pairs {
+			c.checkNode(path, pair.Value, schema.items, name+"."+pair.Key.String())
+		}
+	case kindObject:
+		pairs, ok := mappingPairs(node)
+		if !ok {
+			c.add(path, node, fmt.Sprintf("%s must be %s", name, schema.kind))
+			return
+		}
+		c.checkObject(path, node, pairs, schema, name)
+	}
+}
+
+// Check the keys of an object against the schema properties, reporting unknown, duplicate and missing keys.
+func (c *templateChecker) checkObject(path string, node ast.Node, pairs []*ast.MappingValueNode, schema *schemaNode, name string) {
+	if schema != templateSchema {
+		present := make(map[string]bool, len(pairs))
+		for _, pair := range pairs {
+			present[pair.Key.String()] = true
+		}
+		for _, key := range schema.required {
+			if !present[key] {
+				c.add(path, node, fmt.Sprintf("%s is missing required key %q", name, key))
+			}
+		}
+	}
+	seen := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		key := pair.Key.String()
+		qualified := key
+		if name != "" {
+			qualified = name + "." + key
+		}
+		if seen[key] {
+			c.add(path, pair.Key, fmt.Sprintf("duplicate key %q", qualified))
+			continue
+		}
+		seen[key] = true
+		property, exists := schema.properties[key]
+		if !exists {
+			message := fmt.Sprintf("unknown key %q", qualified)
+			if suggestion := closestName(key, schema.propertyNames()); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			c.add(path, pair.Key, message)
+			continue
+		}
+		c.checkNode(path, pair.Value, property, qualified)
+	}
+}
+
+// Record an issue at the position of node, or at the start of the file when node is nil.
+func (c *templateChecker) add(path string, node ast.Node, message string) {
+	c.issues = append(c.issues, c.issueAt(path, node, message))
+}
+
+func (c *templateChecker) issueAt(path string, node ast.Node, message string) TemplateIssue {
+	issue := TemplateIssue{Path: path, Line: 1, Column: 1, Message: message}
+	if node != nil && node.GetToken() != nil {
+		issue.Line = node.GetToken().Position.Line
+		issue.Column = node.GetToken().Position.Column
+	}
+	return issue
+}
+
+// Return the key-value pairs of a mapping node.
+func mappingPairs(node ast.Node) ([]*ast.MappingValueNode, bool) {
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingNode:
+		return n.Values, true
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}, true
+	default:
+		return nil, false
+	}
+}
+
+// Return the text of a scalar node.
+func scalarValue(node ast.Node) (string, bool) {
+	switch n := unwrapNode(node).(type) {
+	case *ast.StringNode:
+		return n.Value, true
+	case *ast.LiteralNode:
+		return n.Value.Value, true
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.InfinityNode, *ast.NanNode:
+		return n.String(), true
+	default:
+		return "", false
+	}
+}
+
+// Skip anchors and tags to reach the node holding the value.
+func unwrapNode(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+// Report whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the name closest to key when it is within two edits, for "did you mean" hints.
+func closestName(key string, names []string) string {
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if distance := editDistance(key, name); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
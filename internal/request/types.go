@@ -2,25 +2,121 @@ package request
 
 import (
 	"net/http/cookiejar"
+	"reqcorder/pkg/jsonschema"
 	"time"
 )
 
 // RequestObject represents an HTTP request with all its configuration options and metadata.
 type RequestObject struct {
-	TemplateHash   string            `yaml:"template_hash"`
-	URL            string            `yaml:"url"`
-	Method         string            `yaml:"method"`
-	Headers        map[string]string `yaml:"headers,omitempty"`
-	Cookies        map[string]string `yaml:"cookies,omitempty"`
-	CookieJar      *cookiejar.Jar    `yaml:"-"`
-	Auth           string            `yaml:"auth,omitempty"`
-	AuthType       string            `yaml:"auth_type,omitempty"`
-	AuthHeaderName string            `yaml:"auth_header_name,omitempty"`
-	UserAgent      string            `yaml:"user_agent,omitempty"`
-	Body           string            `yaml:"body,omitempty"`
-	Timeout        time.Duration     `yaml:"-"`
-	TimeoutSeconds float64           `yaml:"timeout,omitempty"`
-	BodyVars       map[string]string `yaml:"body_vars,omitempty"`
-	SSLVerify      *bool             `yaml:"ssl_verify,omitempty"`
-	CACertPath     string            `yaml:"ca_cert_path,omitempty"`
+	TemplateHash   string                `yaml:"template_hash"`
+	URL            string                `yaml:"url"`
+	Query          map[string]MultiValue `yaml:"query,omitempty"`
+	Method         string                `yaml:"method"`
+	Headers        map[string]string     `yaml:"headers,omitempty"`
+	Cookies        map[string]string     `yaml:"cookies,omitempty"`
+	CookieJar      *cookiejar.Jar        `yaml:"-"`
+	Auth           string                `yaml:"auth,omitempty"`
+	AuthType       string                `yaml:"auth_type,omitempty"`
+	AuthHeaderName string                `yaml:"auth_header_name,omitempty"`
+	UserAgent      string                `yaml:"user_agent,omitempty"`
+	Body           string                `yaml:"body,omitempty"`
+	BodyFile       string                `yaml:"body_file,omitempty"`
+	BodyFileSubst  *bool                 `yaml:"body_file_substitute,omitempty"`
+	BodyFileSize   int64                 `yaml:"body_file_size,omitempty"`
+	BodyFileSHA256 string                `yaml:"body_file_sha256,omitempty"`
+	BinaryBody     []byte                `yaml:"-"`
+	Form           map[string]MultiValue `yaml:"form,omitempty"`
+	JSON           any                   `yaml:"json,omitempty"`
+	Multipart      *MultipartObject      `yaml:"multipart,omitempty"`
+	Timeout        time.Duration         `yaml:"-"`
+	TimeoutSeconds float64               `yaml:"timeout,omitempty"`
+	BodyVars       map[string]string     `yaml:"body_vars,omitempty"`
+	SSLVerify      *bool                 `yaml:"ssl_verify,omitempty"`
+	CACertPath     string                `yaml:"ca_cert_path,omitempty"`
+	Environment    string                `yaml:"environment,omitempty"`
+	Overrides      map[string]string     `yaml:"overrides,omitempty"`
+	Strict         *bool                 `yaml:"strict,omitempty"`
+	Capture        map[string]Capture    `yaml:"capture,omitempty"`
+	Captured       map[string]string     `yaml:"captured,omitempty"`
+	Expect         *Expectation          `yaml:"expect,omitempty"`
+	Baseline       *BaselineOptions      `yaml:"baseline,omitempty"`
+	Generated      []GeneratedValue      `yaml:"generated,omitempty"`
+	TemplateDir    string                `yaml:"-"`
+
+	templateSource    string
+	environmentPath   string
+	bodyFileSource    string
+	emptyPlaceholders map[string]bool
+	captured          map[string]string
+}
+
+// MultipartObject describes a multipart/form-data body made of plain fields and file parts.
+type MultipartObject struct {
+	Fields map[string]MultiValue `yaml:"fields,omitempty"`
+	Files  []MultipartFile       `yaml:"files,omitempty"`
+}
+
+// MultipartFile describes a file part. Size and SHA256 are filled in during validation and recorded in place of the file content.
+type MultipartFile struct {
+	Field       string `yaml:"field"`
+	Path        string `yaml:"path"`
+	Filename    string `yaml:"filename,omitempty"`
+	ContentType string `yaml:"content_type,omitempty"`
+	Size        int64  `yaml:"size,omitempty"`
+	SHA256      string `yaml:"sha256,omitempty"`
+}
+
+// Capture extracts a value from the response into a variable for later requests of a collection run. Exactly one source is set.
+type Capture struct {
+	JSON   string `yaml:"json,omitempty"`
+	Header string `yaml:"header,omitempty"`
+	Cookie string `yaml:"cookie,omitempty"`
+	Status bool   `yaml:"status,omitempty"`
+	Regex  string `yaml:"regex,omitempty"`
+}
+
+// Expectation lists the assertions checked against the response.
+type Expectation struct {
+	// Exact codes such as 200 or classes such as 2xx. The response must match one of them.
+	Status     MultiValue         `yaml:"status,omitempty"`
+	Headers    map[string]Matcher `yaml:"headers,omitempty"`
+	JSON       map[string]Matcher `yaml:"json,omitempty"`
+	Body       *Matcher           `yaml:"body,omitempty"`
+	MaxLatency string             `yaml:"max_latency,omitempty"`
+	MaxSize    int64              `yaml:"max_size,omitempty"`
+	// JSON Schema (draft 2020-12) file the body must match, relative to the template.
+	Schema string `yaml:"schema,omitempty"`
+
+	compiledSchema *jsonschema.Schema
 }
+
+// BaselineOptions configures the comparison of a response with the baseline of its template.
+type BaselineOptions struct {
+	// Locations left out of the comparison: status, body, header:<name>, or a JSONPath pattern such as $.items[*].id.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+// Matcher checks a single value. In YAML a plain value is shorthand for equals.
+type Matcher struct {
+	Equals   any    `yaml:"equals,omitempty"`
+	Contains any    `yaml:"contains,omitempty"`
+	Regex    string `yaml:"regex,omitempty"`
+	Type     string `yaml:"type,omitempty"`
+	Exists   *bool  `yaml:"exists,omitempty"`
+}
+
+// GeneratedValue records the output of a dynamic template function so a run can be reproduced.
+type GeneratedValue struct {
+	Placeholder string `yaml:"placeholder"`
+	Value       string `yaml:"value"`
+}
+
+// EnvironmentObject represents a named set of variables that is merged into a template before execution.
+type EnvironmentObject struct {
+	Name string            `yaml:"-"`
+	Path string            `yaml:"-"`
+	Vars map[string]string `yaml:"vars"`
+}
+
+// MultiValue holds one or more string values. In YAML it accepts either a scalar or a list of scalars.
+type MultiValue []string
@@ -0,0 +1,19 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Look up a response header. Header names are matched case-insensitively.
+func (r *ResponseObject) Header(name string) (string, bool) {
+	if value, exists := r.Headers[http.CanonicalHeaderKey(name)]; exists {
+		return value, true
+	}
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
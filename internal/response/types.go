@@ -15,6 +15,44 @@ type ResponseObject struct {
 	Size         int64             `yaml:"size_bytes"`
 	Timing       ResponseTimes     `yaml:"timing"`
 	Cookies      []*http.Cookie    `yaml:"cookies"`
+	Captured     map[string]string `yaml:"captured,omitempty"`
+	Assertions   []Assertion       `yaml:"assertions,omitempty"`
+	// Locations where the body does not match the JSON Schema of the expect block.
+	SchemaViolations []SchemaViolation `yaml:"schema_violations,omitempty"`
+	// Comparison with the baseline of the template, when it was checked.
+	Baseline *BaselineComparison `yaml:"baseline,omitempty"`
+}
+
+// Assertion is the outcome of a single check from the template's expect block.
+type Assertion struct {
+	Check    string `yaml:"check"`
+	Expected string `yaml:"expected"`
+	Actual   string `yaml:"actual"`
+	Passed   bool   `yaml:"passed"`
+}
+
+// SchemaViolation is a location in the response body that does not match the JSON Schema.
+type SchemaViolation struct {
+	// JSON pointer to the failing value in the body, empty for the whole body.
+	Path string `yaml:"path"`
+	// JSON pointer to the failing keyword in the schema.
+	Keyword string `yaml:"keyword"`
+	Message string `yaml:"message"`
+}
+
+// BaselineComparison lists how a response differs from the baseline response of its template.
+type BaselineComparison struct {
+	ResponseID  string       `yaml:"response_id"`
+	Differences []Difference `yaml:"differences,omitempty"`
+}
+
+// Difference is a single location where a response differs from its baseline.
+type Difference struct {
+	// status, header:<name>, body, or a JSONPath into a JSON body.
+	Path     string `yaml:"path"`
+	Kind     string `yaml:"kind"`
+	Baseline string `yaml:"baseline,omitempty"`
+	Actual   string `yaml:"actual,omitempty"`
 }
 
 // ResponseTimes contains timing information for various stages of an HTTP request.
@@ -0,0 +1,114 @@
+package watch
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileState is what the watcher remembers about a file between polls. Missing files are watched too,
+// so creating one counts as a change.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// Watcher polls a set of files and reports when any of them is created, modified, or removed.
+type Watcher struct {
+	// Interval is the time between two polls.
+	Interval time.Duration
+	// Debounce is how long the files must stay unchanged before a change is reported, so that editors
+	// writing a file in several steps trigger a single run.
+	Debounce time.Duration
+
+	states map[string]fileState
+}
+
+// Create a watcher with the given poll interval and debounce period.
+func New(interval time.Duration, debounce time.Duration) *Watcher {
+	return &Watcher{Interval: interval, Debounce: debounce, states: make(map[string]fileState)}
+}
+
+// Replace the watched files and remember their current state. Paths are made absolute and duplicates are dropped.
+func (w *Watcher) Watch(paths []string) {
+	w.states = make(map[string]fileState, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+		w.states[path] = stat(path)
+	}
+	slog.Debug("Watching files", "files", w.Files())
+}
+
+// Return the watched files in sorted order.
+func (w *Watcher) Files() []string {
+	files := make([]string, 0, len(w.states))
+	for path := range w.states {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Poll the watched files once and return the ones that changed since the previous poll, in sorted order.
+func (w *Watcher) Changed() []string {
+	var changed []string
+	for path, previous := range w.states {
+		current := stat(path)
+		if current != previous {
+			w.states[path] = current
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Block until a watched file changes and the files have then stayed unchanged for the debounce period.
+// Every file changed in the meantime is returned. The error is the context error when the context ends first.
+func (w *Watcher) Wait(ctx context.Context) ([]string, error) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	seen := make(map[string]bool)
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case now := <-ticker.C:
+			changed := w.Changed()
+			for _, path := range changed {
+				seen[path] = true
+			}
+			if len(changed) > 0 {
+				lastChange = now
+				continue
+			}
+			if len(seen) > 0 && now.Sub(lastChange) >= w.Debounce {
+				files := make([]string, 0, len(seen))
+				for path := range seen {
+					files = append(files, path)
+				}
+				sort.Strings(files)
+				slog.Debug("Watched files changed", "files", files)
+				return files, nil
+			}
+		}
+	}
+}
+
+func stat(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
@@ -0,0 +1,75 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template.yaml")
+	env := filepath.Join(dir, "env.yaml")
+	if err := os.WriteFile(template, []byte("url: https://example.com"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	w := New(time.Millisecond, time.Millisecond)
+	w.Watch([]string{template, env, template, ""})
+	if !reflect.DeepEqual(w.Files(), []string{env, template}) {
+		t.Fatalf("Expected watched files %v, received %v", []string{env, template}, w.Files())
+	}
+	if changed := w.Changed(); changed != nil {
+		t.Fatalf("Expected no changes, received %v", changed)
+	}
+	if err := os.WriteFile(template, []byte("url: https://example.com/users"), 0644); err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+	if err := os.WriteFile(env, []byte("vars: {}"), 0644); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if changed := w.Changed(); !reflect.DeepEqual(changed, []string{env, template}) {
+		t.Errorf("Expected changes %v, received %v", []string{env, template}, changed)
+	}
+	if err := os.Remove(env); err != nil {
+		t.Fatalf("Failed to remove environment: %v", err)
+	}
+	if changed := w.Changed(); !reflect.DeepEqual(changed, []string{env}) {
+		t.Errorf("Expected removal of %v, received %v", env, changed)
+	}
+}
+
+func TestWait(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	w := New(5*time.Millisecond, 50*time.Millisecond)
+	w.Watch([]string{first, second})
+	go func() {
+		os.WriteFile(first, []byte("1"), 0644)
+		time.Sleep(20 * time.Millisecond)
+		os.WriteFile(second, []byte("2"), 0644)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := w.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(changed, []string{first, second}) {
+		t.Errorf("Expected changes within the debounce period to be reported together, received %v", changed)
+	}
+}
+
+func TestWait_Cancelled(t *testing.T) {
+	w := New(time.Millisecond, time.Millisecond)
+	w.Watch([]string{filepath.Join(t.TempDir(), "template.yaml")})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := w.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, received %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,8 @@
+package jsonpath
+
+import "errors"
+
+var (
+	ErrorInvalidPath = errors.New("invalid JSONPath")
+	ErrorNoMatch     = errors.New("JSONPath matched nothing")
+)
@@ -0,0 +1,215 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is a single step of a path, either an object key or an array index. Patterns may also use a wildcard.
+type segment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Path is a parsed JSONPath that selects a single value.
+// Supported syntax is the root $, dot children ($.a.b), quoted bracket children ($['a-b']) and array indexes ($.items[0], $.items[-1]).
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+// Parse a JSONPath expression.
+func Parse(expression string) (Path, error) {
+	return parse(expression, false)
+}
+
+// Parse a JSONPath pattern used to match locations. In addition to Parse, .* and [*] match any key or index.
+// Locations are matched without knowing the length of their arrays, so negative indexes are rejected.
+func ParsePattern(expression string) (Path, error) {
+	return parse(expression, true)
+}
+
+func parse(expression string, isPattern bool) (Path, error) {
+	path := Path{raw: expression}
+	rest := strings.TrimSpace(expression)
+	if !strings.HasPrefix(rest, "$") {
+		return path, fmt.Errorf("%w %q: must start with $", ErrorInvalidPath, expression)
+	}
+	rest = rest[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return path, fmt.Errorf("%w %q: empty key", ErrorInvalidPath, expression)
+			}
+			if rest[:end] == "*" {
+				if !isPattern {
+					return path, fmt.Errorf("%w %q: wildcards are not supported", ErrorInvalidPath, expression)
+				}
+				path.segments = append(path.segments, segment{wildcard: true})
+				rest = rest[end:]
+				continue
+			}
+			path.segments = append(path.segments, segment{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return path, fmt.Errorf("%w %q: unclosed bracket", ErrorInvalidPath, expression)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path.segments = append(path.segments, segment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			if inner == "*" {
+				if !isPattern {
+					return path, fmt.Errorf("%w %q: wildcards are not supported", ErrorInvalidPath, expression)
+				}
+				path.segments = append(path.segments, segment{wildcard: true})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return path, fmt.Errorf("%w %q: unsupported selector [%s]", ErrorInvalidPath, expression, inner)
+			}
+			if index < 0 && isPattern {
+				return path, fmt.Errorf("%w %q: negative index [%d] is not supported in patterns", ErrorInvalidPath, expression, index)
+			}
+			path.segments = append(path.segments, segment{index: index, isIndex: true})
+		default:
+			return path, fmt.Errorf("%w %q: unexpected %q", ErrorInvalidPath, expression, rest[:1])
+		}
+	}
+	return path, nil
+}
+
+func (p Path) String() string {
+	if p.raw == "" {
+		return p.prefix(len(p.segments))
+	}
+	return p.raw
+}
+
+// Return the path of a key of the object at this path.
+func (p Path) Key(key string) Path {
+	return Path{segments: append(append([]segment(nil), p.segments...), segment{key: key})}
+}
+
+// Return the path of an index of the array at this path.
+func (p Path) Index(index int) Path {
+	return Path{segments: append(append([]segment(nil), p.segments...), segment{index: index, isIndex: true})}
+}
+
+// Report whether the path is the pattern or lies below it. Wildcards match any single key or index.
+func (p Path) Within(pattern Path) bool {
+	if len(pattern.segments) > len(p.segments) {
+		return false
+	}
+	for i, want := range pattern.segments {
+		got := p.segments[i]
+		if want.wildcard {
+			continue
+		}
+		if want.isIndex != got.isIndex || want.key != got.key || want.index != got.index {
+			return false
+		}
+	}
+	return true
+}
+
+// Select the value at the path in a document decoded from JSON.
+func (p Path) Evaluate(doc any) (any, error) {
+	current := doc
+	for i, seg := range p.segments {
+		if seg.wildcard {
+			return nil, fmt.Errorf("%w %q: wildcards select more than one value", ErrorInvalidPath, p.String())
+		}
+		switch value := current.(type) {
+		case map[string]any:
+			if seg.isIndex {
+				return nil, fmt.Errorf("%w: %s is an object, not an array", ErrorNoMatch, p.prefix(i))
+			}
+			child, exists := value[seg.key]
+			if !exists {
+				return nil, fmt.Errorf("%w: %s has no key %q", ErrorNoMatch, p.prefix(i), seg.key)
+			}
+			current = child
+		case []any:
+			if !seg.isIndex {
+				return nil, fmt.Errorf("%w: %s is an array, not an object", ErrorNoMatch, p.prefix(i))
+			}
+			index := seg.index
+			if index < 0 {
+				index += len(value)
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("%w: %s has no index %d", ErrorNoMatch, p.prefix(i), seg.index)
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("%w: %s is not an object or array", ErrorNoMatch, p.prefix(i))
+		}
+	}
+	return current, nil
+}
+
+// Render the path up to segment n. Keys that cannot be written after a dot are quoted in brackets.
+func (p Path) prefix(n int) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, seg := range p.segments[:n] {
+		switch {
+		case seg.wildcard:
+			sb.WriteString("[*]")
+		case seg.isIndex:
+			sb.WriteString("[" + strconv.Itoa(seg.index) + "]")
+		case seg.key == "" || strings.ContainsAny(seg.key, ".[]'\" *"):
+			sb.WriteString("['" + seg.key + "']")
+		default:
+			sb.WriteString("." + seg.key)
+		}
+	}
+	return sb.String()
+}
+
+// Decode a JSON document, keeping numbers as json.Number so they are rendered as written.
+func Decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Render a selected value as text. Strings are returned as is, every other value as compact JSON.
+func Format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+}
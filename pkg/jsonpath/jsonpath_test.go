@@ -0,0 +1,173 @@
+package jsonpath
+
+import (
+	"errors"
+	"testing"
+)
+
+const document = `{"id": 7, "items": [{"id": 1, "name": "first"}, {"id": 2, "name": "second"}], "meta": {"created-at": "today", "count": 2.50}}`
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression    string
+		expected      string
+		expectedError error
+	}{
+		{expression: "$", expected: `{"id":7,"items":[{"id":1,"name":"first"},{"id":2,"name":"second"}],"meta":{"count":2.50,"created-at":"today"}}`},
+		{expression: "$.id", expected: "7"},
+		{expression: "$.items[0].name", expected: "first"},
+		{expression: "$.items[1].id", expected: "2"},
+		{expression: "$.items[-1].name", expected: "second"},
+		{expression: "$.items[-2].name", expected: "first"},
+		{expression: "$.meta['created-at']", expected: "today"},
+		{expression: `$.meta["count"]`, expected: "2.50"},
+		{expression: "$.items[2]", expectedError: ErrorNoMatch},
+		{expression: "$.items[-3]", expectedError: ErrorNoMatch},
+		{expression: "$.missing", expectedError: ErrorNoMatch},
+		{expression: "$.items.id", expectedError: ErrorNoMatch},
+		{expression: "$.meta[0]", expectedError: ErrorNoMatch},
+		{expression: "$.id.value", expectedError: ErrorNoMatch},
+	}
+	doc, err := Decode([]byte(document))
+	if err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			path, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			value, err := path.Evaluate(doc)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("Expected error %v, received %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if formatted := Format(value); formatted != tt.expected {
+				t.Errorf("Expected %q, received %q", tt.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression      string
+		isPattern       bool
+		expectedInvalid bool
+	}{
+		{expression: "$"},
+		{expression: " $.items[0].id "},
+		{expression: "$.items[-1]"},
+		{expression: "$['a b'].c"},
+		{expression: "$.items[*].id", isPattern: true},
+		{expression: "$.*.updated_at", isPattern: true},
+		{expression: "$.items[*].id", expectedInvalid: true},
+		{expression: "$.*", expectedInvalid: true},
+		{expression: "$.items[-1]", isPattern: true, expectedInvalid: true},
+		{expression: "items", expectedInvalid: true},
+		{expression: "", expectedInvalid: true},
+		{expression: "$.", expectedInvalid: true},
+		{expression: "$..id", expectedInvalid: true},
+		{expression: "$.items[", isPattern: true, expectedInvalid: true},
+		{expression: "$.items[first]", expectedInvalid: true},
+		{expression: "$.items[1:2]", expectedInvalid: true},
+		{expression: "$items", expectedInvalid: true},
+	}
+	for _, tt := range tests {
+		name := tt.expression
+		if tt.isPattern {
+			name = "pattern " + name
+		}
+		t.Run(name, func(t *testing.T) {
+			parse := Parse
+			if tt.isPattern {
+				parse = ParsePattern
+			}
+			_, err := parse(tt.expression)
+			if tt.expectedInvalid {
+				if !errors.Is(err, ErrorInvalidPath) {
+					t.Errorf("Expected error %v, received %v", ErrorInvalidPath, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, received %v", err)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Wildcard(t *testing.T) {
+	path, err := ParsePattern("$.items[*].id")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	doc, err := Decode([]byte(document))
+	if err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+	if _, err := path.Evaluate(doc); !errors.Is(err, ErrorInvalidPath) {
+		t.Errorf("Expected error %v, received %v", ErrorInvalidPath, err)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	root, err := Parse("$")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	itemID := root.Key("items").Index(1).Key("id")
+	tests := []struct {
+		pattern  string
+		expected bool
+	}{
+		{pattern: "$", expected: true},
+		{pattern: "$.items", expected: true},
+		{pattern: "$.items[1]", expected: true},
+		{pattern: "$.items[1].id", expected: true},
+		{pattern: "$.items[*].id", expected: true},
+		{pattern: "$.*[1]", expected: true},
+		{pattern: "$['items'][1]", expected: true},
+		{pattern: "$.items[0]", expected: false},
+		{pattern: "$.items.id", expected: false},
+		{pattern: "$.item", expected: false},
+		{pattern: "$.items[1].id.value", expected: false},
+		{pattern: "$.items[*].name", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			pattern, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			if within := itemID.Within(pattern); within != tt.expected {
+				t.Errorf("Expected %s within %s to be %v, received %v", itemID, tt.pattern, tt.expected, within)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	root, err := Parse("$")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	path := root.Key("meta").Key("created at").Key("").Key("plain").Index(-1)
+	expected := "$.meta['created at'][''].plain[-1]"
+	if path.String() != expected {
+		t.Errorf("Expected %q, received %q", expected, path.String())
+	}
+	path, err = Parse("$.items[0]")
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	if path.String() != "$.items[0]" {
+		t.Errorf("Expected the expression to be kept, received %q", path.String())
+	}
+}
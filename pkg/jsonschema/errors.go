@@ -0,0 +1,8 @@
+package jsonschema
+
+import "errors"
+
+var (
+	ErrorFailedToLoadSchema = errors.New("failed to load JSON Schema")
+	ErrorInvalidSchema      = errors.New("invalid JSON Schema")
+)
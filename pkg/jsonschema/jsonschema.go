@@ -0,0 +1,685 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"reqcorder/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Deeply nested $ref chains beyond this depth are reported instead of followed, which stops self-referencing schemas.
+const maxRefDepth = 64
+
+// Violation is a location in the instance that does not satisfy the schema.
+type Violation struct {
+	// JSON pointer to the failing location in the instance, empty for the root.
+	InstancePath string `yaml:"path"`
+	// JSON pointer to the failing keyword in the schema.
+	SchemaPath string `yaml:"schema_path"`
+	Message    string `yaml:"message"`
+}
+
+func (v Violation) String() string {
+	path := v.InstancePath
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// document is a loaded schema file.
+type document struct {
+	path string
+	root any
+}
+
+// Schema is a JSON Schema (draft 2020-12) loaded from a file, with every $ref resolved.
+// Supported are the applicator and validation vocabularies, local and file $refs, and $defs.
+// format is treated as an annotation, and unevaluatedProperties and unevaluatedItems are not checked.
+type Schema struct {
+	root     *document
+	docs     map[string]*document
+	refs     map[string]resolvedRef
+	patterns map[string]*regexp.Regexp
+}
+
+type resolvedRef struct {
+	doc    *document
+	node   any
+	target string
+}
+
+// Load a schema file and resolve its references.
+func Load(path string) (*Schema, error) {
+	s := &Schema{
+		docs:     make(map[string]*document),
+		refs:     make(map[string]resolvedRef),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	doc, err := s.loadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	s.root = doc
+	if err := s.prepare(doc, doc.root, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path of the root schema file.
+func (s *Schema) Path() string {
+	return s.root.path
+}
+
+// Validate an instance decoded with json.Decoder.UseNumber, returning every violation found.
+func (s *Schema) Validate(instance any) []Violation {
+	return s.validate(s.root, s.root.root, instance, "", "", 0)
+}
+
+// Decode a JSON document, keeping numbers exact, and validate it.
+func (s *Schema) ValidateJSON(data []byte) ([]Violation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance any
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, err
+	}
+	return s.Validate(instance), nil
+}
+
+func (s *Schema) loadDocument(path string) (*document, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = filepath.Clean(path)
+	}
+	if doc, exists := s.docs[absPath]; exists {
+		return doc, nil
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrorFailedToLoadSchema, path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var root any
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrorInvalidSchema, path, err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("%w %q: a schema must be an object or a boolean", ErrorInvalidSchema, path)
+	}
+	doc := &document{path: absPath, root: root}
+	s.docs[absPath] = doc
+	return doc, nil
+}
+
+// Walk a schema, compiling patterns and resolving references, including those in referenced files.
+func (s *Schema) prepare(doc *document, node any, schemaPath string) error {
+	switch n := node.(type) {
+	case map[string]any:
+		for _, key := range []string{"pattern"} {
+			if pattern, ok := n[key].(string); ok {
+				if _, err := s.compile(pattern); err != nil {
+					return fmt.Errorf("%w %q: %s/%s: %v", ErrorInvalidSchema, doc.path, schemaPath, key, err)
+				}
+			}
+		}
+		if patterns, ok := n["patternProperties"].(map[string]any); ok {
+			for pattern := range patterns {
+				if _, err := s.compile(pattern); err != nil {
+					return fmt.Errorf("%w %q: %s/patternProperties: %v", ErrorInvalidSchema, doc.path, schemaPath, err)
+				}
+			}
+		}
+		if ref, ok := n["$ref"].(string); ok {
+			key := doc.path + "|" + ref
+			if _, done := s.refs[key]; !done {
+				resolved, err := s.resolve(doc, ref)
+				if errors.Is(err, ErrorFailedToLoadSchema) || errors.Is(err, ErrorInvalidSchema) {
+					return err
+				}
+				if err != nil {
+					return fmt.Errorf("%w %q: %s/$ref: %v", ErrorInvalidSchema, doc.path, schemaPath, err)
+				}
+				s.refs[key] = resolved
+				if resolved.doc != doc {
+					if err := s.prepare(resolved.doc, resolved.doc.root, ""); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(n))
+		for key := range n {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "enum" || key == "const" {
+				continue
+			}
+			if err := s.prepare(doc, n[key], schemaPath+"/"+escapePointer(key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range n {
+			if err := s.prepare(doc, item, schemaPath+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve a reference such as "#/$defs/user" or "common.json#/$defs/id" against the document it appears in.
+func (s *Schema) resolve(doc *document, ref string) (resolvedRef, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	target := doc
+	if file != "" {
+		if strings.Contains(file, "://") {
+			return resolvedRef{}, fmt.Errorf("remote reference %q is not supported", ref)
+		}
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(doc.path), path)
+		}
+		loaded, err := s.loadDocument(path)
+		if err != nil {
+			return resolvedRef{}, err
+		}
+		target = loaded
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return resolvedRef{}, fmt.Errorf("anchor reference %q is not supported, use a JSON pointer", ref)
+	}
+	node := target.root
+	for _, token := range splitPointer(fragment) {
+		switch n := node.(type) {
+		case map[string]any:
+			child, exists := n[token]
+			if !exists {
+				return resolvedRef{}, fmt.Errorf("reference %q not found", ref)
+			}
+			node = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(n) {
+				return resolvedRef{}, fmt.Errorf("reference %q not found", ref)
+			}
+			node = n[index]
+		default:
+			return resolvedRef{}, fmt.Errorf("reference %q not found", ref)
+		}
+	}
+	return resolvedRef{doc: target, node: node, target: fragment}, nil
+}
+
+func (s *Schema) compile(pattern string) (*regexp.Regexp, error) {
+	if re, exists := s.patterns[pattern]; exists {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	s.patterns[pattern] = re
+	return re, nil
+}
+
+// Validate an instance against a schema node, collecting violations.
+func (s *Schema) validate(doc *document, node any, instance any, instancePath string, schemaPath string, depth int) []Violation {
+	switch n := node.(type) {
+	case bool:
+		if n {
+			return nil
+		}
+		return []Violation{{InstancePath: instancePath, SchemaPath: schemaPath, Message: "no value is allowed here"}}
+	case map[string]any:
+		v := &validation{schema: s, doc: doc, node: n, instance: instance, instancePath: instancePath, schemaPath: schemaPath, depth: depth}
+		v.run()
+		return v.violations
+	default:
+		return nil
+	}
+}
+
+// validation evaluates one schema object against one instance.
+type validation struct {
+	schema       *Schema
+	doc          *document
+	node         map[string]any
+	instance     any
+	instancePath string
+	schemaPath   string
+	depth        int
+	violations   []Violation
+}
+
+func (v *validation) fail(keyword string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{
+		InstancePath: v.instancePath,
+		SchemaPath:   v.schemaPath + "/" + keyword,
+		Message:      fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate the instance, or a child of it, against a subschema and keep its violations.
+func (v *validation) apply(subschema any, instance any, instancePath string, schemaPath string) []Violation {
+	return v.schema.validate(v.doc, subschema, instance, instancePath, schemaPath, v.depth)
+}
+
+func (v *validation) run() {
+	v.checkRef()
+	v.checkType()
+	v.checkEnum()
+	v.checkNumber()
+	v.checkString()
+	v.checkArray()
+	v.checkObject()
+	v.checkComposition()
+}
+
+func (v *validation) checkRef() {
+	ref, ok := v.node["$ref"].(string)
+	if !ok {
+		return
+	}
+	if v.depth >= maxRefDepth {
+		v.fail("$ref", "maximum $ref depth exceeded")
+		return
+	}
+	resolved := v.schema.refs[v.doc.path+"|"+ref]
+	v.violations = append(v.violations, v.schema.validate(resolved.doc, resolved.node, v.instance, v.instancePath, v.schemaPath+"/$ref", v.depth+1)...)
+}
+
+func (v *validation) checkType() {
+	expected, exists := v.node["type"]
+	if !exists {
+		return
+	}
+	var types []string
+	switch t := expected.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+	actual := typeOf(v.instance)
+	for _, name := range types {
+		if name == actual || (name == "number" && actual == "integer") || (name == "integer" && actual == "number" && isInteger(v.instance)) {
+			return
+		}
+	}
+	v.fail("type", "expected %s, found %s", strings.Join(types, " or "), actual)
+}
+
+func (v *validation) checkEnum() {
+	if values, ok := v.node["enum"].([]any); ok {
+		for _, value := range values {
+			if equal(v.instance, value) {
+				return
+			}
+		}
+		v.fail("enum", "value %s is not one of %s", render(v.instance), render(values))
+	}
+	if value, exists := v.node["const"]; exists && !equal(v.instance, value) {
+		v.fail("const", "value %s must be %s", render(v.instance), render(value))
+	}
+}
+
+func (v *validation) checkNumber() {
+	number, ok := v.instance.(json.Number)
+	if !ok {
+		return
+	}
+	value, ok := new(big.Rat).SetString(number.String())
+	if !ok {
+		return
+	}
+	bound := func(keyword string) (*big.Rat, bool) {
+		limit, ok := v.node[keyword].(json.Number)
+		if !ok {
+			return nil, false
+		}
+		return new(big.Rat).SetString(limit.String())
+	}
+	if limit, ok := bound("minimum"); ok && value.Cmp(limit) < 0 {
+		v.fail("minimum", "must be >= %s, found %s", limit.RatString(), number)
+	}
+	if limit, ok := bound("maximum"); ok && value.Cmp(limit) > 0 {
+		v.fail("maximum", "must be <= %s, found %s", limit.RatString(), number)
+	}
+	if limit, ok := bound("exclusiveMinimum"); ok && value.Cmp(limit) <= 0 {
+		v.fail("exclusiveMinimum", "must be > %s, found %s", limit.RatString(), number)
+	}
+	if limit, ok := bound("exclusiveMaximum"); ok && value.Cmp(limit) >= 0 {
+		v.fail("exclusiveMaximum", "must be < %s, found %s", limit.RatString(), number)
+	}
+	if divisor, ok := bound("multipleOf"); ok && divisor.Sign() > 0 {
+		if !new(big.Rat).Quo(value, divisor).IsInt() {
+			v.fail("multipleOf", "must be a multiple of %s, found %s", divisor.RatString(), number)
+		}
+	}
+}
+
+func (v *validation) checkString() {
+	text, ok := v.instance.(string)
+	if !ok {
+		return
+	}
+	length := utf8.RuneCountInString(text)
+	if limit, ok := intKeyword(v.node, "minLength"); ok && length < limit {
+		v.fail("minLength", "must be at least %d characters, found %d", limit, length)
+	}
+	if limit, ok := intKeyword(v.node, "maxLength"); ok && length > limit {
+		v.fail("maxLength", "must be at most %d characters, found %d", limit, length)
+	}
+	if pattern, ok := v.node["pattern"].(string); ok {
+		if re, err := v.schema.compile(pattern); err == nil && !re.MatchString(text) {
+			v.fail("pattern", "%q does not match pattern %q", text, pattern)
+		}
+	}
+}
+
+func (v *validation) checkArray() {
+	items, ok := v.instance.([]any)
+	if !ok {
+		return
+	}
+	if limit, ok := intKeyword(v.node, "minItems"); ok && len(items) < limit {
+		v.fail("minItems", "must have at least %d items, found %d", limit, len(items))
+	}
+	if limit, ok := intKeyword(v.node, "maxItems"); ok && len(items) > limit {
+		v.fail("maxItems", "must have at most %d items, found %d", limit, len(items))
+	}
+	if unique, ok := v.node["uniqueItems"].(bool); ok && unique {
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if equal(items[i], items[j]) {
+					v.fail("uniqueItems", "items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+	prefixCount := 0
+	if prefixItems, ok := v.node["prefixItems"].([]any); ok {
+		for i, subschema := range prefixItems {
+			if i >= len(items) {
+				break
+			}
+			v.violations = append(v.violations, v.apply(subschema, items[i], v.instancePath+"/"+strconv.Itoa(i), v.schemaPath+"/prefixItems/"+strconv.Itoa(i))...)
+		}
+		prefixCount = len(prefixItems)
+	}
+	if subschema, exists := v.node["items"]; exists {
+		for i := prefixCount; i < len(items); i++ {
+			v.violations = append(v.violations, v.apply(subschema, items[i], v.instancePath+"/"+strconv.Itoa(i), v.schemaPath+"/items")...)
+		}
+	}
+	if subschema, exists := v.node["contains"]; exists {
+		matches := 0
+		for i, item := range items {
+			if len(v.apply(subschema, item, v.instancePath+"/"+strconv.Itoa(i), v.schemaPath+"/contains")) == 0 {
+				matches++
+			}
+		}
+		minContains, hasMin := intKeyword(v.node, "minContains")
+		if !hasMin {
+			minContains = 1
+		}
+		if matches < minContains {
+			v.fail("contains", "must contain at least %d matching items, found %d", minContains, matches)
+		}
+		if maxContains, ok := intKeyword(v.node, "maxContains"); ok && matches > maxContains {
+			v.fail("maxContains", "must contain at most %d matching items, found %d", maxContains, matches)
+		}
+	}
+}
+
+func (v *validation) checkObject() {
+	object, ok := v.instance.(map[string]any)
+	if !ok {
+		return
+	}
+	if limit, ok := intKeyword(v.node, "minProperties"); ok && len(object) < limit {
+		v.fail("minProperties", "must have at least %d properties, found %d", limit, len(object))
+	}
+	if limit, ok := intKeyword(v.node, "maxProperties"); ok && len(object) > limit {
+		v.fail("maxProperties", "must have at most %d properties, found %d", limit, len(object))
+	}
+	if required, ok := v.node["required"].([]any); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok {
+				if _, exists := object[name]; !exists {
+					v.fail("required", "missing required property %q", name)
+				}
+			}
+		}
+	}
+	if dependentRequired, ok := v.node["dependentRequired"].(map[string]any); ok {
+		for _, name := range utils.SortedKeys(dependentRequired) {
+			if _, exists := object[name]; !exists {
+				continue
+			}
+			dependencies, _ := dependentRequired[name].([]any)
+			for _, item := range dependencies {
+				if dependency, ok := item.(string); ok {
+					if _, exists := object[dependency]; !exists {
+						v.fail("dependentRequired", "property %q requires property %q", name, dependency)
+					}
+				}
+			}
+		}
+	}
+	properties, _ := v.node["properties"].(map[string]any)
+	patternProperties, _ := v.node["patternProperties"].(map[string]any)
+	additional, hasAdditional := v.node["additionalProperties"]
+	propertyNames, hasPropertyNames := v.node["propertyNames"]
+	for _, name := range utils.SortedKeys(object) {
+		childPath := v.instancePath + "/" + escapePointer(name)
+		value := object[name]
+		evaluated := false
+		if subschema, exists := properties[name]; exists {
+			evaluated = true
+			v.violations = append(v.violations, v.apply(subschema, value, childPath, v.schemaPath+"/properties/"+escapePointer(name))...)
+		}
+		for _, pattern := range utils.SortedKeys(patternProperties) {
+			if re, err := v.schema.compile(pattern); err == nil && re.MatchString(name) {
+				evaluated = true
+				v.violations = append(v.violations, v.apply(patternProperties[pattern], value, childPath, v.schemaPath+"/patternProperties/"+escapePointer(pattern))...)
+			}
+		}
+		if !evaluated && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.violations = append(v.violations, Violation{InstancePath: childPath, SchemaPath: v.schemaPath + "/additionalProperties", Message: fmt.Sprintf("property %q is not allowed", name)})
+			} else {
+				v.violations = append(v.violations, v.apply(additional, value, childPath, v.schemaPath+"/additionalProperties")...)
+			}
+		}
+		if hasPropertyNames {
+			for _, violation := range v.apply(propertyNames, name, childPath, v.schemaPath+"/propertyNames") {
+				violation.Message = fmt.Sprintf("property name %q: %s", name, violation.Message)
+				v.violations = append(v.violations, violation)
+			}
+		}
+	}
+	if dependentSchemas, ok := v.node["dependentSchemas"].(map[string]any); ok {
+		for _, name := range utils.SortedKeys(dependentSchemas) {
+			if _, exists := object[name]; exists {
+				v.violations = append(v.violations, v.apply(dependentSchemas[name], v.instance, v.instancePath, v.schemaPath+"/dependentSchemas/"+escapePointer(name))...)
+			}
+		}
+	}
+}
+
+func (v *validation) checkComposition() {
+	if subschemas, ok := v.node["allOf"].([]any); ok {
+		for i, subschema := range subschemas {
+			v.violations = append(v.violations, v.apply(subschema, v.instance, v.instancePath, v.schemaPath+"/allOf/"+strconv.Itoa(i))...)
+		}
+	}
+	if subschemas, ok := v.node["anyOf"].([]any); ok {
+		matched := false
+		for i, subschema := range subschemas {
+			if len(v.apply(subschema, v.instance, v.instancePath, v.schemaPath+"/anyOf/"+strconv.Itoa(i))) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail("anyOf", "does not match any of the %d allowed schemas", len(subschemas))
+		}
+	}
+	if subschemas, ok := v.node["oneOf"].([]any); ok {
+		matches := 0
+		for i, subschema := range subschemas {
+			if len(v.apply(subschema, v.instance, v.instancePath, v.schemaPath+"/oneOf/"+strconv.Itoa(i))) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.fail("oneOf", "must match exactly one of the %d allowed schemas, matched %d", len(subschemas), matches)
+		}
+	}
+	if subschema, exists := v.node["not"]; exists {
+		if len(v.apply(subschema, v.instance, v.instancePath, v.schemaPath+"/not")) == 0 {
+			v.fail("not", "must not match the schema under not")
+		}
+	}
+	if condition, exists := v.node["if"]; exists {
+		if len(v.apply(condition, v.instance, v.instancePath, v.schemaPath+"/if")) == 0 {
+			if then, exists := v.node["then"]; exists {
+				v.violations = append(v.violations, v.apply(then, v.instance, v.instancePath, v.schemaPath+"/then")...)
+			}
+		} else if otherwise, exists := v.node["else"]; exists {
+			v.violations = append(v.violations, v.apply(otherwise, v.instance, v.instancePath, v.schemaPath+"/else")...)
+		}
+	}
+}
+
+// Name the JSON type of an instance. Numbers without a fraction or exponent are integers.
+func typeOf(instance any) string {
+	switch value := instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if !strings.ContainsAny(value.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", instance)
+	}
+}
+
+// Report whether a number has no fractional part, such as 1.0.
+func isInteger(instance any) bool {
+	number, ok := instance.(json.Number)
+	if !ok {
+		return false
+	}
+	value, ok := new(big.Rat).SetString(number.String())
+	return ok && value.IsInt()
+}
+
+// Compare two JSON values. Numbers are compared by value.
+func equal(a any, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		rx, okX := new(big.Rat).SetString(x.String())
+		ry, okY := new(big.Rat).SetString(y.String())
+		return okX && okY && rx.Cmp(ry) == 0
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, exists := y[key]
+			if !exists || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// Render a value as compact JSON for messages.
+func render(value any) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
+
+func intKeyword(node map[string]any, keyword string) (int, bool) {
+	number, ok := node[keyword].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := number.Int64()
+	if err != nil {
+		return 0, false
+	}
+	return int(value), true
+}
+
+// Escape a key for use as a JSON pointer token.
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// Split a JSON pointer into unescaped tokens.
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	var tokens []string
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+	}
+	return tokens
+}
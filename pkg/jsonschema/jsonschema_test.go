@@ -0,0 +1,189 @@
+package jsonschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSchemaFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return filepath.Join(dir, "schema.json")
+}
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		instance string
+		expected []string
+	}{
+		{
+			name:     "True schema allows anything",
+			files:    map[string]string{"schema.json": `true`},
+			instance: `{"a":[1,2]}`,
+		},
+		{
+			name:     "False schema allows nothing",
+			files:    map[string]string{"schema.json": `false`},
+			instance: `null`,
+			expected: []string{"(root): no value is allowed here"},
+		},
+		{
+			name:     "False subschema rejects a property",
+			files:    map[string]string{"schema.json": `{"properties":{"legacy":false}}`},
+			instance: `{"legacy":1,"other":2}`,
+			expected: []string{"/legacy: no value is allowed here"},
+		},
+		{
+			name:     "Local $ref",
+			files:    map[string]string{"schema.json": `{"$defs":{"id":{"type":"integer"}},"properties":{"id":{"$ref":"#/$defs/id"}}}`},
+			instance: `{"id":"a1"}`,
+			expected: []string{"/id: expected integer, found string"},
+		},
+		{
+			name: "File $ref",
+			files: map[string]string{
+				"schema.json":      `{"properties":{"user":{"$ref":"defs/common.json#/$defs/user"}}}`,
+				"defs/common.json": `{"$defs":{"user":{"required":["name"],"properties":{"name":{"type":"string"}}}}}`,
+			},
+			instance: `{"user":{"id":1}}`,
+			expected: []string{`/user: missing required property "name"`},
+		},
+		{
+			name:     "Recursive $ref validates nested levels",
+			files:    map[string]string{"schema.json": `{"$ref":"#/$defs/node","$defs":{"node":{"type":"object","properties":{"value":{"type":"integer"},"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}}}`},
+			instance: `{"value":1,"children":[{"value":2,"children":[{"value":"three"}]}]}`,
+			expected: []string{"/children/0/children/0/value: expected integer, found string"},
+		},
+		{
+			name: "Reference cycle across files",
+			files: map[string]string{
+				"schema.json": `{"$ref":"other.json"}`,
+				"other.json":  `{"$ref":"schema.json"}`,
+			},
+			instance: `1`,
+			expected: []string{"(root): maximum $ref depth exceeded"},
+		},
+		{
+			name:     "Self reference",
+			files:    map[string]string{"schema.json": `{"$ref":"#"}`},
+			instance: `{}`,
+			expected: []string{"(root): maximum $ref depth exceeded"},
+		},
+		{
+			name:     "Required and additional properties",
+			files:    map[string]string{"schema.json": `{"required":["id","name"],"properties":{"id":{},"name":{}},"additionalProperties":false}`},
+			instance: `{"id":1,"extra":true}`,
+			expected: []string{`(root): missing required property "name"`, `/extra: property "extra" is not allowed`},
+		},
+		{
+			name:     "Additional properties schema",
+			files:    map[string]string{"schema.json": `{"properties":{"id":{}},"patternProperties":{"^x-":{"type":"string"}},"additionalProperties":{"type":"number"}}`},
+			instance: `{"id":"a","x-trace":"t","count":"many"}`,
+			expected: []string{"/count: expected number, found string"},
+		},
+		{
+			name:     "Numeric bounds",
+			files:    map[string]string{"schema.json": `{"properties":{"min":{"minimum":1},"max":{"maximum":10},"exMin":{"exclusiveMinimum":0},"exMax":{"exclusiveMaximum":5},"step":{"multipleOf":0.1}}}`},
+			instance: `{"min":0.5,"max":10.5,"exMin":0,"exMax":5,"step":0.3}`,
+			expected: []string{
+				"/exMax: must be < 5, found 5",
+				"/exMin: must be > 0, found 0",
+				"/max: must be <= 10, found 10.5",
+				"/min: must be >= 1, found 0.5",
+			},
+		},
+		{
+			name:     "Numbers within bounds",
+			files:    map[string]string{"schema.json": `{"minimum":1,"maximum":1e3,"multipleOf":0.25}`},
+			instance: `2.75`,
+		},
+		{
+			name:     "Integer type accepts whole decimals",
+			files:    map[string]string{"schema.json": `{"type":"integer"}`},
+			instance: `1.0`,
+		},
+		{
+			name:     "Enum",
+			files:    map[string]string{"schema.json": `{"properties":{"status":{"enum":["active","disabled",null]},"level":{"enum":[1,2]}}}`},
+			instance: `{"status":"deleted","level":2.0}`,
+			expected: []string{`/status: value "deleted" is not one of ["active","disabled",null]`},
+		},
+		{
+			name:     "Const",
+			files:    map[string]string{"schema.json": `{"properties":{"version":{"const":2},"tags":{"const":["a","b"]}}}`},
+			instance: `{"version":3,"tags":["b","a"]}`,
+			expected: []string{`/tags: value ["b","a"] must be ["a","b"]`, "/version: value 3 must be 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Load(writeSchemaFiles(t, tt.files))
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			violations, err := schema.ValidateJSON([]byte(tt.instance))
+			if err != nil {
+				t.Fatalf("Expected no error, received %v", err)
+			}
+			var received []string
+			for _, violation := range violations {
+				received = append(received, violation.String())
+			}
+			if !reflect.DeepEqual(received, tt.expected) {
+				t.Errorf("Expected violations %q, received %q", tt.expected, received)
+			}
+		})
+	}
+}
+
+func TestValidateJSON_SchemaPath(t *testing.T) {
+	schema, err := Load(writeSchemaFiles(t, map[string]string{"schema.json": `{"$defs":{"id":{"minimum":1}},"properties":{"items":{"items":{"$ref":"#/$defs/id"}}}}`}))
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	violations, err := schema.ValidateJSON([]byte(`{"items":[1,0]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, received %v", err)
+	}
+	expected := []Violation{{InstancePath: "/items/1", SchemaPath: "/properties/items/items/$ref/minimum", Message: "must be >= 1, found 0"}}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %+v, received %+v", expected, violations)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected error
+	}{
+		{name: "Missing file", files: map[string]string{}, expected: ErrorFailedToLoadSchema},
+		{name: "Invalid JSON", files: map[string]string{"schema.json": `{"type":`}, expected: ErrorInvalidSchema},
+		{name: "Not an object or boolean", files: map[string]string{"schema.json": `["string"]`}, expected: ErrorInvalidSchema},
+		{name: "Invalid pattern", files: map[string]string{"schema.json": `{"pattern":"("}`}, expected: ErrorInvalidSchema},
+		{name: "Unresolved local $ref", files: map[string]string{"schema.json": `{"$ref":"#/$defs/missing"}`}, expected: ErrorInvalidSchema},
+		{name: "Missing $ref file", files: map[string]string{"schema.json": `{"$ref":"missing.json"}`}, expected: ErrorFailedToLoadSchema},
+		{name: "Remote $ref", files: map[string]string{"schema.json": `{"$ref":"https://example.com/schema.json"}`}, expected: ErrorInvalidSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeSchemaFiles(t, tt.files)); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected error %v, received %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"sort"
 	"strconv"
 
 	"github.com/goccy/go-yaml"
@@ -89,6 +90,16 @@ func EnsureDir(path string) error {
 	return nil
 }
 
+// Return the keys of a map in sorted order.
+func SortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Print the given error to the specified writer, prefixing with "error: ". Panics on write failure.
 func PrintError(w io.Writer, err error) {
 	_, e := fmt.Fprintf(w, "error: %v\n", err)